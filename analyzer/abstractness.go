@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"go/ast"
+	"math"
+)
+
+// CountAbstractTypes counts the package's type declarations, returning how many are
+// interfaces (Go's only abstract types) and the total
+func CountAbstractTypes(pkg *ast.Package) (abstract int, total int) {
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				total++
+				if _, isInterface := typeSpec.Type.(*ast.InterfaceType); isInterface {
+					abstract++
+				}
+			}
+		}
+	}
+
+	return abstract, total
+}
+
+// calculateAbstractness returns the package abstractness A = abstract types / total types (0 without types)
+func calculateAbstractness(abstract int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(abstract) / float64(total)
+}
+
+// distanceFromMainSequence returns D = |A + I - 1|, how far a package is from the ideal
+// balance where stable packages are abstract and unstable packages are concrete
+func distanceFromMainSequence(abstractness float64, instability float64) float64 {
+	return math.Abs(abstractness + instability - 1)
+}
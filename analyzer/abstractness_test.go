@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAbstractnessAndMainSequence(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		// Concrete-heavy and depended upon: A = 0, I = 0
+		"model/model.go": `package model
+
+type User struct{ Name string }
+
+type Order struct{ ID int }
+
+type Item struct{ SKU string }
+`,
+		// Interface-heavy and depending on others: A = 1, I = 1
+		"ports/ports.go": `package ports
+
+import "example.com/app/model"
+
+type UserStore interface{ Get(id int) model.User }
+
+type OrderStore interface{ Put(o model.Order) }
+`,
+		// Balanced: one interface of two types, half unstable
+		"svc/svc.go": `package svc
+
+import "example.com/app/model"
+
+type Notifier interface{ Notify(u model.User) }
+
+type Service struct{ n Notifier }
+`,
+		"cmd/main.go": `package main
+
+import "example.com/app/svc"
+
+func main() { _ = svc.Service{} }
+`,
+	}, nil)
+
+	tests := []struct {
+		path                      string
+		abstractness, instability float64
+		wantDiagnostic            string
+	}{
+		{"model", 0, 0, DiagnosticZoneOfPain},
+		{"ports", 1, 1, DiagnosticZoneOfUselessness},
+		{"svc", 0.5, 0.5, ""},
+	}
+
+	for _, tt := range tests {
+		pkg := findPackage(t, report, tt.path)
+		if math.Abs(pkg.Abstractness-tt.abstractness) > 1e-9 || math.Abs(pkg.Instability-tt.instability) > 1e-9 {
+			t.Errorf("%s: A = %.2f, I = %.2f, want %.2f and %.2f", tt.path, pkg.Abstractness, pkg.Instability, tt.abstractness, tt.instability)
+		}
+		if want := math.Abs(pkg.Abstractness + pkg.Instability - 1); math.Abs(pkg.Distance-want) > 1e-9 {
+			t.Errorf("%s: D = %.2f, want %.2f", tt.path, pkg.Distance, want)
+		}
+
+		var got []string
+		for _, d := range report.Diagnostics {
+			if (d.Type == DiagnosticZoneOfPain || d.Type == DiagnosticZoneOfUselessness) && d.TargetName == pkg.Name {
+				got = append(got, d.Type)
+				if d.Severity != "Warning" {
+					t.Errorf("%s: severity %s at distance %.2f, want Warning", tt.path, d.Severity, pkg.Distance)
+				}
+			}
+		}
+		if tt.wantDiagnostic == "" && len(got) != 0 || tt.wantDiagnostic != "" && (len(got) != 1 || got[0] != tt.wantDiagnostic) {
+			t.Errorf("%s: diagnostics %v, want %q", tt.path, got, tt.wantDiagnostic)
+		}
+	}
+}
+
+func TestCalculateAbstractness(t *testing.T) {
+	if got := calculateAbstractness(0, 0); got != 0 {
+		t.Errorf("abstractness without types = %v, want 0", got)
+	}
+	if got := calculateAbstractness(1, 4); got != 0.25 {
+		t.Errorf("abstractness of 1 of 4 = %v, want 0.25", got)
+	}
+	if got := distanceFromMainSequence(0.25, 0.25); got != 0.5 {
+		t.Errorf("distance = %v, want 0.5", got)
+	}
+}
@@ -5,110 +5,482 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
-// Analyze performs comprehensive code analysis on the provided directory
+// Analyze performs comprehensive code analysis on the provided directory,
+// using the configuration discovered at its root
 func Analyze(targetPath string, excludeDirs []string) (*Report, error) {
+	// Load configuration from the project root (defaults if absent)
+	cfg, err := DiscoverConfig(targetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return AnalyzeWithOptions(targetPath, AnalyzeOptions{ExcludeDirs: excludeDirs, Config: cfg})
+}
+
+// AnalyzeWithConfig performs comprehensive code analysis on the provided directory with the given configuration
+func AnalyzeWithConfig(targetPath string, excludeDirs []string, cfg *Config) (*Report, error) {
+	return AnalyzeWithOptions(targetPath, AnalyzeOptions{ExcludeDirs: excludeDirs, Config: cfg})
+}
+
+// AnalyzeOptions holds the settings of one analysis run. New settings are added as fields,
+// so callers setting only what they need keep compiling as the analyzer grows.
+type AnalyzeOptions struct {
+	// ExcludeDirs lists directories, globs, or "regex:" patterns to skip (vendor and testdata always are)
+	ExcludeDirs []string
+
+	// Config holds the diagnostic thresholds and other analysis settings. Nil uses DefaultConfig,
+	// and unset complexity weights and thresholds are taken from it (see Config.withDefaults).
+	Config *Config
+
+	// IncludeTests, IncludeGenerated, and TypeCheck turn on the Config setting of the same name
+	// without building a Config first. Leaving them false keeps the Config value.
+	IncludeTests     bool
+	IncludeGenerated bool
+	TypeCheck        bool
+
+	// Logger receives progress while the analysis runs: the directories being parsed, the number
+	// of packages found, and the time each phase took. Nil keeps the analysis silent.
+	Logger io.Writer
+
+	// CacheDir, when set, keeps the report of each analysis in that directory, keyed by a
+	// fingerprint of the target's Go files, go.mod and go.work files, ignore and architecture
+	// files, and the configuration (see cacheKey). An analysis whose inputs match a cached one
+	// returns the cached report without parsing. Churn and SLA ages are those of the cached run.
+	CacheDir string
+}
+
+// AnalyzeWithOptions performs comprehensive code analysis on the provided directory with the given options
+func AnalyzeWithOptions(targetPath string, opts AnalyzeOptions) (*Report, error) {
 	// Normalize the target path
 	absPath, err := filepath.Abs(targetPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve path: %w", err)
 	}
 
-	// Determine project module path (for coupling calculation)
-	projectPrefix := determineProjectPrefix(absPath)
+	cfg := opts.config()
+	progress := newProgressLogger(opts.Logger)
+
+	// Return the cached report when nothing the analysis depends on has changed
+	var cacheKeyHex string
+	if opts.CacheDir != "" {
+		cacheKeyHex, err = cacheKey(absPath, opts.ExcludeDirs, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute cache key: %w", err)
+		}
+		if cached := loadCachedReport(opts.CacheDir, cacheKeyHex); cached != nil {
+			progress.logf("Using cached analysis %s", cachePath(opts.CacheDir, cacheKeyHex))
+			return cached, nil
+		}
+	}
+
+	// Determine the project's modules (several with a go.work workspace) for coupling calculation
+	modules := determineModuleRoots(absPath)
+
+	// Imports of project modules, of modules they replace with local directories, and under the
+	// configured prefixes are internal
+	internalPrefixes := modules.internalPrefixes(absPath)
+	for _, prefix := range cfg.InternalPrefixes {
+		internalPrefixes = append(internalPrefixes, strings.TrimSuffix(prefix, "/"))
+	}
 
 	// Parse all Go packages in the directory
-	packages, err := parsePackages(absPath, excludeDirs)
+	start := time.Now()
+	packages, err := parsePackages(absPath, opts.ExcludeDirs, cfg.IncludeTests, cfg.IncludeGenerated, runtime.NumCPU(), progress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse packages: %w", err)
 	}
+	progress.logf("Found %d packages", len(packages))
+	progress.phase("Parsing", time.Since(start))
 
 	// Build package dependency graph
-	pkgDeps := buildDependencyGraph(packages, projectPrefix)
+	start = time.Now()
+	pkgDeps := buildDependencyGraph(packages, modules)
 
-	// Calculate coupling metrics
-	couplingMetrics := CalculateCoupling(pkgDeps, projectPrefix)
+	// Calculate coupling metrics, plain and weighted by how much of each import is used
+	couplingMetrics := CalculateCoupling(pkgDeps, internalPrefixes)
+	weightedInstability := CalculateWeightedInstability(pkgDeps, internalPrefixes)
 
 	// Calculate dependency depth
-	depthMetrics := CalculateDependencyDepth(pkgDeps, projectPrefix)
+	depthMetrics := CalculateDependencyDepth(pkgDeps, internalPrefixes)
+
+	// Find import cycles and index them by member package
+	importCycles := make(map[string]*ImportCycle)
+	for _, cycle := range DetectCycles(pkgDeps, modules) {
+		importCycle := &ImportCycle{
+			Packages: cycle,
+			Ring:     importRing(cycle, pkgDeps, modules),
+		}
+		for _, member := range cycle {
+			importCycles[member] = importCycle
+		}
+	}
+
+	couplingTime := time.Since(start)
 
 	// Generate report for each package
 	var packageResults []PackageResult
 	totalProjectLoC := 0
+	var lcomTime, complexityTime time.Duration
 
 	for pkgPath, pkg := range packages {
 		// Calculate LCOM4 for all structs
-		structs := CalculateLCOM4(pkg.Package, pkg.FileSet)
+		start = time.Now()
+		structs := CalculateLCOM4(pkg.Package, pkg.FileSet, cfg)
+		sortStructResults(structs)
+		lcomTime += time.Since(start)
 
 		// Calculate cyclomatic complexity and LoC for all functions
-		functions := CalculateComplexity(pkg.Package, pkg.FileSet, projectPrefix)
+		start = time.Now()
+		functions := CalculateComplexity(pkg.Package, pkg.FileSet, internalPrefixes, cfg)
+		sortFunctionResults(functions)
+		complexityTime += time.Since(start)
+
+		// Sum method complexity per struct (WMC)
+		applyWeightedMethods(structs, functions)
+
+		// Count methods and the distinct calls they make per struct (RFC)
+		applyResponseForClass(structs, pkg.Package)
+
+		// Find methods more interested in another struct's fields than their own
+		applyFeatureEnvy(structs, pkg.Package, pkg.FileSet)
+
+		// Mark functions exercised (directly or transitively) by test files
+		MarkTestedFunctions(functions, pkg.Package, pkg.TestFiles)
+
+		// Mark unexported functions nothing in the package refers to
+		MarkUnreferencedFunctions(functions, pkg.Package, pkg.referenceFiles())
 
 		// Calculate LoC for the package
 		pkgLoC := CalculateLoCForPackage(pkg.Package, pkg.FileSet)
-		totalProjectLoC += pkgLoC.TotalLoC
+		totalProjectLoC += pkgLoC.count(cfg.LoCMode)
+
+		// Detect names declared more than once across the package's files
+		duplicates := FindDuplicateDeclarations(pkg.Package, pkg.FileSet)
+
+		// Find exported declarations without a doc comment
+		undocumented := FindUndocumentedExports(pkg.Package, pkg.FileSet)
+
+		// Extract interface declarations
+		interfaces := ExtractInterfaces(pkg.Package, pkg.FileSet)
+
+		// Tally error construction styles
+		errorStyles := CountErrorStyles(pkg.Package)
+
+		// Count exported top-level declarations
+		exportedDecls, totalDecls := CountExportedDeclarations(pkg.Package)
+		exportedRatio := 0.0
+		if totalDecls > 0 {
+			exportedRatio = float64(exportedDecls) / float64(totalDecls)
+		}
+
+		// Count interfaces versus all type declarations
+		abstractTypes, totalTypes := CountAbstractTypes(pkg.Package)
+		abstractness := calculateAbstractness(abstractTypes, totalTypes)
 
 		// Calculate derived metrics
 		funcCount := len(functions)
 		avgFuncLoC := 0.0
+		avgComplexity := 0.0
+		totalComplexity, maxComplexity := 0, 0
 		if funcCount > 0 {
 			totalFuncLoC := 0
 			for _, f := range functions {
-				totalFuncLoC += f.LoC
+				totalFuncLoC += functionLoC(f, cfg.LoCMode)
+				totalComplexity += f.Complexity
+				if f.Complexity > maxComplexity {
+					maxComplexity = f.Complexity
+				}
 			}
 			avgFuncLoC = float64(totalFuncLoC) / float64(funcCount)
+			avgComplexity = float64(totalComplexity) / float64(funcCount)
 		}
 
+		// Collect per-file metrics and flag files with an ignore-file directive
+		files := buildFileResults(pkgLoC, functions, structs)
+		markIgnoredFiles(files, pkg.Package)
+
 		// Get coupling metrics
 		coupling := couplingMetrics[pkgPath]
 
 		// Get dependency depth
 		depth := depthMetrics[pkgPath]
 
+		// Resolve imports of other project packages to relative paths
+		internalImports := resolveInternalImports(pkgDeps, pkgDeps[pkgPath].Imports, modules)
+
 		packageResults = append(packageResults, PackageResult{
-			Name:            pkg.Package.Name,
-			Path:            pkgPath,
-			Afferent:        coupling.Afferent,
-			Efferent:        coupling.Efferent,
-			Instability:     coupling.Instability,
-			Structs:         structs,
-			Functions:       functions,
-			TotalLoC:        pkgLoC.TotalLoC,
-			AvgFuncLoC:      avgFuncLoC,
-			FuncCount:       funcCount,
-			FileCount:       pkgLoC.FileCount,
-			DependencyDepth: depth,
+			Name:                  pkg.Package.Name,
+			Path:                  pkgPath,
+			Afferent:              coupling.Afferent,
+			Efferent:              coupling.Efferent,
+			Instability:           coupling.Instability,
+			WeightedInstability:   weightedInstability[pkgPath],
+			Structs:               structs,
+			Functions:             functions,
+			TotalLoC:              pkgLoC.count(cfg.LoCMode),
+			SourceLoC:             pkgLoC.SourceLoC,
+			CommentDensity:        lineRatio(pkgLoC.CommentLines, pkgLoC.SourceLoC),
+			DocCommentDensity:     lineRatio(pkgLoC.DocCommentLines, pkgLoC.SourceLoC),
+			MaintainabilityIndex:  averageMaintainabilityIndex(functions),
+			AvgFuncLoC:            avgFuncLoC,
+			AvgComplexity:         avgComplexity,
+			MaxComplexity:         maxComplexity,
+			TotalComplexity:       totalComplexity,
+			FuncCount:             funcCount,
+			FileCount:             pkgLoC.FileCount,
+			Files:                 files,
+			DependencyDepth:       depth,
+			DuplicateDeclarations: duplicates,
+			UndocumentedExports:   undocumented,
+			Interfaces:            interfaces,
+			InternalImports:       internalImports,
+			ErrorStyles:           errorStyles,
+			ExportedDecls:         exportedDecls,
+			TotalDecls:            totalDecls,
+			ExportedRatio:         exportedRatio,
+			AbstractTypes:         abstractTypes,
+			TotalTypes:            totalTypes,
+			Abstractness:          abstractness,
+			Distance:              distanceFromMainSequence(abstractness, coupling.Instability),
+			ImportCycle:           importCycles[pkgPath],
 		})
 	}
 
+	progress.phase("LCOM4", lcomTime)
+	progress.phase("Complexity", complexityTime)
+
+	// Packages are parsed into a map; fix their order so reports are reproducible
+	sort.Slice(packageResults, func(i, j int) bool {
+		return packageResults[i].Path < packageResults[j].Path
+	})
+
+	// Count calls to functions from other project packages
+	start = time.Now()
+	applyCrossPackageAfferentCoupling(packageResults, packages, modules)
+
+	// Resolve call targets with type information (falls back to the AST results per package)
+	if cfg.TypeCheck {
+		applyTypedAfferentCoupling(packageResults, packages, typeCheckPackages(packages, modules), modules)
+	}
+	progress.phase("Coupling", couplingTime+time.Since(start))
+
+	// Mark functions tested from other packages' test files, or called by tested functions there
+	markCrossPackageTestReferences(packageResults, packages, modules)
+
+	// Find interface methods that no code in the project ever calls
+	markUnusedInterfaceMethods(packageResults, collectSelectorNames(packages))
+
+	// Match project types to the interfaces whose methods they implement
+	markInterfaceImplementers(packageResults, packages, modules)
+
+	// Mark struct types nothing in the project refers to
+	markUnreferencedStructs(packageResults, packages, modules)
+
+	// Count recent commits per file when churn is enabled
+	var hotspots []Hotspot
+	if cfg.ChurnDays > 0 {
+		start = time.Now()
+		churn, err := collectChurn(absPath, cfg.ChurnDays, time.Now())
+		if err != nil {
+			progress.logf("Skipping churn: %v", err)
+		} else {
+			applyChurn(packageResults, churn)
+			hotspots = rankHotspots(packageResults, cfg.TopOffenders)
+		}
+		progress.phase("Churn", time.Since(start))
+	}
+
 	// Perform integrated diagnostics
-	diagnostics := PerformDiagnostics(packageResults)
+	start = time.Now()
+	diagnostics := PerformDiagnostics(packageResults, cfg)
+
+	// Annotate diagnostics with their age and SLA status
+	annotateSLA(diagnostics, cfg.SeveritySLADays, time.Now())
+	progress.logf("Found %d diagnostics", len(diagnostics))
+	progress.phase("Diagnostics", time.Since(start))
+
+	report := &Report{
+		SchemaVersion:   SchemaVersion,
+		GeneratedAt:     time.Now().UTC(),
+		AnalyzerVersion: analyzerVersion(),
+		TargetPath:      absPath,
+		Diagnostics:     diagnostics,
+		Packages:        packageResults,
+		TotalLoC:        totalProjectLoC,
+		LoCMode:         locMode(cfg.LoCMode),
+		HealthScore:     CalculateHealthScore(packageResults, diagnostics),
+		TopOffenders:    rankOffenders(packageResults, diagnostics, cfg, cfg.TopOffenders),
+		Hotspots:        hotspots,
+	}
+
+	if opts.CacheDir != "" {
+		if err := storeCachedReport(opts.CacheDir, cacheKeyHex, report); err != nil {
+			return nil, err
+		}
+		progress.logf("Cached analysis in %s", cachePath(opts.CacheDir, cacheKeyHex))
+	}
 
-	return &Report{
-		Diagnostics: diagnostics,
-		Packages:    packageResults,
-		TotalLoC:    totalProjectLoC,
-	}, nil
+	return report, nil
+}
+
+// sortStructResults orders structs by name, then file and line. Files are parsed into a map,
+// so without sorting the order (and sums over it) would change from run to run.
+func sortStructResults(structs []StructResult) {
+	sort.Slice(structs, func(i, j int) bool {
+		if structs[i].StructName != structs[j].StructName {
+			return structs[i].StructName < structs[j].StructName
+		}
+		if structs[i].FilePath != structs[j].FilePath {
+			return structs[i].FilePath < structs[j].FilePath
+		}
+		return structs[i].Line < structs[j].Line
+	})
+}
+
+// sortFunctionResults orders functions by name, then file and line (init functions can repeat
+// across files)
+func sortFunctionResults(functions []FunctionResult) {
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].FuncName != functions[j].FuncName {
+			return functions[i].FuncName < functions[j].FuncName
+		}
+		if functions[i].FilePath != functions[j].FilePath {
+			return functions[i].FilePath < functions[j].FilePath
+		}
+		return functions[i].Line < functions[j].Line
+	})
+}
+
+// config returns the effective configuration: Config (or the defaults) with unset settings filled
+// from the defaults and the option switches applied. The caller's Config is copied, not modified.
+func (opts AnalyzeOptions) config() *Config {
+	cfg := opts.Config.withDefaults()
+	cfg.IncludeTests = cfg.IncludeTests || opts.IncludeTests
+	cfg.IncludeGenerated = cfg.IncludeGenerated || opts.IncludeGenerated
+	cfg.TypeCheck = cfg.TypeCheck || opts.TypeCheck
+	return cfg
 }
 
 // ParsedPackage holds a parsed package and its file set
 type ParsedPackage struct {
-	Package *ast.Package
-	FileSet *token.FileSet
+	Package   *ast.Package
+	FileSet   *token.FileSet
+	TestFiles map[string]*ast.File // _test.go files in the same directory (not measured)
+
+	// GeneratedFiles are files marked "// Code generated ... DO NOT EDIT." (not measured unless
+	// generated files are included). They still count as references and are type-checked.
+	GeneratedFiles map[string]*ast.File
+}
+
+// referenceFiles returns the unmeasured files whose references to the package's declarations count:
+// test files and generated files
+func (p *ParsedPackage) referenceFiles() map[string]*ast.File {
+	if len(p.GeneratedFiles) == 0 {
+		return p.TestFiles
+	}
+
+	files := make(map[string]*ast.File, len(p.TestFiles)+len(p.GeneratedFiles))
+	for fileName, file := range p.TestFiles {
+		files[fileName] = file
+	}
+	for fileName, file := range p.GeneratedFiles {
+		files[fileName] = file
+	}
+	return files
 }
 
-// parsePackages parses all Go packages in the given directory
-func parsePackages(rootPath string, excludeDirs []string) (map[string]*ParsedPackage, error) {
+// parsePackages parses all Go packages in the given directory.
+// With includeTests, _test.go files are measured too: in-package test files join their package,
+// and an external test package (package foo_test) is keyed by its directory path plus "_test".
+// Any further package in a directory is keyed by the directory path, ":", and its package name
+// ("tools:main"), so it is analyzed instead of replacing the directory's own package.
+// Unless includeGenerated is set, generated files are set aside and packages made only of
+// generated files are skipped. Directories are parsed by up to workers goroutines, and each is
+// reported to the progress logger as it is parsed.
+func parsePackages(rootPath string, excludeDirs []string, includeTests bool, includeGenerated bool, workers int, progress *progressLogger) (map[string]*ParsedPackage, error) {
 	packages := make(map[string]*ParsedPackage)
 
-	// Default exclude patterns
-	defaultExcludes := []string{"vendor", "testdata"}
-	allExcludes := append(defaultExcludes, excludeDirs...)
+	dirs, err := collectSourceDirs(rootPath, excludeDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse directories concurrently. Each directory has its own FileSet and package path,
+	// so workers only share the result map.
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	work := make(chan string)
+
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(dirs) {
+		workers = len(dirs)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range work {
+				// Generate package path relative to root
+				relPath, _ := filepath.Rel(rootPath, path)
+				pkgPath := filepath.ToSlash(relPath)
+				if pkgPath == "." {
+					pkgPath = ""
+				}
+
+				progress.logf("Parsing %s", relPath)
+				parsed, external, others := parseDirectory(path, includeTests, includeGenerated)
+				if parsed == nil && external == nil {
+					continue
+				}
+
+				mu.Lock()
+				if parsed != nil {
+					packages[pkgPath] = parsed
+				}
+				if external != nil {
+					packages[pkgPath+"_test"] = external
+				}
+				for _, other := range others {
+					packages[pkgPath+":"+other.Package.Name] = other
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, path := range dirs {
+		work <- path
+	}
+	close(work)
+	wg.Wait()
+
+	return packages, nil
+}
 
-	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+// collectSourceDirs returns the directories under rootPath that may hold packages to analyze:
+// every directory except hidden ones, vendor and testdata, and those matching excludeDirs
+func collectSourceDirs(rootPath string, excludeDirs []string) ([]string, error) {
+	// vendor and testdata are always excluded
+	excludes, err := newExcludeMatcher(append([]string{"vendor", "testdata"}, excludeDirs...))
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the directories to parse; the walk itself is cheap
+	var dirs []string
+	err = filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -133,74 +505,151 @@ func parsePackages(rootPath string, excludeDirs []string) (map[string]*ParsedPac
 		// Normalize to use forward slashes for consistent matching
 		relPath = filepath.ToSlash(relPath)
 
-		// Skip excluded directories (check both basename and relative path)
-		for _, exclude := range allExcludes {
-			// Normalize exclude pattern to forward slashes
-			normalizedExclude := filepath.ToSlash(exclude)
-
-			// Match by basename (e.g., "vendor") or by relative path (e.g., "hoge/fuga")
-			if baseName == normalizedExclude || relPath == normalizedExclude {
-				return filepath.SkipDir
-			}
+		// Skip excluded directories (the root itself is never excluded)
+		if relPath != "." && excludes.matches(relPath) {
+			return filepath.SkipDir
 		}
 
-		// Try to parse Go files in this directory
-		fset := token.NewFileSet()
-		pkgs, err := parser.ParseDir(fset, path, func(fi os.FileInfo) bool {
-			// Skip test files
-			return !strings.HasSuffix(fi.Name(), "_test.go")
-		}, parser.ParseComments)
+		dirs = append(dirs, path)
+		return nil
+	})
 
-		if err != nil {
-			// Skip directories with parse errors
-			return nil
-		}
+	if err != nil {
+		return nil, err
+	}
+	return dirs, nil
+}
+
+// parseDirectory parses the Go package in a directory, returning nil if it has no
+// parsable Go files. Test files are parsed separately so they can be cross-referenced
+// without being measured. With includeTests, in-package test files are measured as part of
+// the package, and an external test package is returned separately. If the directory declares
+// several other packages, parsed is the one named after the directory (else the one with the most
+// files) and the rest are returned as others. Generated files are moved to GeneratedFiles unless
+// includeGenerated is set.
+func parseDirectory(path string, includeTests bool, includeGenerated bool) (parsed *ParsedPackage, external *ParsedPackage, others []*ParsedPackage) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, path, func(fi os.FileInfo) bool {
+		// Skip test files unless they are measured
+		return includeTests || !isTestFile(fi.Name())
+	}, parser.ParseComments)
 
-		// Store each package found
+	if err != nil {
+		// Skip directories with parse errors
+		return nil, nil, nil
+	}
+
+	testFiles := make(map[string]*ast.File)
+	if includeTests {
 		for _, pkg := range pkgs {
-			// Generate package path relative to root
-			relPath, _ := filepath.Rel(rootPath, path)
-			pkgPath := filepath.ToSlash(relPath)
-			if pkgPath == "." {
-				pkgPath = ""
+			for fileName, file := range pkg.Files {
+				if isTestFile(fileName) {
+					testFiles[fileName] = file
+				}
+			}
+		}
+	} else {
+		testPkgs, err := parser.ParseDir(fset, path, func(fi os.FileInfo) bool {
+			return isTestFile(fi.Name())
+		}, 0)
+		if err == nil {
+			for _, testPkg := range testPkgs {
+				for fileName, file := range testPkg.Files {
+					testFiles[fileName] = file
+				}
 			}
+		}
+	}
 
-			packages[pkgPath] = &ParsedPackage{
+	// A directory normally holds one package, but files excluded by build constraints
+	// (e.g. a "//go:build ignore" generator in package main) may declare another
+	var candidates []*ParsedPackage
+	for name, pkg := range pkgs {
+		var generatedFiles map[string]*ast.File
+		if !includeGenerated {
+			generatedFiles = separateGeneratedFiles(pkg)
+			if len(pkg.Files) == 0 {
+				continue
+			}
+		}
+
+		if includeTests && strings.HasSuffix(name, "_test") && isTestPackage(pkg) {
+			external = &ParsedPackage{
 				Package: pkg,
 				FileSet: fset,
 			}
+			continue
 		}
+		candidates = append(candidates, &ParsedPackage{
+			Package:        pkg,
+			FileSet:        fset,
+			GeneratedFiles: generatedFiles,
+		})
+	}
+	if len(candidates) == 0 {
+		return nil, external, nil
+	}
 
-		return nil
+	// The directory's own package comes first: the one named after the directory, else the largest
+	dirName := filepath.Base(path)
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i].Package, candidates[j].Package
+		if (a.Name == dirName) != (b.Name == dirName) {
+			return a.Name == dirName
+		}
+		if len(a.Files) != len(b.Files) {
+			return len(a.Files) > len(b.Files)
+		}
+		return a.Name < b.Name
 	})
 
-	if err != nil {
-		return nil, err
+	parsed = candidates[0]
+	parsed.TestFiles = testFiles
+	return parsed, external, candidates[1:]
+}
+
+// separateGeneratedFiles removes files carrying the "// Code generated ... DO NOT EDIT." marker
+// (detected from the parsed comments, not the file name) from the package and returns them
+func separateGeneratedFiles(pkg *ast.Package) map[string]*ast.File {
+	generated := make(map[string]*ast.File)
+	for fileName, file := range pkg.Files {
+		if ast.IsGenerated(file) {
+			generated[fileName] = file
+			delete(pkg.Files, fileName)
+		}
 	}
+	return generated
+}
 
-	return packages, nil
+// isTestFile reports whether a file name is a Go test file
+func isTestFile(fileName string) bool {
+	return strings.HasSuffix(fileName, "_test.go")
+}
+
+// isTestPackage reports whether every file of a package is a test file (an external test package)
+func isTestPackage(pkg *ast.Package) bool {
+	for fileName := range pkg.Files {
+		if !isTestFile(fileName) {
+			return false
+		}
+	}
+	return true
 }
 
 // buildDependencyGraph builds a dependency graph for all packages
-func buildDependencyGraph(packages map[string]*ParsedPackage, projectPrefix string) map[string]*PackageDependency {
+func buildDependencyGraph(packages map[string]*ParsedPackage, modules projectModules) map[string]*PackageDependency {
 	deps := make(map[string]*PackageDependency)
 
 	// Create mapping from full import path to relative path
 	fullToRelPath := make(map[string]string)
 	for pkgPath := range packages {
-		fullPath := projectPrefix
-		if pkgPath != "" {
-			fullPath = projectPrefix + "/" + pkgPath
-		}
+		fullPath := modules.importPath(pkgPath)
 		fullToRelPath[fullPath] = pkgPath
 	}
 
 	// Initialize dependency info for each package (using relative path as key)
 	for pkgPath := range packages {
-		fullPath := projectPrefix
-		if pkgPath != "" {
-			fullPath = projectPrefix + "/" + pkgPath
-		}
+		fullPath := modules.importPath(pkgPath)
 		deps[pkgPath] = &PackageDependency{
 			PkgPath:    fullPath,
 			Imports:    []string{},
@@ -210,13 +659,11 @@ func buildDependencyGraph(packages map[string]*ParsedPackage, projectPrefix stri
 
 	// Extract imports for each package
 	for pkgPath, pkg := range packages {
-		fullPath := projectPrefix
-		if pkgPath != "" {
-			fullPath = projectPrefix + "/" + pkgPath
-		}
+		fullPath := modules.importPath(pkgPath)
 
 		imports := ExtractImports(pkg.Package)
 		deps[pkgPath].Imports = imports
+		deps[pkgPath].ImportWeights = countImportUsage(pkg.Package)
 
 		// Update ImportedBy for imported packages
 		for _, imp := range imports {
@@ -230,22 +677,19 @@ func buildDependencyGraph(packages map[string]*ParsedPackage, projectPrefix stri
 	return deps
 }
 
-// determineProjectPrefix tries to determine the project's module path
-func determineProjectPrefix(rootPath string) string {
-	// Try to read go.mod file
-	goModPath := filepath.Join(rootPath, "go.mod")
-	data, err := os.ReadFile(goModPath)
-	if err == nil {
-		// Parse module line
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "module ") {
-				return strings.TrimSpace(strings.TrimPrefix(line, "module"))
-			}
+// resolveInternalImports converts import paths of analyzed project packages to relative package paths
+func resolveInternalImports(pkgDeps map[string]*PackageDependency, imports []string, modules projectModules) []string {
+	var internal []string
+	for _, imp := range imports {
+		relPath, ok := modules.packagePath(imp)
+		if !ok {
+			continue
 		}
-	}
 
-	// Fallback: use directory name
-	return filepath.Base(rootPath)
+		if _, exists := pkgDeps[relPath]; exists {
+			internal = append(internal, relPath)
+		}
+	}
+	sort.Strings(internal)
+	return internal
 }
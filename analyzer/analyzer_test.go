@@ -0,0 +1,391 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// mixedTestFixture has production code, an in-package test file, and an external test package
+var mixedTestFixture = map[string]string{
+	"calc/calc.go": `package calc
+
+type Calc struct{ total int }
+
+func (c *Calc) Add(n int) { c.total += n }
+`,
+	"calc/calc_test.go": `package calc
+
+import "testing"
+
+type fakeClock struct{ now int }
+
+func TestAdd(t *testing.T) {
+	c := &Calc{}
+	c.Add(1)
+}
+`,
+	"calc/example_test.go": `package calc_test
+
+import (
+	"testing"
+
+	"example.com/app/calc"
+)
+
+func TestExternal(t *testing.T) {
+	c := &calc.Calc{}
+	c.Add(2)
+}
+`,
+}
+
+func TestIncludeTests(t *testing.T) {
+	without := analyzeFixture(t, mixedTestFixture, nil)
+	if len(without.Packages) != 1 {
+		t.Fatalf("got %d packages without tests, want 1", len(without.Packages))
+	}
+	pkg := findPackage(t, without, "calc")
+	if len(pkg.Functions) != 1 || len(pkg.Structs) != 1 {
+		t.Errorf("without tests: %d functions and %d structs, want 1 and 1", len(pkg.Functions), len(pkg.Structs))
+	}
+
+	cfg := DefaultConfig()
+	cfg.IncludeTests = true
+	with := analyzeFixture(t, mixedTestFixture, cfg)
+
+	pkg = findPackage(t, with, "calc")
+	if len(pkg.Functions) != 2 || len(pkg.Structs) != 2 {
+		t.Errorf("with tests: %d functions and %d structs, want 2 and 2", len(pkg.Functions), len(pkg.Structs))
+	}
+	if findFunction(t, pkg, "Calc.Add").IsTest || !findFunction(t, pkg, "TestAdd").IsTest {
+		t.Errorf("IsTest should mark only the function declared in calc_test.go")
+	}
+	if findStruct(t, pkg, "Calc").IsTest || !findStruct(t, pkg, "fakeClock").IsTest {
+		t.Errorf("IsTest should mark only the struct declared in calc_test.go")
+	}
+
+	// The external test package is kept apart from the production package
+	external := findPackage(t, with, "calc_test")
+	if external.Name != "calc_test" {
+		t.Errorf("external test package name = %q, want calc_test", external.Name)
+	}
+	if !findFunction(t, external, "TestExternal").IsTest {
+		t.Errorf("TestExternal should be marked IsTest")
+	}
+}
+
+// generatedFixture marks files as generated by their leading comment, not their name
+var generatedFixture = map[string]string{
+	"svc/svc.go": "package svc\n\nfunc Handwritten() {}\n",
+	"svc/mock.go": `// Code generated by mockgen. DO NOT EDIT.
+
+package svc
+
+type MockStore struct{}
+
+func (m *MockStore) Get() {}
+`,
+	"svc/looks_generated.pb.go": "package svc\n\nfunc NotGenerated() {}\n",
+	"gen/gen.go":                "// Code generated by stringer; DO NOT EDIT.\n\npackage gen\n\nfunc String() string { return \"\" }\n",
+}
+
+func TestGeneratedFilesSkippedByDefault(t *testing.T) {
+	report := analyzeFixture(t, generatedFixture, nil)
+
+	pkg := findPackage(t, report, "svc")
+	names := make(map[string]bool)
+	for _, f := range pkg.Functions {
+		names[f.FuncName] = true
+	}
+	if !names["Handwritten"] || !names["NotGenerated"] || names["MockStore.Get"] || len(pkg.Structs) != 0 {
+		t.Errorf("functions %v and %d structs, want Handwritten and NotGenerated only", names, len(pkg.Structs))
+	}
+	for _, p := range report.Packages {
+		if p.Path == "gen" {
+			t.Errorf("package made only of generated files was reported")
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.IncludeGenerated = true
+	with := analyzeFixture(t, generatedFixture, cfg)
+	findFunction(t, findPackage(t, with, "svc"), "MockStore.Get")
+	findStruct(t, findPackage(t, with, "svc"), "MockStore")
+	findFunction(t, findPackage(t, with, "gen"), "String")
+}
+
+func TestDeclarationLines(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"geo/geo.go": `package geo
+
+// Point is a location
+type Point struct {
+	X, Y int
+}
+
+// Move shifts the point
+func (p *Point) Move(dx, dy int) {
+	p.X += dx
+	p.Y += dy
+}
+
+func Origin() Point { return Point{} }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "geo")
+	point := findStruct(t, pkg, "Point")
+	if point.Line != 4 || point.EndLine != 6 {
+		t.Errorf("Point lines %d-%d, want 4-6", point.Line, point.EndLine)
+	}
+	for name, want := range map[string][2]int{"Point.Move": {9, 12}, "Origin": {14, 14}} {
+		f := findFunction(t, pkg, name)
+		if f.Line != want[0] || f.EndLine != want[1] {
+			t.Errorf("%s lines %d-%d, want %d-%d", name, f.Line, f.EndLine, want[0], want[1])
+		}
+	}
+}
+
+func TestAnalyzeWithOptions(t *testing.T) {
+	dir := writeFixture(t, mergeFiles(mixedTestFixture, generatedFixture))
+
+	// The option flags turn settings on over a Config that leaves them off, without changing it
+	cfg := DefaultConfig()
+	report, err := AnalyzeWithOptions(dir, AnalyzeOptions{
+		ExcludeDirs:      []string{"gen"},
+		Config:           cfg,
+		IncludeTests:     true,
+		IncludeGenerated: true,
+	})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if cfg.IncludeTests || cfg.IncludeGenerated {
+		t.Errorf("options modified the caller's Config")
+	}
+	findFunction(t, findPackage(t, report, "calc"), "TestAdd")
+	findFunction(t, findPackage(t, report, "svc"), "MockStore.Get")
+	for _, p := range report.Packages {
+		if p.Path == "gen" {
+			t.Errorf("excluded package gen was analyzed")
+		}
+	}
+
+	// The legacy wrapper keeps the defaults and still honors the excludes
+	legacy, err := Analyze(dir, []string{"gen"})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if len(legacy.Packages) != 2 {
+		t.Errorf("legacy Analyze found %d packages, want calc and svc", len(legacy.Packages))
+	}
+	if got := len(findPackage(t, legacy, "calc").Functions); got != 1 {
+		t.Errorf("legacy Analyze found %d calc functions, want 1 (tests excluded)", got)
+	}
+	if got := len(findPackage(t, legacy, "svc").Structs); got != 0 {
+		t.Errorf("legacy Analyze found %d svc structs, want 0 (generated files excluded)", got)
+	}
+}
+
+func TestMultiplePackagesInDirectory(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.IncludeTests = true
+	report := analyzeFixture(t, map[string]string{
+		"foo/foo.go": "package foo\n\nfunc Foo() int { return 1 }\n",
+		"foo/foo_test.go": `package foo_test
+
+import (
+	"testing"
+
+	"example.com/app/foo"
+)
+
+func TestFoo(t *testing.T) {
+	if foo.Foo() != 1 {
+		t.Fail()
+	}
+}
+`,
+		// A generator excluded from the build declares a third package in the directory
+		"foo/gen.go": "//go:build ignore\n\npackage main\n\nfunc main() {}\n",
+	}, cfg)
+
+	foo := findPackage(t, report, "foo")
+	if foo.Name != "foo" {
+		t.Errorf("package foo name = %q", foo.Name)
+	}
+	findFunction(t, foo, "Foo")
+
+	external := findPackage(t, report, "foo_test")
+	if external.Name != "foo_test" {
+		t.Errorf("package foo_test name = %q", external.Name)
+	}
+	findFunction(t, external, "TestFoo")
+
+	generator := findPackage(t, report, "foo:main")
+	findFunction(t, generator, "main")
+
+	if len(report.Packages) != 3 {
+		t.Errorf("got %d packages, want foo, foo_test, and foo:main", len(report.Packages))
+	}
+}
+
+func TestAnalysisIsDeterministic(t *testing.T) {
+	dir := writeFixture(t, mergeFiles(mixedTestFixture, generatedFixture, map[string]string{
+		"a/a.go": "package a\n\nimport \"example.com/app/b\"\n\nfunc A(name string, age int, email string) int { return b.B(name, age, email) }\n",
+		"b/b.go": "package b\n\nimport \"example.com/app/a\"\n\nfunc B(name string, age int, email string) int { return len(name) + age + len(email) }\n\nfunc C(name string, age int, email string) int { return a.A(name, age, email) }\n",
+		"shapes/circle.go": `package shapes
+
+type Shape interface{ Area() float64 }
+
+type Circle struct{ R float64 }
+
+func (c Circle) Area() float64 { return 3 * c.R * c.R }
+`,
+		"shapes/square.go": `package shapes
+
+type Square struct {
+	S     float64
+	label string
+}
+
+func (s *Square) Area() float64 { return s.S * s.S }
+
+func (s *Square) Label() string { return s.label }
+`,
+	}))
+
+	cfg := DefaultConfig()
+	cfg.IncludeTests = true
+	cfg.Experimental = true
+	encode := func() []byte {
+		report, err := AnalyzeWithConfig(dir, nil, cfg)
+		if err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+		// The timestamp is the only field expected to change between runs
+		report.GeneratedAt = time.Time{}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	first := encode()
+	for i := 0; i < 5; i++ {
+		if again := encode(); !bytes.Equal(again, first) {
+			t.Fatalf("run %d produced different JSON than the first run", i+2)
+		}
+	}
+}
+
+// syntheticTree returns a fixture of n packages, each importing the previous one and holding a
+// struct, its methods, and a test file
+func syntheticTree(n int) map[string]string {
+	files := make(map[string]string, 2*n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("pkg%03d", i)
+		src := "package " + name + "\n\n"
+		call := "0"
+		if i > 0 {
+			prev := fmt.Sprintf("pkg%03d", i-1)
+			src += "import \"example.com/app/" + prev + "\"\n\n"
+			call = prev + ".Sum(n)"
+		}
+		src += `type Counter struct {
+	total int
+	hits  int
+}
+
+func (c *Counter) Add(n int) {
+	if n > 0 {
+		c.total += n
+	}
+	c.hits++
+}
+
+func (c *Counter) Reset() { c.total, c.hits = 0, 0 }
+
+func Sum(n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += i
+	}
+	return total + ` + call + `
+}
+`
+		files[name+"/"+name+".go"] = src
+		files[name+"/"+name+"_test.go"] = "package " + name + "\n\nimport \"testing\"\n\nfunc TestSum(t *testing.T) { Sum(3) }\n"
+	}
+	return files
+}
+
+func TestParsePackagesConcurrentMatchesSerial(t *testing.T) {
+	dir := writeFixture(t, syntheticTree(20))
+
+	serial, err := parsePackages(dir, nil, true, false, 1, newProgressLogger(nil))
+	if err != nil {
+		t.Fatalf("serial parse failed: %v", err)
+	}
+	concurrent, err := parsePackages(dir, nil, true, false, runtime.NumCPU(), newProgressLogger(nil))
+	if err != nil {
+		t.Fatalf("concurrent parse failed: %v", err)
+	}
+
+	if len(serial) != 20 {
+		t.Fatalf("serial parse found %d packages, want 20", len(serial))
+	}
+	if len(concurrent) != len(serial) {
+		t.Fatalf("concurrent parse found %d packages, want %d", len(concurrent), len(serial))
+	}
+	for pkgPath, want := range serial {
+		got, ok := concurrent[pkgPath]
+		if !ok {
+			t.Errorf("package %s missing from the concurrent parse", pkgPath)
+			continue
+		}
+		// A FileSet caches its last lookup, so compare the files it holds instead
+		if !reflect.DeepEqual(got.Package, want.Package) || !reflect.DeepEqual(got.TestFiles, want.TestFiles) ||
+			!reflect.DeepEqual(got.GeneratedFiles, want.GeneratedFiles) || !reflect.DeepEqual(fileSetFiles(got.FileSet), fileSetFiles(want.FileSet)) {
+			t.Errorf("package %s differs between the concurrent and the serial parse", pkgPath)
+		}
+	}
+}
+
+// fileSetFiles lists the name, base, and line offsets of every file in fset
+func fileSetFiles(fset *token.FileSet) []string {
+	var files []string
+	fset.Iterate(func(f *token.File) bool {
+		files = append(files, fmt.Sprintf("%s@%d %v", f.Name(), f.Base(), f.Lines()))
+		return true
+	})
+	return files
+}
+
+func BenchmarkParsePackages(b *testing.B) {
+	dir := writeFixture(b, syntheticTree(200))
+
+	benchmarks := []struct {
+		name    string
+		workers int
+	}{
+		{"serial", 1},
+		{"concurrent", runtime.NumCPU()},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := parsePackages(dir, nil, true, false, bm.workers, newProgressLogger(nil)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"go/ast"
+)
+
+// countAnonymousTypes counts struct and interface types written inline in a function's
+// parameters, results, and body (variable declarations, conversions, composite literals, and
+// fields of other inline types) instead of being declared as named types.
+// The empty types struct{} and interface{} are idiomatic and not counted, nor are inline
+// type parameter constraints.
+func countAnonymousTypes(funcDecl *ast.FuncDecl) int {
+	count := 0
+
+	inspect := func(n ast.Node) bool {
+		switch t := n.(type) {
+		case *ast.StructType:
+			if t.Fields != nil && len(t.Fields.List) > 0 {
+				count++
+			}
+		case *ast.InterfaceType:
+			if t.Methods != nil && len(t.Methods.List) > 0 {
+				count++
+			}
+		}
+		return true
+	}
+
+	if funcDecl.Type.Params != nil {
+		ast.Inspect(funcDecl.Type.Params, inspect)
+	}
+	if funcDecl.Type.Results != nil {
+		ast.Inspect(funcDecl.Type.Results, inspect)
+	}
+	if funcDecl.Body != nil {
+		ast.Inspect(funcDecl.Body, inspect)
+	}
+
+	return count
+}
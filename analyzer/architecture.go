@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ArchFileName is the architecture rules file discovered at the root of the analyzed project
+const ArchFileName = "arch.yaml"
+
+// ArchRules declares the intended dependency directions between packages.
+//
+// The file is a small subset of YAML with two lists of "->" chains:
+//
+//	layers:
+//	  - handlers -> services -> repositories
+//	deny:
+//	  - repositories -> handlers
+//
+// A layers chain lists layers from top to bottom: a package may import its own layer and
+// the layers below it, but never a layer above it. A deny rule forbids imports from the
+// left-hand packages to the right-hand packages regardless of layers.
+//
+// Each element is a package path pattern relative to the project root, matched like
+// .health-ignore patterns: a bare name ("services") matches any package with that directory
+// name, a path ("internal/services") is anchored at the root, and subpackages are included.
+type ArchRules struct {
+	Layers [][]string     // Layer chains, each ordered top to bottom
+	Deny   []ArchDenyRule // Explicitly forbidden imports
+}
+
+// ArchDenyRule forbids packages matching From from importing packages matching To
+type ArchDenyRule struct {
+	From string
+	To   string
+}
+
+// LoadArchRules reads ArchFileName from the project root, returning nil if it is absent
+func LoadArchRules(rootPath string) (*ArchRules, error) {
+	data, err := os.ReadFile(filepath.Join(rootPath, ArchFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ArchFileName, err)
+	}
+
+	rules, err := parseArchRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ArchFileName, err)
+	}
+	return rules, nil
+}
+
+// parseArchRules parses the supported YAML subset: top-level keys followed by "- item" lists
+func parseArchRules(data []byte) (*ArchRules, error) {
+	rules := &ArchRules{}
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		// Top-level key
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(trimmed, "-") {
+			key, rest, found := strings.Cut(trimmed, ":")
+			if !found || strings.TrimSpace(rest) != "" {
+				return nil, fmt.Errorf("line %d: expected a \"layers:\" or \"deny:\" key", lineNum)
+			}
+			section = strings.TrimSpace(key)
+			if section != "layers" && section != "deny" {
+				return nil, fmt.Errorf("line %d: unknown key %q", lineNum, section)
+			}
+			continue
+		}
+
+		// List item
+		if !strings.HasPrefix(trimmed, "-") {
+			return nil, fmt.Errorf("line %d: expected a list item starting with \"-\"", lineNum)
+		}
+		if section == "" {
+			return nil, fmt.Errorf("line %d: list item outside of \"layers:\" or \"deny:\"", lineNum)
+		}
+
+		chain, err := parseArchChain(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		switch section {
+		case "layers":
+			rules.Layers = append(rules.Layers, chain)
+		case "deny":
+			// "a -> b -> c" denies every downstream pair
+			for i := 0; i < len(chain); i++ {
+				for j := i + 1; j < len(chain); j++ {
+					rules.Deny = append(rules.Deny, ArchDenyRule{From: chain[i], To: chain[j]})
+				}
+			}
+		}
+	}
+
+	return rules, nil
+}
+
+// parseArchChain splits "a -> b -> c" into validated package patterns
+func parseArchChain(item string) ([]string, error) {
+	item = strings.Trim(item, `"'`)
+
+	var chain []string
+	for _, part := range strings.Split(item, "->") {
+		pattern := strings.Trim(strings.TrimSpace(part), "/")
+		if pattern == "" {
+			return nil, fmt.Errorf("empty package pattern in %q", item)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		chain = append(chain, pattern)
+	}
+
+	if len(chain) < 2 {
+		return nil, fmt.Errorf("%q must relate at least two packages with \"->\"", item)
+	}
+	return chain, nil
+}
+
+// layerIndex returns the position of the first layer in the chain matching the package, or -1
+func layerIndex(chain []string, pkgPath string) int {
+	for i, pattern := range chain {
+		if matchPathPattern(pattern, pkgPath) {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkImport returns the rule an import from pkgPath to importPath violates, or "" if it is allowed
+func (r *ArchRules) checkImport(pkgPath string, importPath string) string {
+	for _, rule := range r.Deny {
+		if matchPathPattern(rule.From, pkgPath) && matchPathPattern(rule.To, importPath) {
+			return fmt.Sprintf("deny: %s -> %s", rule.From, rule.To)
+		}
+	}
+
+	for _, chain := range r.Layers {
+		from := layerIndex(chain, pkgPath)
+		to := layerIndex(chain, importPath)
+		if from >= 0 && to >= 0 && to < from {
+			return fmt.Sprintf("layers: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	return ""
+}
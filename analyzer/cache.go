@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// cacheInputFiles are the non-Go files whose content changes an analysis: module and workspace
+// definitions anywhere in the tree, and the ignore and architecture files at the root
+var cacheInputFiles = map[string]bool{
+	"go.mod":       true,
+	WorkFileName:   true,
+	IgnoreFileName: true,
+	ArchFileName:   true,
+}
+
+// cacheKey fingerprints the inputs of an analysis: the analyzer and schema versions, the target
+// path, the exclude patterns, the configuration, and the path and content of every Go file (test
+// and generated files included) and cacheInputFiles file in the analyzed directories. Any change
+// to them yields a different key.
+func cacheKey(absPath string, excludeDirs []string, cfg *Config) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "analyzer %s\x00schema %s\x00target %s\x00", analyzerVersion(), SchemaVersion, absPath)
+	fmt.Fprintf(hash, "exclude %s\x00", strings.Join(excludeDirs, "\x01"))
+
+	// Ignore and Arch are not part of the JSON; their files are hashed below
+	configJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode config for the cache key: %w", err)
+	}
+	fmt.Fprintf(hash, "config %s\x00", configJSON)
+
+	dirs, err := collectSourceDirs(absPath, excludeDirs)
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || (!strings.HasSuffix(name, ".go") && !cacheInputFiles[name]) {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if err := hashFile(hash, absPath, path); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// hashFile adds a file's path relative to root, size, and content to the hash
+func hashFile(hash io.Writer, root string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	relPath, err := filepath.Rel(root, path)
+	if err != nil {
+		relPath = path
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	fmt.Fprintf(hash, "file %s %d\x00", filepath.ToSlash(relPath), info.Size())
+	if _, err := io.Copy(hash, file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return nil
+}
+
+// cachePath returns the file holding the cached report for a key
+func cachePath(cacheDir string, key string) string {
+	return filepath.Join(cacheDir, key+".json")
+}
+
+// loadCachedReport returns the report cached under key, or nil if there is none or it cannot be read
+func loadCachedReport(cacheDir string, key string) *Report {
+	data, err := os.ReadFile(cachePath(cacheDir, key))
+	if err != nil {
+		return nil
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil
+	}
+	return &report
+}
+
+// storeCachedReport writes the report under key. It writes a temporary file and renames it, so
+// concurrent analyses sharing a cache directory never read a partial report.
+func storeCachedReport(cacheDir string, key string, report *Report) error {
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report for the cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(cacheDir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), cachePath(cacheDir, key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
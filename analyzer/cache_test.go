@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzeWithCacheDir(t *testing.T) {
+	dir := writeFixture(t, map[string]string{
+		"app/app.go": "package app\n\nfunc Add(a, b int) int { return a + b }\n",
+	})
+	cacheDir := t.TempDir()
+	opts := AnalyzeOptions{CacheDir: cacheDir}
+
+	report, err := AnalyzeWithOptions(dir, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("cache entries = %v (%v), want one", entries, err)
+	}
+
+	// Mark the cached report so a cache hit can be told apart from a fresh analysis
+	report.TargetPath = "cached"
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(entries[0], data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cached, err := AnalyzeWithOptions(dir, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if cached.TargetPath != "cached" {
+		t.Errorf("unchanged inputs analyzed again, want the cached report")
+	}
+
+	// A different configuration misses the cache
+	cfg := DefaultConfig()
+	cfg.ComplexFunctionThreshold++
+	reconfigured, err := AnalyzeWithOptions(dir, AnalyzeOptions{CacheDir: cacheDir, Config: cfg})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if reconfigured.TargetPath == "cached" {
+		t.Errorf("changed configuration returned the cached report")
+	}
+
+	// So does a changed Go file
+	if err := os.WriteFile(filepath.Join(dir, "app", "app.go"), []byte("package app\n\nfunc Sub(a, b int) int { return a - b }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := AnalyzeWithOptions(dir, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if changed.TargetPath == "cached" {
+		t.Fatalf("changed source returned the cached report")
+	}
+	findFunction(t, findPackage(t, changed, "app"), "Sub")
+}
@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// collectChurn counts the commits of the last days days that touched each file under rootPath,
+// keyed by absolute file path. It returns an error when git is not installed or rootPath is not
+// inside a git work tree; callers treat that as "no churn data" rather than failing the analysis.
+func collectChurn(rootPath string, days int, now time.Time) (map[string]int, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is not installed")
+	}
+
+	out, err := exec.Command("git", "-C", rootPath, "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not inside a git work tree", rootPath)
+	}
+	prefix := strings.TrimSpace(string(out))
+
+	since := now.AddDate(0, 0, -days).Format(time.RFC3339)
+	out, err = exec.Command("git", "-C", rootPath, "log", "--since="+since, "--name-only", "--format=", "--no-renames", "--", ".").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	// Paths are listed once per commit, relative to the top of the work tree
+	churn := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		churn[filepath.Join(rootPath, filepath.FromSlash(strings.TrimPrefix(line, prefix)))]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read git log: %w", err)
+	}
+
+	return churn, nil
+}
+
+// applyChurn sets the commit count of every file and function. A function gets the count of
+// its file: git history is tracked per file, so every function in a file shares its churn.
+func applyChurn(packages []PackageResult, churn map[string]int) {
+	for i := range packages {
+		pkg := &packages[i]
+		for j := range pkg.Files {
+			pkg.Files[j].Churn = churn[pkg.Files[j].Path]
+		}
+		for j := range pkg.Functions {
+			pkg.Functions[j].Churn = churn[pkg.Functions[j].FilePath]
+		}
+	}
+}
+
+// rankHotspots returns the functions with the highest complexity × churn, capped at n entries.
+// Functions in files without commits in the window, and test functions, are left out.
+func rankHotspots(packages []PackageResult, n int) []Hotspot {
+	if n <= 0 {
+		return nil
+	}
+
+	var hotspots []Hotspot
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.IsTest || f.Churn == 0 {
+				continue
+			}
+			hotspots = append(hotspots, Hotspot{
+				Name:        fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Package:     pkg.Path,
+				Complexity:  f.Complexity,
+				Churn:       f.Churn,
+				Score:       f.Complexity * f.Churn,
+				FilePath:    f.FilePath,
+				Line:        f.Line,
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		if hotspots[i].Score != hotspots[j].Score {
+			return hotspots[i].Score > hotspots[j].Score
+		}
+		return hotspots[i].RelatedPath < hotspots[j].RelatedPath
+	})
+
+	if len(hotspots) > n {
+		hotspots = hotspots[:n]
+	}
+	return hotspots
+}
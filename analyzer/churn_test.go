@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// gitCommit runs git in dir with a fixed identity, failing the test on error
+func gitCommit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestChurnAndHotspots(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := writeFixture(t, map[string]string{
+		"app/busy.go":  "package app\n\nfunc Busy(n int) int {\n\tif n > 0 {\n\t\treturn n\n\t}\n\treturn 0\n}\n",
+		"app/quiet.go": "package app\n\nfunc Quiet() int { return 1 }\n",
+	})
+	gitCommit(t, dir, "init", "-q")
+	gitCommit(t, dir, "add", ".")
+	gitCommit(t, dir, "commit", "-q", "-m", "initial")
+	busy := filepath.Join(dir, "app", "busy.go")
+	for i := 0; i < 2; i++ {
+		f, err := os.OpenFile(busy, os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString("\n// edited\n")
+		f.Close()
+		gitCommit(t, dir, "commit", "-q", "-am", "edit busy")
+	}
+
+	cfg := DefaultConfig()
+	cfg.ChurnDays = 30
+	report, err := AnalyzeWithConfig(dir, nil, cfg)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	pkg := findPackage(t, report, "app")
+	churn := make(map[string]int)
+	for _, file := range pkg.Files {
+		churn[filepath.Base(file.Path)] = file.Churn
+	}
+	if churn["busy.go"] != 3 || churn["quiet.go"] != 1 {
+		t.Errorf("file churn = %v, want busy.go 3 and quiet.go 1", churn)
+	}
+	if f := findFunction(t, pkg, "Busy"); f.Churn != 3 {
+		t.Errorf("Busy churn = %d, want 3", f.Churn)
+	}
+
+	if len(report.Hotspots) != 2 || report.Hotspots[0].Name != "app.Busy" || report.Hotspots[0].Score != 6 {
+		t.Errorf("hotspots = %+v, want app.Busy first with score 2 × 3", report.Hotspots)
+	}
+}
+
+func TestChurnOutsideGitRepository(t *testing.T) {
+	if _, err := collectChurn(t.TempDir(), 30, time.Now()); err == nil {
+		t.Skip("temporary directory is inside a git work tree")
+	}
+
+	cfg := DefaultConfig()
+	cfg.ChurnDays = 30
+	report := analyzeFixture(t, map[string]string{"app/app.go": "package app\n\nfunc Run() {}\n"}, cfg)
+	if f := findFunction(t, findPackage(t, report, "app"), "Run"); f.Churn != 0 || len(report.Hotspots) != 0 {
+		t.Errorf("churn = %d, hotspots = %v, want none without git history", f.Churn, report.Hotspots)
+	}
+}
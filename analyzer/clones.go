@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strings"
+)
+
+// duplicateCodeMinStatements is the smallest body, in statements, compared for duplicates.
+// Short bodies such as getters and delegating wrappers share shapes by nature and are not reported.
+const duplicateCodeMinStatements = 20
+
+// bodyFingerprint returns a hash of a function body's syntax tree with identifier names and
+// literal values left out. Bodies that differ only in naming or constants (a copy with renamed
+// variables) hash the same; the tree shape, operators, and literal kinds must match. Functions
+// without a body return an empty hash.
+func bodyFingerprint(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Body == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if n == nil {
+			b.WriteString(")")
+			return true
+		}
+
+		// Node type, then the parts that carry meaning besides names
+		b.WriteString(reflect.TypeOf(n).Elem().Name())
+		switch node := n.(type) {
+		case *ast.BasicLit:
+			b.WriteString(":" + node.Kind.String())
+		case *ast.BinaryExpr:
+			b.WriteString(":" + node.Op.String())
+		case *ast.UnaryExpr:
+			b.WriteString(":" + node.Op.String())
+		case *ast.AssignStmt:
+			b.WriteString(":" + node.Tok.String())
+		case *ast.IncDecStmt:
+			b.WriteString(":" + node.Tok.String())
+		case *ast.BranchStmt:
+			b.WriteString(":" + node.Tok.String())
+		case *ast.ChanType:
+			fmt.Fprintf(&b, ":%d", node.Dir)
+		}
+		b.WriteString("(")
+		return true
+	})
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// duplicateGroup is a set of functions whose bodies share a fingerprint
+type duplicateGroup struct {
+	Statements int
+	Functions  []dataClumpMember
+}
+
+// findDuplicateBodies groups non-test functions with identical body fingerprints and at least
+// duplicateCodeMinStatements statements (see countStatements), in the order the first function of each group is found
+func findDuplicateBodies(packages []PackageResult) []duplicateGroup {
+	groups := make(map[string]*duplicateGroup)
+	var order []string
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.IsTest || f.BodyHash == "" || f.Statements < duplicateCodeMinStatements {
+				continue
+			}
+
+			group, ok := groups[f.BodyHash]
+			if !ok {
+				group = &duplicateGroup{Statements: f.Statements}
+				groups[f.BodyHash] = group
+				order = append(order, f.BodyHash)
+			}
+			group.Functions = append(group.Functions, dataClumpMember{Package: pkg, Function: f})
+		}
+	}
+
+	var duplicates []duplicateGroup
+	for _, hash := range order {
+		if group := groups[hash]; len(group.Functions) >= 2 {
+			duplicates = append(duplicates, *group)
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// cloneBody returns a function with 22 statements using the given variable name, constant, and operator
+func cloneBody(name, variable string, constant int, op string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "func %s(n int) int {\n\t%s := 0\n", name, variable)
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&b, "\t%s = %s %s n*%d\n", variable, variable, op, constant+i)
+	}
+	fmt.Fprintf(&b, "\treturn %s\n}\n\n", variable)
+	return b.String()
+}
+
+func TestDuplicateCodeDiagnostic(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"calc/sum.go": "package calc\n\n" + cloneBody("Sum", "total", 1, "+"),
+		"calc/alt.go": "package calc\n\n" + cloneBody("Total", "acc", 7, "+") +
+			// Same shape with another operator: different code
+			cloneBody("Diff", "rest", 1, "-") +
+			// Identical tiny bodies are below the size guard
+			"func A() int { return 1 }\n\nfunc B() int { return 2 }\n",
+	}, nil)
+
+	duplicates := diagnosticsOfType(report, DiagnosticDuplicateCode)
+	if len(duplicates) != 1 {
+		t.Fatalf("got %d duplicate code diagnostics, want 1: %+v", len(duplicates), duplicates)
+	}
+	evidence, ok := duplicates[0].Evidence.(DuplicateCodeEvidence)
+	if !ok {
+		t.Fatalf("evidence is %T, want DuplicateCodeEvidence", duplicates[0].Evidence)
+	}
+	if want := []string{"calc.Sum", "calc.Total"}; !reflect.DeepEqual(evidence.Functions, want) {
+		t.Errorf("functions = %v, want %v", evidence.Functions, want)
+	}
+	if evidence.Statements != 22 || len(evidence.Locations) != 2 {
+		t.Errorf("statements = %d, locations = %v, want 22 statements in two places", evidence.Statements, evidence.Locations)
+	}
+}
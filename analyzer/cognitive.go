@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// calculateCognitiveComplexity calculates cognitive complexity following the SonarSource rules:
+//   - +1 for each if, switch, type switch, select, for, and range, plus 1 per level of nesting
+//   - +1 for each else if and else, without a nesting penalty (they continue the same structure)
+//   - +1 for each sequence of like boolean operators ("a && b && c" is 1, "a && b || c" is 2)
+//   - +1 for each labeled break, continue, or goto
+//
+// Nesting increases inside the bodies of those structures and inside function literals.
+// Unlike cyclomatic complexity, a nested loop costs more than a flat chain of ifs.
+func calculateCognitiveComplexity(funcDecl *ast.FuncDecl) int {
+	if funcDecl.Body == nil {
+		return 0
+	}
+
+	complexity := 0
+
+	var visit func(n ast.Node, nesting int)
+	var visitIf func(ifStmt *ast.IfStmt, nesting int)
+
+	visitIf = func(ifStmt *ast.IfStmt, nesting int) {
+		visit(ifStmt.Init, nesting)
+		visit(ifStmt.Cond, nesting)
+		visit(ifStmt.Body, nesting+1)
+
+		switch elseStmt := ifStmt.Else.(type) {
+		case *ast.IfStmt:
+			complexity++
+			visitIf(elseStmt, nesting)
+		case *ast.BlockStmt:
+			complexity++
+			visit(elseStmt, nesting+1)
+		}
+	}
+
+	visit = func(n ast.Node, nesting int) {
+		if n == nil {
+			return
+		}
+
+		ast.Inspect(n, func(node ast.Node) bool {
+			switch x := node.(type) {
+			case *ast.IfStmt:
+				complexity += 1 + nesting
+				visitIf(x, nesting)
+				return false
+
+			case *ast.ForStmt:
+				complexity += 1 + nesting
+				visit(x.Init, nesting)
+				visit(x.Cond, nesting)
+				visit(x.Post, nesting)
+				visit(x.Body, nesting+1)
+				return false
+
+			case *ast.RangeStmt:
+				complexity += 1 + nesting
+				visit(x.X, nesting)
+				visit(x.Body, nesting+1)
+				return false
+
+			case *ast.SwitchStmt:
+				complexity += 1 + nesting
+				visit(x.Init, nesting)
+				visit(x.Tag, nesting)
+				visit(x.Body, nesting+1)
+				return false
+
+			case *ast.TypeSwitchStmt:
+				complexity += 1 + nesting
+				visit(x.Init, nesting)
+				visit(x.Assign, nesting)
+				visit(x.Body, nesting+1)
+				return false
+
+			case *ast.SelectStmt:
+				complexity += 1 + nesting
+				visit(x.Body, nesting+1)
+				return false
+
+			case *ast.FuncLit:
+				visit(x.Body, nesting+1)
+				return false
+
+			case *ast.BranchStmt:
+				if x.Label != nil {
+					complexity++
+				}
+
+			case *ast.BinaryExpr:
+				if x.Op != token.LAND && x.Op != token.LOR {
+					return true
+				}
+
+				// Count each run of identical operators once, then visit the operands
+				var operators []token.Token
+				var operands []ast.Expr
+				flattenLogicalExpr(x, &operators, &operands)
+				for i, op := range operators {
+					if i == 0 || op != operators[i-1] {
+						complexity++
+					}
+				}
+				for _, operand := range operands {
+					visit(operand, nesting)
+				}
+				return false
+			}
+			return true
+		})
+	}
+
+	visit(funcDecl.Body, 0)
+
+	return complexity
+}
+
+// flattenLogicalExpr lists the && and || operators of an unparenthesized boolean expression
+// in source order, along with the operands between them
+func flattenLogicalExpr(expr ast.Expr, operators *[]token.Token, operands *[]ast.Expr) {
+	binary, ok := expr.(*ast.BinaryExpr)
+	if !ok || (binary.Op != token.LAND && binary.Op != token.LOR) {
+		*operands = append(*operands, expr)
+		return
+	}
+
+	flattenLogicalExpr(binary.X, operators, operands)
+	*operators = append(*operators, binary.Op)
+	flattenLogicalExpr(binary.Y, operators, operands)
+}
@@ -0,0 +1,103 @@
+package analyzer
+
+import "testing"
+
+func TestCognitiveComplexityPenalizesNesting(t *testing.T) {
+	flat := parseFunc(t, `func flat(a, b, c bool) int {
+	if a {
+		return 1
+	}
+	if b {
+		return 2
+	}
+	if c {
+		return 3
+	}
+	return 0
+}`)
+	nested := parseFunc(t, `func nested(a, b, c bool) int {
+	if a {
+		if b {
+			if c {
+				return 3
+			}
+		}
+	}
+	return 0
+}`)
+
+	weights := DefaultConfig().ComplexityWeights
+	if flatCC, nestedCC := calculateFunctionComplexity(flat, weights), calculateFunctionComplexity(nested, weights); flatCC != nestedCC {
+		t.Fatalf("cyclomatic complexity differs (flat %d, nested %d), want equal", flatCC, nestedCC)
+	}
+	if got := calculateCognitiveComplexity(flat); got != 3 {
+		t.Errorf("flat cognitive complexity = %d, want 3", got)
+	}
+	if got := calculateCognitiveComplexity(nested); got != 6 {
+		t.Errorf("nested cognitive complexity = %d, want 6 (1+2+3)", got)
+	}
+}
+
+func TestCognitiveComplexityRules(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"empty", `func f() {}`, 0},
+		{"else if and else", `func f(a, b bool) {
+	if a {
+	} else if b {
+	} else {
+	}
+}`, 3},
+		{"boolean sequences", `func f(a, b, c, d bool) bool {
+	return a && b && c || d
+}`, 2},
+		{"loop in closure", `func f(xs []int) {
+	go func() {
+		for range xs {
+		}
+	}()
+}`, 2},
+		{"labeled break", `func f(xs []int) {
+outer:
+	for range xs {
+		for range xs {
+			break outer
+		}
+	}
+}`, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateCognitiveComplexity(parseFunc(t, tt.src)); got != tt.want {
+				t.Errorf("cognitive complexity = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectHighCognitiveComplexity(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CognitiveComplexityThreshold = 5
+	packages := []PackageResult{{
+		Name: "app",
+		Path: "app",
+		Functions: []FunctionResult{
+			{FuncName: "Nested", CognitiveComplexity: 6, Complexity: 4},
+			{FuncName: "Flat", CognitiveComplexity: 3, Complexity: 4},
+		},
+	}}
+
+	results := detectHighCognitiveComplexity(packages, cfg)
+	if len(results) != 1 || results[0].TargetName != "app.Nested" {
+		t.Fatalf("got %+v, want one diagnostic for app.Nested", results)
+	}
+
+	cfg.CognitiveComplexityThreshold = 0
+	if results := detectHighCognitiveComplexity(packages, cfg); len(results) != 0 {
+		t.Errorf("threshold 0 should disable the diagnostic, got %d", len(results))
+	}
+}
@@ -3,12 +3,14 @@ package analyzer
 import (
 	"go/ast"
 	"go/token"
+	"sort"
 	"strings"
 )
 
 // CalculateComplexity calculates cyclomatic complexity for all functions in the package
-func CalculateComplexity(pkg *ast.Package, fset *token.FileSet, projectPrefix string) []FunctionResult {
+func CalculateComplexity(pkg *ast.Package, fset *token.FileSet, internalPrefixes []string, cfg *Config) []FunctionResult {
 	var results []FunctionResult
+	cfg = cfg.withDefaults()
 
 	// Traverse all files in the package
 	for fileName, file := range pkg.Files {
@@ -22,48 +24,86 @@ func CalculateComplexity(pkg *ast.Package, fset *token.FileSet, projectPrefix st
 			}
 
 			// Calculate complexity for this function
-			complexity := calculateFunctionComplexity(funcDecl)
-			funcName := funcDecl.Name.Name
-
-			// Add receiver type for methods
-			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-				recv := funcDecl.Recv.List[0]
-				var recvTypeName string
-				switch t := recv.Type.(type) {
-				case *ast.Ident:
-					recvTypeName = t.Name
-				case *ast.StarExpr:
-					if ident, ok := t.X.(*ast.Ident); ok {
-						recvTypeName = ident.Name
-					}
-				}
-				if recvTypeName != "" {
-					funcName = recvTypeName + "." + funcName
-				}
-			}
+			complexity := calculateFunctionComplexity(funcDecl, cfg.ComplexityWeights)
+			cognitiveComplexity := calculateCognitiveComplexity(funcDecl)
+			maxNestingDepth := calculateMaxNestingDepth(funcDecl)
+			funcName := qualifiedFuncName(funcDecl)
 
 			// Calculate LoC for this function
 			loc := CalculateFunctionLoC(funcDecl, fset)
+			sourceLoC := CalculateFunctionSourceLoC(funcDecl, fset)
+			statements := CalculateFunctionStatements(funcDecl)
+			literalLoC := largestLiteralLoC(funcDecl, fset)
 
 			// Extract dependencies for this function
-			deps := extractFunctionDependencies(funcDecl, fileImports, projectPrefix)
-			internalDeps, externalDeps := CategorizeDependencies(deps, projectPrefix)
+			deps, fanOut := extractFunctionDependencies(funcDecl, fileImports)
+			internalDeps, externalDeps := CategorizeDependencies(deps, internalPrefixes)
+
+			// Ce (Efferent): Count of unique packages this function depends on,
+			// limited to the configured kinds of external imports
+			efferent := len(internalDeps) + countExternalCoupling(externalDeps, cfg.ExternalCoupling)
+
+			// Count unnamed numeric literals
+			magicNumbers := countMagicNumbers(funcDecl)
+
+			// Find repeated type assertions on the same value
+			assertionSubject, assertedTypes := findTypeAssertionCascade(funcDecl)
 
-			// Ce (Efferent): Count of unique packages this function depends on
-			efferent := len(deps)
+			// Capture the returned value types and the parameters
+			resultTypes := extractResultTypes(funcDecl)
+			params := extractParams(funcDecl, pkg.Name, fileImports)
+
+			// Count operators and operands (Halstead metrics)
+			halsteadVolume, halsteadEffort := calculateHalstead(funcDecl)
+
+			// Fingerprint the body for duplicate detection
+			bodyHash := bodyFingerprint(funcDecl)
+
+			// Count struct and interface types written inline
+			anonymousTypes := countAnonymousTypes(funcDecl)
+
+			// Find allocations inside loops (opt-in performance hints)
+			var loopAllocations []LoopAllocation
+			if cfg.PerfHints {
+				loopAllocations = findLoopAllocations(funcDecl, fset)
+			}
 
 			results = append(results, FunctionResult{
-				FuncName:        funcName,
-				FilePath:        fileName,
-				Complexity:      complexity,
-				LoC:             loc,
-				Dependencies:    deps,
-				InternalDeps:    internalDeps,
-				ExternalDeps:    externalDeps,
-				DependencyCount: len(deps),
-				Efferent:        efferent,
-				Afferent:        0, // Will be calculated later in a second pass
-				Instability:     0, // Will be calculated later
+				FuncName:             funcName,
+				FilePath:             fileName,
+				Line:                 fset.Position(funcDecl.Pos()).Line,
+				EndLine:              fset.Position(funcDecl.End()).Line,
+				Complexity:           complexity,
+				CognitiveComplexity:  cognitiveComplexity,
+				MaxNestingDepth:      maxNestingDepth,
+				LoC:                  loc,
+				SourceLoC:            sourceLoC,
+				Statements:           statements,
+				LiteralLoC:           literalLoC,
+				Dependencies:         deps,
+				InternalDeps:         internalDeps,
+				ExternalDeps:         externalDeps,
+				DependencyCount:      len(deps),
+				Efferent:             efferent,
+				FanOut:               fanOut,
+				Afferent:             0, // Will be calculated later in a second pass
+				Instability:          0, // Will be calculated later
+				MagicNumbers:         magicNumbers,
+				AssertionSubject:     assertionSubject,
+				AssertedTypes:        assertedTypes,
+				ParamCount:           countParameters(funcDecl),
+				Params:               params,
+				ResultCount:          len(resultTypes),
+				ResultTypes:          resultTypes,
+				SurfaceComplexity:    surfaceComplexity(funcDecl, resultTypes),
+				LoopAllocations:      loopAllocations,
+				AnonymousTypes:       anonymousTypes,
+				HalsteadVolume:       halsteadVolume,
+				HalsteadEffort:       halsteadEffort,
+				MaintainabilityIndex: maintainabilityIndex(halsteadVolume, complexity, sourceLoC),
+				IsTest:               isTestFile(fileName),
+				Suppressed:           parseIgnoreDirectives(funcDecl.Doc),
+				BodyHash:             bodyHash,
 			})
 
 			return true
@@ -85,7 +125,8 @@ func CalculateComplexity(pkg *ast.Package, fset *token.FileSet, projectPrefix st
 	return results
 }
 
-// calculateAfferentCoupling calculates how many functions call each function
+// calculateAfferentCoupling calculates how many functions call each function, and marks
+// recursive functions from the same call graph (see recursion.go)
 func calculateAfferentCoupling(functions []FunctionResult, pkg *ast.Package) {
 	// Create a map for quick lookup
 	funcMap := make(map[string]*FunctionResult)
@@ -99,6 +140,9 @@ func calculateAfferentCoupling(functions []FunctionResult, pkg *ast.Package) {
 		localFunctions[f.FuncName] = true
 	}
 
+	// Calls between package functions, including calls through a method's own receiver
+	calls := make(map[string][]string)
+
 	// Traverse all functions and find function calls
 	for _, file := range pkg.Files {
 		ast.Inspect(file, func(n ast.Node) bool {
@@ -108,28 +152,22 @@ func calculateAfferentCoupling(functions []FunctionResult, pkg *ast.Package) {
 			}
 
 			// Get the name of the current function
-			callerName := funcDecl.Name.Name
-			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-				recv := funcDecl.Recv.List[0]
-				var recvTypeName string
-				switch t := recv.Type.(type) {
-				case *ast.Ident:
-					recvTypeName = t.Name
-				case *ast.StarExpr:
-					if ident, ok := t.X.(*ast.Ident); ok {
-						recvTypeName = ident.Name
-					}
-				}
-				if recvTypeName != "" {
-					callerName = recvTypeName + "." + callerName
-				}
-			}
+			callerName := qualifiedFuncName(funcDecl)
 
 			// Check if this is a function we're tracking
 			if !localFunctions[callerName] {
 				return true
 			}
 
+			// A recursive closure makes its enclosing function recursive
+			if hasRecursiveClosure(funcDecl.Body) {
+				calls[callerName] = append(calls[callerName], callerName)
+			}
+
+			// Calls like r.Method() through the receiver r resolve to the receiver's type
+			recvName := receiverName(funcDecl)
+			recvPrefix := strings.TrimSuffix(callerName, funcDecl.Name.Name)
+
 			// Find all function calls within this function
 			if funcDecl.Body != nil {
 				ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
@@ -151,6 +189,9 @@ func calculateAfferentCoupling(functions []FunctionResult, pkg *ast.Package) {
 							// Check if it's a method call (receiver is a local variable/type)
 							if localFunctions[ident.Name+"."+fun.Sel.Name] {
 								calledName = ident.Name + "." + fun.Sel.Name
+							} else if recvName != "" && ident.Name == recvName && localFunctions[recvPrefix+fun.Sel.Name] {
+								// Receiver calls only feed the recursion graph, not Ca
+								calls[callerName] = append(calls[callerName], recvPrefix+fun.Sel.Name)
 							}
 						}
 					}
@@ -160,6 +201,7 @@ func calculateAfferentCoupling(functions []FunctionResult, pkg *ast.Package) {
 						if calledFunc, exists := funcMap[calledName]; exists {
 							calledFunc.Afferent++
 						}
+						calls[callerName] = append(calls[callerName], calledName)
 					}
 
 					return true
@@ -169,6 +211,29 @@ func calculateAfferentCoupling(functions []FunctionResult, pkg *ast.Package) {
 			return true
 		})
 	}
+
+	markRecursion(functions, calls)
+}
+
+// projectImportNames maps each name a file refers to an imported project package by to that
+// package's path, skipping imports of the file's own package (pkgPath)
+func projectImportNames(file *ast.File, pkgPath string, packages map[string]*ParsedPackage, modules projectModules) map[string]string {
+	projectImports := make(map[string]string)
+	for name, importPath := range buildFileImportMap(file) {
+		if importedPath, ok := modules.packagePath(importPath); ok && importedPath != pkgPath {
+			projectImports[name] = importedPath
+		}
+	}
+	// The package name may differ from the last import path element
+	for _, imp := range file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		importedPath, ok := modules.packagePath(importPath)
+		if !ok || imp.Name != nil || importedPath == pkgPath || packages[importedPath] == nil {
+			continue
+		}
+		projectImports[packages[importedPath].Package.Name] = importedPath
+	}
+	return projectImports
 }
 
 // buildFileImportMap creates a mapping from package name/alias to full import path
@@ -195,10 +260,11 @@ func buildFileImportMap(file *ast.File) map[string]string {
 	return importMap
 }
 
-// extractFunctionDependencies extracts package dependencies from a function
-func extractFunctionDependencies(funcDecl *ast.FuncDecl, fileImports map[string]string, projectPrefix string) []string {
+// extractFunctionDependencies extracts package dependencies from a function, along with its
+// fan-out: the number of distinct functions and methods it calls, keyed as in extractCallTargets
+func extractFunctionDependencies(funcDecl *ast.FuncDecl, fileImports map[string]string) (deps []string, fanOut int) {
 	if funcDecl.Body == nil {
-		return nil
+		return nil, 0
 	}
 
 	usedPackages := make(map[string]bool)
@@ -225,18 +291,18 @@ func extractFunctionDependencies(funcDecl *ast.FuncDecl, fileImports map[string]
 	})
 
 	// Convert map to slice
-	var deps []string
 	for pkg := range usedPackages {
 		deps = append(deps, pkg)
 	}
+	sort.Strings(deps)
 
-	return deps
+	return deps, len(extractCallTargets(funcDecl.Body, "", ""))
 }
 
-// CategorizeDependencies categorizes dependencies into internal and external
-func CategorizeDependencies(deps []string, projectPrefix string) (internal []string, external []string) {
+// CategorizeDependencies categorizes dependencies into internal (under any of the internal prefixes) and external
+func CategorizeDependencies(deps []string, internalPrefixes []string) (internal []string, external []string) {
 	for _, dep := range deps {
-		if strings.HasPrefix(dep, projectPrefix) {
+		if isInternalImport(dep, internalPrefixes) {
 			internal = append(internal, dep)
 		} else {
 			external = append(external, dep)
@@ -245,8 +311,49 @@ func CategorizeDependencies(deps []string, projectPrefix string) (internal []str
 	return
 }
 
-// calculateFunctionComplexity calculates the cyclomatic complexity of a function
-func calculateFunctionComplexity(funcDecl *ast.FuncDecl) int {
+// countExternalCoupling counts the external dependencies of the kinds selected by mode
+// (ExternalCouplingAll, ExternalCouplingStdlib, or ExternalCouplingThirdParty; empty means all)
+func countExternalCoupling(external []string, mode string) int {
+	count := 0
+	for _, dep := range external {
+		switch mode {
+		case ExternalCouplingStdlib:
+			if isStandardLibraryImport(dep) {
+				count++
+			}
+		case ExternalCouplingThirdParty:
+			if !isStandardLibraryImport(dep) {
+				count++
+			}
+		default:
+			count++
+		}
+	}
+	return count
+}
+
+// isStandardLibraryImport reports whether an import path belongs to the standard library.
+// As in the go command, paths whose first element has no dot are reserved for it.
+func isStandardLibraryImport(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// calculateFunctionComplexity calculates the cyclomatic complexity of a function.
+// Each construct adds its configured weight; control-flow constructs additionally add
+// the "nesting" weight once per enclosing if/for/range/switch/select.
+//
+// Starting from 1, the default weights count one path per:
+//   - if, for, and range statement (else and else if add nothing beyond the nested if)
+//   - switch and type switch statement, plus each case clause listing values or types;
+//     default clauses add no path, and fallthrough only joins two existing paths
+//   - select case with a communication; the default branch adds no path
+//   - && and || operator
+//   - goto statement, which jumps to a label outside the structured flow
+//
+// break and continue, labeled or not, leave a loop that is already counted and add nothing.
+// Function literals are part of the enclosing function.
+func calculateFunctionComplexity(funcDecl *ast.FuncDecl, weights map[string]int) int {
 	// Start with base complexity of 1
 	complexity := 1
 
@@ -254,38 +361,70 @@ func calculateFunctionComplexity(funcDecl *ast.FuncDecl) int {
 		return complexity
 	}
 
+	// Track how many control-flow constructs enclose the current node
+	var stack []ast.Node
+	depth := 0
+
 	// Count decision points
 	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		if n == nil {
+			// Leaving a node
+			if isNestingNode(stack[len(stack)-1]) {
+				depth--
+			}
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+
+		nesting := weights[WeightNesting] * depth
+
 		switch node := n.(type) {
 		case *ast.IfStmt:
 			// Each if adds 1 to complexity
-			complexity++
+			complexity += weights[WeightIf] + nesting
 
-		case *ast.ForStmt, *ast.RangeStmt:
+		case *ast.ForStmt:
 			// Each loop adds 1 to complexity
-			complexity++
+			complexity += weights[WeightFor] + nesting
+
+		case *ast.RangeStmt:
+			complexity += weights[WeightRange] + nesting
 
-		case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+		case *ast.SwitchStmt:
 			// Switch statement itself adds 1
-			complexity++
+			complexity += weights[WeightSwitch] + nesting
+
+		case *ast.TypeSwitchStmt:
+			complexity += weights[WeightTypeSwitch] + nesting
 
 		case *ast.CaseClause:
-			// Each case (except default) adds 1
+			// Each case (except default) adds 1, in expression and type switches alike
 			if node.List != nil && len(node.List) > 0 {
-				complexity++
+				complexity += weights[WeightCase]
 			}
 
 		case *ast.CommClause:
 			// Each case in select statement adds 1
 			if node.Comm != nil {
-				complexity++
+				complexity += weights[WeightSelectCase]
 			}
 
 		case *ast.BinaryExpr:
 			// Logical operators add to complexity
 			if node.Op == token.LAND || node.Op == token.LOR {
-				complexity++
+				complexity += weights[WeightLogicalOperator]
 			}
+
+		case *ast.BranchStmt:
+			// goto adds a jump; break, continue, and fallthrough follow existing paths
+			if node.Tok == token.GOTO {
+				complexity += weights[WeightGoto]
+			}
+		}
+
+		if isNestingNode(n) {
+			depth++
 		}
 
 		return true
@@ -293,3 +432,163 @@ func calculateFunctionComplexity(funcDecl *ast.FuncDecl) int {
 
 	return complexity
 }
+
+// isNestingNode reports whether a node opens a nested control-flow block
+func isNestingNode(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+		return true
+	}
+	return false
+}
+
+// calculateMaxNestingDepth returns the deepest nesting of if/for/range/switch/select blocks in a function.
+// An else if continues its chain rather than nesting, and function literals start again from
+// depth 0 relative to their own body.
+func calculateMaxNestingDepth(funcDecl *ast.FuncDecl) int {
+	if funcDecl.Body == nil {
+		return 0
+	}
+
+	maxDepth := 0
+
+	var visit func(n ast.Node, depth int)
+	visit = func(n ast.Node, depth int) {
+		if n == nil {
+			return
+		}
+
+		ast.Inspect(n, func(node ast.Node) bool {
+			switch x := node.(type) {
+			case *ast.IfStmt:
+				if depth+1 > maxDepth {
+					maxDepth = depth + 1
+				}
+				// Walk the else if chain at the same depth
+				for ifStmt := x; ifStmt != nil; {
+					visit(ifStmt.Init, depth)
+					visit(ifStmt.Cond, depth)
+					visit(ifStmt.Body, depth+1)
+
+					elseIf, ok := ifStmt.Else.(*ast.IfStmt)
+					if !ok {
+						visit(ifStmt.Else, depth+1)
+						break
+					}
+					ifStmt = elseIf
+				}
+				return false
+
+			case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				if depth+1 > maxDepth {
+					maxDepth = depth + 1
+				}
+				// Headers (conditions, range expressions) stay at the current depth
+				switch s := x.(type) {
+				case *ast.ForStmt:
+					visit(s.Init, depth)
+					visit(s.Cond, depth)
+					visit(s.Post, depth)
+					visit(s.Body, depth+1)
+				case *ast.RangeStmt:
+					visit(s.X, depth)
+					visit(s.Body, depth+1)
+				case *ast.SwitchStmt:
+					visit(s.Init, depth)
+					visit(s.Tag, depth)
+					visit(s.Body, depth+1)
+				case *ast.TypeSwitchStmt:
+					visit(s.Init, depth)
+					visit(s.Body, depth+1)
+				case *ast.SelectStmt:
+					visit(s.Body, depth+1)
+				}
+				return false
+
+			case *ast.FuncLit:
+				visit(x.Body, 0)
+				return false
+			}
+			return true
+		})
+	}
+
+	visit(funcDecl.Body, 0)
+
+	return maxDepth
+}
+
+// countParameters returns the number of parameters a function declares. Grouped parameters
+// (a, b int) count once per name, unnamed parameters once each, and a variadic parameter once.
+// The receiver of a method is not a parameter.
+func countParameters(funcDecl *ast.FuncDecl) int {
+	if funcDecl.Type.Params == nil {
+		return 0
+	}
+	return funcDecl.Type.Params.NumFields()
+}
+
+// applyCrossPackageAfferentCoupling adds calls from other project packages to function afferent
+// coupling (Ca). A call pkg.Func() counts when pkg is an import of a project package, resolved
+// through the calling file's imports, so functions with the same name in different packages
+// are kept apart. Identifiers bound to a local declaration (a variable shadowing an import)
+// are not package references and are skipped.
+func applyCrossPackageAfferentCoupling(packageResults []PackageResult, packages map[string]*ParsedPackage, modules projectModules) {
+	// Index functions by package path and name
+	funcIndex := make(map[string]*FunctionResult)
+	for i := range packageResults {
+		pkg := &packageResults[i]
+		for j := range pkg.Functions {
+			funcIndex[pkg.Path+"|"+pkg.Functions[j].FuncName] = &pkg.Functions[j]
+		}
+	}
+
+	for pkgPath := range packages {
+		forEachCrossPackageCall(pkgPath, packages, modules, func(key string) {
+			if calledFunc, exists := funcIndex[key]; exists {
+				calledFunc.Afferent++
+			}
+		})
+	}
+
+	// Recalculate instability with the added callers
+	for i := range packageResults {
+		for j := range packageResults[i].Functions {
+			f := &packageResults[i].Functions[j]
+			if total := f.Afferent + f.Efferent; total > 0 {
+				f.Instability = float64(f.Efferent) / float64(total)
+			}
+		}
+	}
+}
+
+// forEachCrossPackageCall calls fn with "pkgPath|FuncName" for every pkg.Func() call in the
+// package at callerPath whose pkg is an import of another project package
+func forEachCrossPackageCall(callerPath string, packages map[string]*ParsedPackage, modules projectModules, fn func(key string)) {
+	for _, file := range packages[callerPath].Package.Files {
+		projectImports := projectImportNames(file, callerPath, packages, modules)
+		if len(projectImports) == 0 {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			callExpr, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			selector, ok := callExpr.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			ident, ok := selector.X.(*ast.Ident)
+			if !ok || ident.Obj != nil {
+				return true
+			}
+
+			if importedPath, exists := projectImports[ident.Name]; exists {
+				fn(importedPath + "|" + selector.Sel.Name)
+			}
+			return true
+		})
+	}
+}
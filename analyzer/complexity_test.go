@@ -0,0 +1,289 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculateMaxNestingDepth(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"empty", `func f() {}`, 0},
+		{"nested loops", `func f(xs [][]int) {
+	for _, row := range xs {
+		for i := 0; i < len(row); i++ {
+			switch row[i] {
+			case 0:
+				if i > 1 {
+				}
+			}
+		}
+	}
+}`, 4},
+		{"guard clauses", `func f(a, b, c bool) error {
+	if a {
+		return nil
+	}
+	if b {
+		return nil
+	}
+	if c {
+		return nil
+	}
+	return nil
+}`, 1},
+		{"else if chain", `func f(n int) {
+	if n == 0 {
+	} else if n == 1 {
+	} else if n == 2 {
+		if n > 0 {
+		}
+	} else {
+	}
+}`, 2},
+		{"anonymous function resets depth", `func f(xs []int) {
+	for range xs {
+		go func() {
+			if len(xs) > 0 {
+			}
+		}()
+	}
+}`, 1},
+		{"nesting inside anonymous function", `func f(xs []int) {
+	fn := func() {
+		for range xs {
+			select {
+			default:
+				if len(xs) > 0 {
+				}
+			}
+		}
+	}
+	fn()
+}`, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateMaxNestingDepth(parseFunc(t, tt.src)); got != tt.want {
+				t.Errorf("max nesting depth = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDeeplyNestedFunctions(t *testing.T) {
+	packages := []PackageResult{{
+		Name: "app",
+		Path: "app",
+		Functions: []FunctionResult{
+			{FuncName: "Deep", MaxNestingDepth: 4},
+			{FuncName: "Shallow", MaxNestingDepth: 3},
+		},
+	}}
+
+	results := detectDeeplyNestedFunctions(packages)
+	if len(results) != 1 || results[0].TargetName != "app.Deep" || results[0].Severity != "Warning" {
+		t.Fatalf("got %+v, want one Warning for app.Deep", results)
+	}
+}
+
+func TestCrossPackageAfferentCoupling(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"util/util.go": "package util\n\nfunc Format(s string) string { return s }\n",
+		// Same function name in another package must not collect util's callers
+		"other/other.go": "package other\n\nfunc Format(s string) string { return s + \"!\" }\n",
+		"svc/svc.go": `package svc
+
+import (
+	"example.com/app/util"
+	u "example.com/app/util"
+)
+
+type formatter struct{}
+
+func (formatter) Format(s string) string { return s }
+
+func Hello() string { return util.Format("hello") }
+
+func Bye() string { return u.Format("bye") }
+
+func Shadowed() string {
+	util := formatter{}
+	return util.Format("not a package call")
+}
+`,
+	}, nil)
+
+	if got := findFunction(t, findPackage(t, report, "util"), "Format").Afferent; got != 2 {
+		t.Errorf("util.Format afferent = %d, want 2 (Hello and the aliased call in Bye)", got)
+	}
+	if got := findFunction(t, findPackage(t, report, "other"), "Format").Afferent; got != 0 {
+		t.Errorf("other.Format afferent = %d, want 0", got)
+	}
+}
+
+func TestCalculateFunctionComplexityCountingRules(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		// 1 + goto; the if inside the label block adds 1
+		{"goto loop", `func f(n int) int {
+	i := 0
+loop:
+	if i < n {
+		i++
+		goto loop
+	}
+	return i
+}`, 3},
+		// 1 + type switch + two typed cases; default adds nothing
+		{"type switch", `func f(v any) string {
+	switch v.(type) {
+	case int:
+		return "int"
+	case string, []byte:
+		return "text"
+	default:
+		return "other"
+	}
+}`, 4},
+		// Same shape as the type switch; fallthrough joins existing paths
+		{"expression switch", `func f(n int) string {
+	switch n {
+	case 0:
+		fallthrough
+	case 1, 2:
+		return "small"
+	default:
+		return "large"
+	}
+}`, 4},
+		// Each communication case adds 1; default and the select itself add nothing
+		{"select with default", `func f(a, b chan int) int {
+	select {
+	case v := <-a:
+		return v
+	case v := <-b:
+		return v
+	default:
+		return 0
+	}
+}`, 3},
+		// Labeled continue and break leave an already-counted loop
+		{"labeled continue", `func f(xs [][]int) int {
+	total := 0
+outer:
+	for _, row := range xs {
+		for _, x := range row {
+			if x < 0 {
+				continue outer
+			}
+			if x > 100 {
+				break outer
+			}
+			total += x
+		}
+	}
+	return total
+}`, 5},
+	}
+
+	weights := DefaultConfig().ComplexityWeights
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateFunctionComplexity(parseFunc(t, tt.src), weights); got != tt.want {
+				t.Errorf("complexity = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackageComplexityAggregates(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"calc/calc.go": `package calc
+
+func One() int { return 1 }
+
+func Two(n int) int {
+	if n > 0 {
+		return n
+	}
+	return 0
+}
+
+func Four(a, b int) int {
+	for i := 0; i < a; i++ {
+		if i > b && b > 0 {
+			return i
+		}
+	}
+	return 0
+}
+`,
+		"types/types.go": "package types\n\ntype ID string\n",
+	}, nil)
+
+	calc := findPackage(t, report, "calc")
+	if calc.TotalComplexity != 7 || calc.MaxComplexity != 4 {
+		t.Errorf("calc total = %d, max = %d, want 7 and 4", calc.TotalComplexity, calc.MaxComplexity)
+	}
+	if math.Abs(calc.AvgComplexity-7.0/3.0) > 1e-9 {
+		t.Errorf("calc average = %f, want %f", calc.AvgComplexity, 7.0/3.0)
+	}
+
+	// A package without functions has no complexity rather than a division by zero
+	types := findPackage(t, report, "types")
+	if types.AvgComplexity != 0 || types.MaxComplexity != 0 || types.TotalComplexity != 0 {
+		t.Errorf("types aggregates = %f, %d, %d, want zeros", types.AvgComplexity, types.MaxComplexity, types.TotalComplexity)
+	}
+}
+
+func TestFanOutAndFanIn(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.HighFanOutThreshold = 5
+	report := analyzeFixture(t, map[string]string{
+		"app/app.go": `package app
+
+import "fmt"
+
+type Item struct{ ID int }
+
+func h1() int      { return 1 }
+func h2() int      { return 2 }
+func h3(n int) int { return n }
+func h4() []int    { return nil }
+
+// Process calls five distinct functions; repeated calls, builtins, and conversions add nothing
+func Process() {
+	total := h1() + h1() + h2() + h3(int(len(h4())))
+	_ = Item{ID: total}
+	fmt.Println(total)
+}
+
+func Other() int { return h1() }
+`,
+	}, cfg)
+
+	pkg := findPackage(t, report, "app")
+	if got := findFunction(t, pkg, "Process").FanOut; got != 5 {
+		t.Errorf("Process fan-out = %d, want 5", got)
+	}
+	if got := findFunction(t, pkg, "Other").FanOut; got != 1 {
+		t.Errorf("Other fan-out = %d, want 1", got)
+	}
+	// Fan-in (Ca) counts call sites: two in Process, one in Other
+	if got := findFunction(t, pkg, "h1").Afferent; got != 3 {
+		t.Errorf("h1 fan-in = %d, want 3", got)
+	}
+
+	highFanOut := diagnosticsOfType(report, DiagnosticHighFanOut)
+	if len(highFanOut) != 1 || highFanOut[0].TargetName != "app.Process" {
+		t.Errorf("got %+v, want one High Fan-Out diagnostic for app.Process", highFanOut)
+	}
+}
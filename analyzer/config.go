@@ -0,0 +1,475 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConfigFileName is the configuration file discovered at the root of the analyzed project
+const ConfigFileName = ".codehealth.json"
+
+// Complexity weight keys (see Config.ComplexityWeights)
+const (
+	WeightIf              = "if"               // if statement
+	WeightFor             = "for"              // for loop
+	WeightRange           = "range"            // range loop
+	WeightSwitch          = "switch"           // switch statement
+	WeightTypeSwitch      = "type_switch"      // type switch statement
+	WeightCase            = "case"             // non-default case clause
+	WeightSelectCase      = "select_case"      // non-default select case
+	WeightLogicalOperator = "logical_operator" // && or ||
+	WeightGoto            = "goto"             // goto statement
+	WeightNesting         = "nesting"          // extra cost per enclosing if/for/range/switch/select
+)
+
+// External coupling modes (see Config.ExternalCoupling)
+const (
+	ExternalCouplingAll        = "all"        // standard library and third-party imports
+	ExternalCouplingStdlib     = "stdlib"     // standard library imports only
+	ExternalCouplingThirdParty = "thirdparty" // third-party imports only
+)
+
+// LoC counting modes (see Config.LoCMode)
+const (
+	LoCModePhysical   = "physical"   // every line, including comments and blank lines
+	LoCModeSource     = "source"     // lines containing code
+	LoCModeStatements = "statements" // statements in function bodies
+)
+
+// Config holds user-tunable analysis settings
+type Config struct {
+	// ComplexityWeights sets how much each construct adds to a function's complexity.
+	// The defaults (1 per construct, 0 for nesting) reproduce standard cyclomatic complexity.
+	// Raising "nesting" makes nested constructs cost more than flat ones.
+	ComplexityWeights map[string]int `json:"complexity_weights"`
+
+	// CouplingMinLoC and CouplingMinFunctions skip coupling-based diagnostics for packages
+	// smaller than the floor, since tiny utility packages have misleadingly extreme instability.
+	// Zero disables the floor.
+	CouplingMinLoC       int `json:"coupling_min_loc"`
+	CouplingMinFunctions int `json:"coupling_min_functions"`
+
+	// Diagnostic thresholds. A diagnostic fires when the metric is greater than or equal to its threshold.
+	// Zero uses the default, except for the Shotgun Surgery thresholds, where zero disables the check.
+	GodObjectLCOM4                int     `json:"god_object_lcom4"`                // God Object: struct LCOM4
+	GodObjectAfferent             int     `json:"god_object_afferent"`             // God Object: package Ca
+	ComplexFunctionThreshold      int     `json:"complex_function_threshold"`      // Overly Complex Function: complexity
+	UnstableFoundationAfferent    int     `json:"unstable_foundation_afferent"`    // Unstable Foundation: package Ca
+	UnstableFoundationInstability float64 `json:"unstable_foundation_instability"` // Unstable Foundation: package instability
+	ShotgunSurgeryAfferent        int     `json:"shotgun_surgery_afferent"`        // Shotgun Surgery: package Ca
+	ShotgunSurgeryEfferent        int     `json:"shotgun_surgery_efferent"`        // Shotgun Surgery: package Ce
+	AmbiguousStructLCOM4          int     `json:"ambiguous_struct_lcom4"`          // Ambiguous Struct: struct LCOM4
+	AmbiguousStructComplexity     int     `json:"ambiguous_struct_complexity"`     // Ambiguous Struct: complexity of at least one method
+
+	// MagicNumberThreshold is the number of magic numbers in one function that triggers
+	// a "Magic Number" diagnostic. Zero disables the check.
+	MagicNumberThreshold int `json:"magic_number_threshold"`
+
+	// CognitiveComplexityThreshold is the cognitive complexity at which a function gets a
+	// "High Cognitive Complexity" diagnostic. Zero disables the check.
+	CognitiveComplexityThreshold int `json:"cognitive_complexity_threshold"`
+
+	// LongParameterListThreshold is the number of parameters at which a function gets a
+	// "Long Parameter List" diagnostic. Zero disables the check.
+	LongParameterListThreshold int `json:"long_parameter_list_threshold"`
+
+	// SurfaceComplexityThreshold is the surface complexity (parameters plus results plus penalties,
+	// see signature.go) at which an exported function gets an "Awkward API" diagnostic. Zero disables the check.
+	SurfaceComplexityThreshold int `json:"surface_complexity_threshold"`
+
+	// HighFanOutThreshold is the number of distinct functions and methods a function calls at which
+	// it gets a "High Fan-Out" diagnostic. Zero disables the check.
+	HighFanOutThreshold int `json:"high_fan_out_threshold"`
+
+	// LongFunctionThreshold is the number of body lines at which a function gets a
+	// "Long Function" diagnostic. Zero disables the check.
+	LongFunctionThreshold int `json:"long_function_threshold"`
+
+	// LargeStructFields and LargeStructMethods are the field and method counts above which a
+	// struct gets a "Large Struct" diagnostic. Zero disables the respective check.
+	LargeStructFields  int `json:"large_struct_fields"`
+	LargeStructMethods int `json:"large_struct_methods"`
+
+	// WMCThreshold is the Weighted Methods per Class (sum of method complexities) at which a
+	// struct gets a "High Struct Complexity" diagnostic. Zero disables the check.
+	WMCThreshold int `json:"wmc_threshold"`
+
+	// FeatureEnvyMargin is how many more fields of another struct than of its own a method must
+	// read to get a "Feature Envy" diagnostic. Zero disables the check.
+	FeatureEnvyMargin int `json:"feature_envy_margin"`
+
+	// TopOffenders is the length of the ranked list of worst functions, structs, and critical
+	// diagnostics included in the report. Zero leaves the list out.
+	TopOffenders int `json:"top_offenders"`
+
+	// ChurnDays enables git churn: the commits touching each file over this many days are counted,
+	// and the report ranks functions by complexity × churn (as many as TopOffenders).
+	// Zero disables it. Without git or outside a git work tree, the analysis runs without churn.
+	ChurnDays int `json:"churn_days"`
+
+	// Seed seeds the start vectors of the PCA power iteration used for field clustering.
+	// Zero uses a fixed uniform start vector. Results are deterministic for a given seed.
+	Seed int64 `json:"seed"`
+
+	// PerfHints enables heuristic performance diagnostics such as allocations inside loops.
+	// They are opt-in because they are syntactic guesses without escape analysis.
+	PerfHints bool `json:"perf_hints"`
+
+	// IncludeTests measures _test.go files alongside production code. Results from test files
+	// are tagged with IsTest.
+	IncludeTests bool `json:"include_tests"`
+
+	// IncludeGenerated measures files marked "// Code generated ... DO NOT EDIT.", which are
+	// otherwise left out of the metrics (they still count as references).
+	IncludeGenerated bool `json:"include_generated"`
+
+	// TypeCheck type-checks packages to resolve function call targets for afferent coupling.
+	// Packages that fail to type-check keep the AST-only results.
+	TypeCheck bool `json:"typecheck"`
+
+	// ExternalCoupling selects the imports outside the project that count toward a function's
+	// efferent coupling: "all" (the default), "stdlib", or "thirdparty". Imports of project
+	// packages always count. Use "thirdparty" to focus on lock-in to outside modules.
+	ExternalCoupling string `json:"external_coupling"`
+
+	// LoCMode selects how the package total_loc, the report total_loc, and avg_func_loc are
+	// counted: "physical" (the default), "source", or "statements". Statements are counted in
+	// function bodies, not including blocks themselves, for comparison with tools that count them.
+	LoCMode string `json:"loc_mode"`
+
+	// InternalPrefixes lists import path prefixes (e.g. "github.com/org") treated as internal in
+	// addition to the project's own modules, for organizations that split code across module paths.
+	// Imports under them count toward package coupling, dependency depth, and function internal_deps.
+	InternalPrefixes []string `json:"internal_prefixes"`
+
+	// LCOMTransitive connects a method in LCOM4 to the fields used by the same-struct methods
+	// it calls, directly or transitively. It is opt-in because it changes LCOM4 scores.
+	LCOMTransitive bool `json:"lcom_transitive"`
+
+	// Experimental enables best-effort diagnostics that are likely to have false positives,
+	// such as unsynchronized map and struct field access from goroutines.
+	Experimental bool `json:"experimental"`
+
+	// SeveritySLADays maps a severity ("Critical", "Warning", "Info") to the number of days
+	// a diagnostic may stay unfixed. Diagnostics of listed severities get an age and SLA status.
+	SeveritySLADays map[string]int `json:"severity_sla_days"`
+
+	// SeverityOverrides maps a diagnostic type ("Overly Complex Function") to the severity its
+	// diagnostics get instead of the one the detector assigns. Types not listed keep their severity.
+	SeverityOverrides map[string]string `json:"severity_overrides"`
+
+	// ComplexityBudget limits the share of complex functions per package. Nil disables the budget.
+	ComplexityBudget *ComplexityBudget `json:"complexity_budget"`
+
+	// PackageRoles maps package path patterns (matched like .health-ignore patterns, e.g. "domain"
+	// or "internal/infra") to a role, "stable" or "unstable". Packages whose instability contradicts
+	// their role get an "Instability Role Mismatch" diagnostic.
+	PackageRoles map[string]string `json:"package_roles"`
+
+	// IgnorePackages lists package path patterns (matched like .health-ignore patterns, e.g. "legacy"
+	// or "internal/old/**") whose diagnostics are suppressed. The packages are still analyzed and
+	// counted for coupling, so a project can adopt the tool before cleaning up legacy code.
+	IgnorePackages []string `json:"ignore_packages"`
+
+	// Ignore suppresses diagnostics for matching files. It is loaded from IgnoreFileName,
+	// not from the JSON configuration.
+	Ignore *IgnoreList `json:"-"`
+
+	// Arch holds the intended dependency directions between packages. It is loaded from
+	// ArchFileName, not from the JSON configuration. Nil disables layering checks.
+	Arch *ArchRules `json:"-"`
+}
+
+// ComplexityBudget allows at most MaxPercent percent of a package's functions to have a
+// complexity above Threshold, e.g. "no more than 10% of functions may exceed 15"
+type ComplexityBudget struct {
+	Threshold  int     `json:"threshold"`
+	MaxPercent float64 `json:"max_percent"`
+}
+
+// DefaultConfig returns the default configuration
+func DefaultConfig() *Config {
+	return &Config{
+		ComplexityWeights: map[string]int{
+			WeightIf:              1,
+			WeightFor:             1,
+			WeightRange:           1,
+			WeightSwitch:          1,
+			WeightTypeSwitch:      1,
+			WeightCase:            1,
+			WeightSelectCase:      1,
+			WeightLogicalOperator: 1,
+			WeightGoto:            1,
+			WeightNesting:         0,
+		},
+		GodObjectLCOM4:                5,
+		GodObjectAfferent:             10,
+		ComplexFunctionThreshold:      15,
+		UnstableFoundationAfferent:    10,
+		UnstableFoundationInstability: 0.7,
+		ShotgunSurgeryAfferent:        5,
+		ShotgunSurgeryEfferent:        5,
+		AmbiguousStructLCOM4:          3,
+		AmbiguousStructComplexity:     10,
+		CouplingMinLoC:                0,
+		CouplingMinFunctions:          0,
+		MagicNumberThreshold:          5,
+		CognitiveComplexityThreshold:  15,
+		LongParameterListThreshold:    5,
+		HighFanOutThreshold:           20,
+		SurfaceComplexityThreshold:    9,
+		LongFunctionThreshold:         60,
+		LargeStructFields:             15,
+		LargeStructMethods:            20,
+		WMCThreshold:                  50,
+		FeatureEnvyMargin:             2,
+		TopOffenders:                  10,
+		ExternalCoupling:              ExternalCouplingAll,
+		LoCMode:                       LoCModePhysical,
+	}
+}
+
+// withDefaults returns a copy of c with unset settings taken from DefaultConfig: complexity weights
+// missing from the map, the diagnostic thresholds for which zero would flag everything, and the
+// external coupling and LoC modes. Settings where zero disables a check are kept. A nil c
+// returns the defaults.
+func (c *Config) withDefaults() *Config {
+	defaults := DefaultConfig()
+	if c == nil {
+		return defaults
+	}
+
+	cfg := *c
+	cfg.ComplexityWeights = make(map[string]int, len(defaults.ComplexityWeights))
+	for key, weight := range defaults.ComplexityWeights {
+		cfg.ComplexityWeights[key] = weight
+	}
+	for key, weight := range c.ComplexityWeights {
+		cfg.ComplexityWeights[key] = weight
+	}
+
+	fillInt := func(value *int, fallback int) {
+		if *value == 0 {
+			*value = fallback
+		}
+	}
+	fillInt(&cfg.GodObjectLCOM4, defaults.GodObjectLCOM4)
+	fillInt(&cfg.GodObjectAfferent, defaults.GodObjectAfferent)
+	fillInt(&cfg.ComplexFunctionThreshold, defaults.ComplexFunctionThreshold)
+	fillInt(&cfg.UnstableFoundationAfferent, defaults.UnstableFoundationAfferent)
+	fillInt(&cfg.AmbiguousStructLCOM4, defaults.AmbiguousStructLCOM4)
+	fillInt(&cfg.AmbiguousStructComplexity, defaults.AmbiguousStructComplexity)
+	if cfg.UnstableFoundationInstability == 0 {
+		cfg.UnstableFoundationInstability = defaults.UnstableFoundationInstability
+	}
+	if cfg.ExternalCoupling == "" {
+		cfg.ExternalCoupling = defaults.ExternalCoupling
+	}
+	if cfg.LoCMode == "" {
+		cfg.LoCMode = defaults.LoCMode
+	}
+	return &cfg
+}
+
+// LoadConfig loads a configuration file, using defaults for any setting it omits
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := DefaultConfig()
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// DiscoverConfig loads ConfigFileName, IgnoreFileName, and ArchFileName from the project root,
+// using defaults for whichever is absent
+func DiscoverConfig(rootPath string) (*Config, error) {
+	return DiscoverConfigWithFile(rootPath, "")
+}
+
+// DiscoverConfigWithFile is like DiscoverConfig, but loads the configuration from configPath
+// instead of ConfigFileName at the root. An empty configPath falls back to discovery.
+func DiscoverConfigWithFile(rootPath string, configPath string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if configPath != "" {
+		loaded, err := LoadConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg = loaded
+	} else {
+		discovered := filepath.Join(rootPath, ConfigFileName)
+		if _, err := os.Stat(discovered); err == nil {
+			loaded, err := LoadConfig(discovered)
+			if err != nil {
+				return nil, err
+			}
+			cfg = loaded
+		}
+	}
+
+	ignore, err := LoadIgnoreFile(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Ignore = ignore
+
+	arch, err := LoadArchRules(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Arch = arch
+
+	return cfg, nil
+}
+
+// validate checks the configuration for unknown keys and invalid values
+func (c *Config) validate() error {
+	known := DefaultConfig().ComplexityWeights
+
+	var unknown []string
+	for key, weight := range c.ComplexityWeights {
+		if _, ok := known[key]; !ok {
+			unknown = append(unknown, key)
+			continue
+		}
+		if weight < 0 {
+			return fmt.Errorf("complexity weight %q must not be negative", key)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown complexity weight(s): %s", strings.Join(unknown, ", "))
+	}
+
+	if c.CouplingMinLoC < 0 || c.CouplingMinFunctions < 0 {
+		return fmt.Errorf("coupling_min_loc and coupling_min_functions must not be negative")
+	}
+
+	if c.GodObjectLCOM4 < 0 || c.GodObjectAfferent < 0 || c.ComplexFunctionThreshold < 0 ||
+		c.UnstableFoundationAfferent < 0 || c.AmbiguousStructLCOM4 < 0 || c.AmbiguousStructComplexity < 0 ||
+		c.ShotgunSurgeryAfferent < 0 || c.ShotgunSurgeryEfferent < 0 {
+		return fmt.Errorf("diagnostic thresholds must not be negative")
+	}
+
+	if c.UnstableFoundationInstability < 0 || c.UnstableFoundationInstability > 1 {
+		return fmt.Errorf("unstable_foundation_instability must be between 0 and 1")
+	}
+
+	if c.MagicNumberThreshold < 0 {
+		return fmt.Errorf("magic_number_threshold must not be negative")
+	}
+
+	if c.CognitiveComplexityThreshold < 0 {
+		return fmt.Errorf("cognitive_complexity_threshold must not be negative")
+	}
+
+	if c.LongParameterListThreshold < 0 {
+		return fmt.Errorf("long_parameter_list_threshold must not be negative")
+	}
+
+	if c.SurfaceComplexityThreshold < 0 {
+		return fmt.Errorf("surface_complexity_threshold must not be negative")
+	}
+
+	if c.HighFanOutThreshold < 0 {
+		return fmt.Errorf("high_fan_out_threshold must not be negative")
+	}
+
+	if c.LongFunctionThreshold < 0 {
+		return fmt.Errorf("long_function_threshold must not be negative")
+	}
+
+	if c.LargeStructFields < 0 || c.LargeStructMethods < 0 {
+		return fmt.Errorf("large_struct_fields and large_struct_methods must not be negative")
+	}
+
+	if c.WMCThreshold < 0 {
+		return fmt.Errorf("wmc_threshold must not be negative")
+	}
+
+	if c.FeatureEnvyMargin < 0 {
+		return fmt.Errorf("feature_envy_margin must not be negative")
+	}
+
+	if c.TopOffenders < 0 {
+		return fmt.Errorf("top_offenders must not be negative")
+	}
+
+	switch c.ExternalCoupling {
+	case "", ExternalCouplingAll, ExternalCouplingStdlib, ExternalCouplingThirdParty:
+	default:
+		return fmt.Errorf("external_coupling must be all, stdlib, or thirdparty, got %q", c.ExternalCoupling)
+	}
+
+	switch c.LoCMode {
+	case "", LoCModePhysical, LoCModeSource, LoCModeStatements:
+	default:
+		return fmt.Errorf("loc_mode must be physical, source, or statements, got %q", c.LoCMode)
+	}
+
+	if c.ChurnDays < 0 {
+		return fmt.Errorf("churn_days must not be negative")
+	}
+
+	for severity, days := range c.SeveritySLADays {
+		if severity != "Critical" && severity != "Warning" && severity != "Info" {
+			return fmt.Errorf("unknown severity %q in severity_sla_days", severity)
+		}
+		if days < 0 {
+			return fmt.Errorf("severity_sla_days for %q must not be negative", severity)
+		}
+	}
+
+	for diagnosticType, severity := range c.SeverityOverrides {
+		if _, ok := evidenceTypes[diagnosticType]; !ok {
+			return fmt.Errorf("unknown diagnostic type %q in severity_overrides", diagnosticType)
+		}
+		if severity != "Critical" && severity != "Warning" && severity != "Info" {
+			return fmt.Errorf("severity_overrides for %q must be Critical, Warning, or Info, got %q", diagnosticType, severity)
+		}
+	}
+
+	if b := c.ComplexityBudget; b != nil {
+		if b.Threshold <= 0 {
+			return fmt.Errorf("complexity_budget.threshold must be positive")
+		}
+		if b.MaxPercent < 0 || b.MaxPercent > 100 {
+			return fmt.Errorf("complexity_budget.max_percent must be between 0 and 100")
+		}
+	}
+
+	for _, prefix := range c.InternalPrefixes {
+		if strings.Trim(prefix, "/") == "" {
+			return fmt.Errorf("internal_prefixes must not contain empty prefixes")
+		}
+	}
+
+	for _, pattern := range c.IgnorePackages {
+		if _, err := path.Match(strings.Trim(pattern, "/"), ""); err != nil {
+			return fmt.Errorf("ignore_packages: invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	return c.validateRoles()
+}
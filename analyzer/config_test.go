@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadConfigJSON writes content to a config file and loads it
+func loadConfigJSON(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadConfig(path)
+}
+
+func TestSeverityOverrides(t *testing.T) {
+	cfg, err := loadConfigJSON(t, `{"complex_function_threshold": 2, "severity_overrides": {"Overly Complex Function": "Critical"}}`)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	report := analyzeFixture(t, map[string]string{
+		"app/app.go": "package app\n\nfunc Check(n int) bool {\n\tif n > 0 {\n\t\treturn true\n\t}\n\treturn false\n}\n",
+	}, cfg)
+
+	flagged := diagnosticsOfType(report, DiagnosticComplexFunction)
+	if len(flagged) != 1 || flagged[0].Severity != "Critical" {
+		t.Errorf("got %+v, want one Critical Overly Complex Function diagnostic", flagged)
+	}
+}
+
+func TestSeverityOverridesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown type", `{"severity_overrides": {"Too Clever": "Critical"}}`, `unknown diagnostic type "Too Clever"`},
+		{"unknown severity", `{"severity_overrides": {"Overly Complex Function": "Fatal"}}`, "must be Critical, Warning, or Info"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadConfigJSON(t, tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to mention %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPartialConfigUsesDefaults(t *testing.T) {
+	src := `package app
+
+func Branchy(n int) int {
+	if n > 0 {
+		return 1
+	}
+	for i := 0; i < n; i++ {
+		n--
+	}
+	return n
+}
+`
+	// A literal Config without weights or thresholds, as an API caller might build it
+	report := analyzeFixture(t, map[string]string{"app/app.go": src}, &Config{TopOffenders: 3})
+
+	if got := findFunction(t, findPackage(t, report, "app"), "Branchy").Complexity; got != 3 {
+		t.Errorf("complexity = %d, want 3 with the default weights", got)
+	}
+	if flagged := diagnosticsOfType(report, DiagnosticComplexFunction); len(flagged) != 0 {
+		t.Errorf("got %d Overly Complex Function diagnostics, want none at the default threshold", len(flagged))
+	}
+
+	// Set weights are kept and missing ones come from the defaults
+	cfg := (&Config{ComplexityWeights: map[string]int{WeightIf: 5}}).withDefaults()
+	if cfg.ComplexityWeights[WeightIf] != 5 || cfg.ComplexityWeights[WeightFor] != 1 {
+		t.Errorf("weights = %v, want if 5 and for 1", cfg.ComplexityWeights)
+	}
+	if cfg.ComplexFunctionThreshold != DefaultConfig().ComplexFunctionThreshold || cfg.LoCMode != LoCModePhysical {
+		t.Errorf("threshold = %d, loc mode = %q, want the defaults", cfg.ComplexFunctionThreshold, cfg.LoCMode)
+	}
+	// Zero keeps a check disabled
+	if cfg.MagicNumberThreshold != 0 {
+		t.Errorf("magic number threshold = %d, want 0 (disabled)", cfg.MagicNumberThreshold)
+	}
+
+	// The exported calculators treat a nil configuration as the defaults
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "app.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := &ast.Package{Name: "app", Files: map[string]*ast.File{"app.go": file}}
+	if results := CalculateComplexity(pkg, fset, nil, nil); len(results) != 1 || results[0].Complexity != 3 {
+		t.Errorf("CalculateComplexity with a nil config = %+v, want Branchy with complexity 3", results)
+	}
+	CalculateLCOM4(pkg, fset, nil)
+}
@@ -2,18 +2,21 @@ package analyzer
 
 import (
 	"go/ast"
+	"sort"
 	"strings"
 )
 
 // PackageDependency holds dependency information for packages
 type PackageDependency struct {
-	PkgPath      string
-	Imports      []string // Packages this package imports
-	ImportedBy   []string // Packages that import this package
+	PkgPath       string
+	Imports       []string       // Packages this package imports
+	ImportedBy    []string       // Packages that import this package
+	ImportWeights map[string]int // Distinct selectors (pkg.Name) referenced through each import
 }
 
-// CalculateCoupling calculates coupling metrics for packages
-func CalculateCoupling(pkgDeps map[string]*PackageDependency, projectPrefix string) map[string]CouplingMetrics {
+// CalculateCoupling calculates coupling metrics for packages.
+// Imports under any of the internal prefixes (the project module and locally replaced modules) count as coupling.
+func CalculateCoupling(pkgDeps map[string]*PackageDependency, internalPrefixes []string) map[string]CouplingMetrics {
 	metrics := make(map[string]CouplingMetrics)
 
 	for pkgPath, dep := range pkgDeps {
@@ -23,14 +26,14 @@ func CalculateCoupling(pkgDeps map[string]*PackageDependency, projectPrefix stri
 
 		// Count packages that depend on this package (Ca)
 		for _, importingPkg := range dep.ImportedBy {
-			if strings.HasPrefix(importingPkg, projectPrefix) {
+			if isInternalImport(importingPkg, internalPrefixes) {
 				ca++
 			}
 		}
 
 		// Count packages this package depends on (Ce)
 		for _, importedPkg := range dep.Imports {
-			if strings.HasPrefix(importedPkg, projectPrefix) {
+			if isInternalImport(importedPkg, internalPrefixes) {
 				ce++
 			}
 		}
@@ -51,6 +54,83 @@ func CalculateCoupling(pkgDeps map[string]*PackageDependency, projectPrefix stri
 	return metrics
 }
 
+// CalculateWeightedInstability calculates instability with each internal import edge weighted by
+// how much of the imported package is used: the number of distinct selectors (pkg.Func, pkg.Type,
+// pkg.Var) the importing package references through the import, at least 1 per import.
+// Weighted Ce sums the weights of a package's imports and weighted Ca the weights of the imports of it,
+// so a package leaned on heavily by one importer weighs more than one touched lightly by several.
+// The result is Ce / (Ca + Ce) over the weighted counts (0 without internal imports either way).
+func CalculateWeightedInstability(pkgDeps map[string]*PackageDependency, internalPrefixes []string) map[string]float64 {
+	fullToRelPath := make(map[string]string)
+	for pkgPath, dep := range pkgDeps {
+		fullToRelPath[dep.PkgPath] = pkgPath
+	}
+
+	weightedCa := make(map[string]int)
+	weightedCe := make(map[string]int)
+	for pkgPath, dep := range pkgDeps {
+		for _, importedPkg := range dep.Imports {
+			if !isInternalImport(importedPkg, internalPrefixes) {
+				continue
+			}
+
+			weight := dep.ImportWeights[importedPkg]
+			if weight < 1 {
+				weight = 1
+			}
+			weightedCe[pkgPath] += weight
+			if relPath, exists := fullToRelPath[importedPkg]; exists {
+				weightedCa[relPath] += weight
+			}
+		}
+	}
+
+	instability := make(map[string]float64)
+	for pkgPath := range pkgDeps {
+		ca, ce := weightedCa[pkgPath], weightedCe[pkgPath]
+		if ca+ce > 0 {
+			instability[pkgPath] = float64(ce) / float64(ca+ce)
+		}
+	}
+	return instability
+}
+
+// countImportUsage counts, for each import path, the distinct selectors a package references
+// through it. Identifiers bound to a local declaration (a variable shadowing an import) are skipped.
+func countImportUsage(pkg *ast.Package) map[string]int {
+	selectors := make(map[string]map[string]bool)
+
+	for _, file := range pkg.Files {
+		fileImports := buildFileImportMap(file)
+		ast.Inspect(file, func(n ast.Node) bool {
+			selector, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			ident, ok := selector.X.(*ast.Ident)
+			if !ok || ident.Obj != nil {
+				return true
+			}
+
+			importPath, exists := fileImports[ident.Name]
+			if !exists {
+				return true
+			}
+			if selectors[importPath] == nil {
+				selectors[importPath] = make(map[string]bool)
+			}
+			selectors[importPath][selector.Sel.Name] = true
+			return true
+		})
+	}
+
+	usage := make(map[string]int, len(selectors))
+	for importPath, names := range selectors {
+		usage[importPath] = len(names)
+	}
+	return usage
+}
+
 // CouplingMetrics holds coupling metrics for a package
 type CouplingMetrics struct {
 	Afferent    int
@@ -70,29 +150,26 @@ func ExtractImports(pkg *ast.Package) []string {
 		}
 	}
 
-	// Convert map to slice
+	// Convert map to slice, sorted so dependency walks visit imports in a fixed order
 	var imports []string
 	for imp := range importsMap {
 		imports = append(imports, imp)
 	}
+	sort.Strings(imports)
 
 	return imports
 }
 
 // CalculateDependencyDepth calculates the maximum depth of the internal dependency chain for each package
-func CalculateDependencyDepth(pkgDeps map[string]*PackageDependency, projectPrefix string) map[string]int {
+func CalculateDependencyDepth(pkgDeps map[string]*PackageDependency, internalPrefixes []string) map[string]int {
 	depths := make(map[string]int)
 	visited := make(map[string]bool)
 	inProgress := make(map[string]bool)
 
 	// Create mapping from full import path to relative path
 	fullToRelPath := make(map[string]string)
-	for pkgPath := range pkgDeps {
-		fullPath := projectPrefix
-		if pkgPath != "" {
-			fullPath = projectPrefix + "/" + pkgPath
-		}
-		fullToRelPath[fullPath] = pkgPath
+	for pkgPath, dep := range pkgDeps {
+		fullToRelPath[dep.PkgPath] = pkgPath
 	}
 
 	// DFS to calculate depth for each package
@@ -116,7 +193,7 @@ func CalculateDependencyDepth(pkgDeps map[string]*PackageDependency, projectPref
 		if dep != nil {
 			// Only consider internal dependencies (within the project)
 			for _, importPath := range dep.Imports {
-				if strings.HasPrefix(importPath, projectPrefix) {
+				if isInternalImport(importPath, internalPrefixes) {
 					// Convert full import path to relative path
 					if relPath, exists := fullToRelPath[importPath]; exists {
 						childDepth := dfs(relPath)
@@ -141,8 +218,14 @@ func CalculateDependencyDepth(pkgDeps map[string]*PackageDependency, projectPref
 		return depths[pkgPath]
 	}
 
-	// Calculate depth for each package
+	// Calculate depth for each package. Within an import cycle the depths depend on where the
+	// walk enters it, so packages are visited in path order to give the same depths every run.
+	pkgPaths := make([]string, 0, len(pkgDeps))
 	for pkgPath := range pkgDeps {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+	for _, pkgPath := range pkgPaths {
 		if !visited[pkgPath] {
 			dfs(pkgPath)
 		}
@@ -150,3 +233,13 @@ func CalculateDependencyDepth(pkgDeps map[string]*PackageDependency, projectPref
 
 	return depths
 }
+
+// isInternalImport reports whether an import path is one of the internal prefixes or a package below one
+func isInternalImport(importPath string, internalPrefixes []string) bool {
+	for _, prefix := range internalPrefixes {
+		if importPath == prefix || strings.HasPrefix(importPath, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWeightedInstabilityByUsage(t *testing.T) {
+	// heavy and light each import base and are imported once (plain I = 0.5 for both);
+	// heavy uses much of base and is used lightly, light the other way around
+	report := analyzeFixture(t, map[string]string{
+		"base/base.go": "package base\n\nfunc F1() {}\n\nfunc F2() {}\n\nfunc F3() {}\n",
+		"heavy/heavy.go": `package heavy
+
+import "example.com/app/base"
+
+func One() { base.F1(); base.F2(); base.F3() }
+`,
+		"light/light.go": `package light
+
+import "example.com/app/base"
+
+func One() { base.F1() }
+
+func Two() {}
+
+func Three() {}
+`,
+		"useheavy/useheavy.go": "package useheavy\n\nimport \"example.com/app/heavy\"\n\nfunc Run() { heavy.One() }\n",
+		"uselight/uselight.go": "package uselight\n\nimport \"example.com/app/light\"\n\nfunc Run() { light.One(); light.Two(); light.Three() }\n",
+	}, nil)
+
+	tests := []struct {
+		pkg         string
+		instability float64
+		weighted    float64
+	}{
+		{"heavy", 0.5, 0.75}, // weighted Ce 3, Ca 1
+		{"light", 0.5, 0.25}, // weighted Ce 1, Ca 3
+		{"base", 0, 0},       // imported only
+	}
+	for _, tt := range tests {
+		pkg := findPackage(t, report, tt.pkg)
+		if math.Abs(pkg.Instability-tt.instability) > 1e-9 {
+			t.Errorf("%s Instability = %f, want %f", tt.pkg, pkg.Instability, tt.instability)
+		}
+		if math.Abs(pkg.WeightedInstability-tt.weighted) > 1e-9 {
+			t.Errorf("%s WeightedInstability = %f, want %f", tt.pkg, pkg.WeightedInstability, tt.weighted)
+		}
+	}
+}
+
+func TestDetectShotgunSurgery(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CouplingMinFunctions = 3
+	packages := []PackageResult{
+		// Used by six packages and depends on nothing: a stable foundation
+		{Name: "model", Path: "model", Afferent: 6, Efferent: 0, Instability: 0},
+		// Used by six packages and depends on six others: changes ripple through it
+		{Name: "service", Path: "service", Afferent: 6, Efferent: 6, Instability: 0.5},
+		// Depends on six packages but few use it
+		{Name: "cmd", Path: "cmd", Afferent: 1, Efferent: 6, Instability: 6.0 / 7.0},
+	}
+	for i := 0; i < 6; i++ {
+		packages = append(packages, PackageResult{
+			Name:            "user",
+			Path:            string(rune('a'+i)) + "/user",
+			InternalImports: []string{"model", "service", "service"},
+		})
+	}
+	for i := range packages {
+		packages[i].FuncCount = 3
+	}
+
+	results := detectShotgunSurgery(packages, cfg)
+	if len(results) != 1 || results[0].TargetName != "service" {
+		t.Fatalf("got %+v, want one diagnostic for service", results)
+	}
+	evidence, ok := results[0].Evidence.(ShotgunSurgeryEvidence)
+	if !ok {
+		t.Fatalf("evidence is %T, want ShotgunSurgeryEvidence", results[0].Evidence)
+	}
+	if len(evidence.Dependents) != 6 || evidence.Dependents[0] != "a/user" {
+		t.Errorf("dependents = %v, want the six user packages, sorted", evidence.Dependents)
+	}
+	if want := "is used by 6 packages (Ca) and depends on 6 packages itself (Ce)"; !strings.Contains(results[0].Message, want) {
+		t.Errorf("message = %q, want it to contain %q", results[0].Message, want)
+	}
+
+	// Small packages are below the coupling floor
+	packages[1].FuncCount = 2
+	if results := detectShotgunSurgery(packages, cfg); len(results) != 0 {
+		t.Errorf("package below the size floor was reported: %+v", results)
+	}
+}
+
+func TestExternalCouplingModes(t *testing.T) {
+	files := map[string]string{
+		"util/util.go": "package util\n\nfunc Clean(s string) string { return s }\n",
+		"app/app.go": `package app
+
+import (
+	"fmt"
+	"strings"
+
+	"example.com/app/util"
+	"github.com/google/uuid"
+)
+
+func Describe(s string) string {
+	return fmt.Sprint(strings.ToUpper(util.Clean(s)), uuid.NewString())
+}
+`,
+	}
+
+	tests := []struct {
+		mode string
+		want int
+	}{
+		{ExternalCouplingAll, 4},        // util, fmt, strings, uuid
+		{ExternalCouplingStdlib, 3},     // util, fmt, strings
+		{ExternalCouplingThirdParty, 2}, // util, uuid
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ExternalCoupling = tt.mode
+			report := analyzeFixture(t, files, cfg)
+			if got := findFunction(t, findPackage(t, report, "app"), "Describe").Efferent; got != tt.want {
+				t.Errorf("efferent = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInternalPrefixes(t *testing.T) {
+	files := map[string]string{
+		"app/app.go": `package app
+
+import (
+	"strings"
+
+	"github.com/org/other"
+	"github.com/vendor/lib"
+)
+
+func Run(s string) string { return other.Wrap(lib.Clean(strings.TrimSpace(s))) }
+`,
+	}
+
+	without := findPackage(t, analyzeFixture(t, files, nil), "app")
+	if without.Efferent != 0 {
+		t.Errorf("without prefixes: Ce = %d, want 0 (all imports external)", without.Efferent)
+	}
+
+	cfg := DefaultConfig()
+	cfg.InternalPrefixes = []string{"github.com/org/"}
+	with := findPackage(t, analyzeFixture(t, files, cfg), "app")
+	if with.Efferent != 1 {
+		t.Errorf("with github.com/org/: Ce = %d, want 1 (github.com/org/other internal)", with.Efferent)
+	}
+
+	internal, external := CategorizeDependencies([]string{"github.com/org/other", "github.com/vendor/lib", "strings"}, []string{"github.com/org"})
+	if len(internal) != 1 || internal[0] != "github.com/org/other" || len(external) != 2 {
+		t.Errorf("internal = %v, external = %v, want only github.com/org/other internal", internal, external)
+	}
+	if internal, _ := CategorizeDependencies([]string{"github.com/organization/x"}, []string{"github.com/org"}); len(internal) != 0 {
+		t.Errorf("prefix github.com/org matched github.com/organization/x")
+	}
+}
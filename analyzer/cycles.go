@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// DetectCycles returns the import cycles among the project's packages: each strongly connected
+// component of the internal dependency graph with more than one package (Tarjan's algorithm).
+// Members of each cycle are sorted, and cycles are ordered by their first member.
+// Self-imports are ignored; the root package is keyed by "".
+func DetectCycles(pkgDeps map[string]*PackageDependency, modules projectModules) [][]string {
+	return stronglyConnectedComponents(internalImportGraph(pkgDeps, modules))
+}
+
+// stronglyConnectedComponents returns the components of a directed graph with more than one
+// node (Tarjan's algorithm). Nodes of each component are sorted, and components are ordered by
+// their first node. Self-edges do not form a component on their own.
+func stronglyConnectedComponents(graph map[string][]string) [][]string {
+	nodes := make([]string, 0, len(graph))
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	index := make(map[string]int)
+	lowLink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	next := 0
+
+	var strongConnect func(node string)
+	strongConnect = func(node string) {
+		index[node] = next
+		lowLink[node] = next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, target := range graph[node] {
+			if _, seen := index[target]; !seen {
+				strongConnect(target)
+				lowLink[node] = min(lowLink[node], lowLink[target])
+			} else if onStack[target] {
+				lowLink[node] = min(lowLink[node], index[target])
+			}
+		}
+
+		// node is the root of a component: pop it off the stack
+		if lowLink[node] == index[node] {
+			var component []string
+			for {
+				member := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[member] = false
+				component = append(component, member)
+				if member == node {
+					break
+				}
+			}
+			if len(component) > 1 {
+				sort.Strings(component)
+				components = append(components, component)
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		if _, seen := index[node]; !seen {
+			strongConnect(node)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+	return components
+}
+
+// importRing returns a shortest import path from the first member of a cycle back to itself,
+// e.g. [a b c a], staying within the cycle's packages
+func importRing(cycle []string, pkgDeps map[string]*PackageDependency, modules projectModules) []string {
+	graph := internalImportGraph(pkgDeps, modules)
+	members := make(map[string]bool)
+	for _, pkgPath := range cycle {
+		members[pkgPath] = true
+	}
+
+	start := cycle[0]
+	previous := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, imported := range graph[current] {
+			if !members[imported] {
+				continue
+			}
+			if imported == start {
+				// Walk back from current to start
+				ring := []string{start}
+				for node := current; node != start; node = previous[node] {
+					ring = append(ring, node)
+				}
+				ring = append(ring, start)
+				// Reverse the middle so the ring reads in import order
+				for i, j := 1, len(ring)-2; i < j; i, j = i+1, j-1 {
+					ring[i], ring[j] = ring[j], ring[i]
+				}
+				return ring
+			}
+			if _, seen := previous[imported]; !seen {
+				previous[imported] = current
+				queue = append(queue, imported)
+			}
+		}
+	}
+
+	return cycle
+}
+
+// internalImportGraph maps each package to the project packages it imports, excluding itself
+func internalImportGraph(pkgDeps map[string]*PackageDependency, modules projectModules) map[string][]string {
+	graph := make(map[string][]string)
+	for pkgPath, dep := range pkgDeps {
+		var imports []string
+		for _, imported := range resolveInternalImports(pkgDeps, dep.Imports, modules) {
+			if imported != pkgPath {
+				imports = append(imports, imported)
+			}
+		}
+		graph[pkgPath] = imports
+	}
+	return graph
+}
+
+// displayPackagePath returns a package path for messages ("." for the root package)
+func displayPackagePath(pkgPath string) string {
+	if pkgPath == "" {
+		return "."
+	}
+	return pkgPath
+}
+
+// formatImportRing joins a ring of package paths with arrows
+func formatImportRing(ring []string) string {
+	labels := make([]string, len(ring))
+	for i, pkgPath := range ring {
+		labels[i] = displayPackagePath(pkgPath)
+	}
+	return strings.Join(labels, " -> ")
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph map[string][]string
+		want  [][]string
+	}{
+		{"acyclic", map[string][]string{"a": {"b"}, "b": {"c"}, "c": nil}, nil},
+		{"two packages", map[string][]string{"a": {"b"}, "b": {"a"}}, [][]string{{"a", "b"}}},
+		{"three packages", map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}, "d": {"a"}}, [][]string{{"a", "b", "c"}}},
+		{"self edge", map[string][]string{"a": {"a"}}, nil},
+		{"root package", map[string][]string{"": {"util"}, "util": {""}}, [][]string{{"", "util"}}},
+		{"separate cycles", map[string][]string{"x": {"y"}, "y": {"x"}, "a": {"b"}, "b": {"a"}}, [][]string{{"a", "b"}, {"x", "y"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stronglyConnectedComponents(tt.graph); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("components = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectCyclicDependencies(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		// Two-package cycle through the root package
+		"root.go":      "package app\n\nimport \"example.com/app/util\"\n\nfunc Root() int { return util.U() }\n",
+		"util/util.go": "package util\n\nimport app \"example.com/app\"\n\nfunc U() int { return app.Root() }\n",
+		// Three-package cycle
+		"a/a.go": "package a\n\nimport \"example.com/app/b\"\n\nfunc A() { b.B() }\n",
+		"b/b.go": "package b\n\nimport \"example.com/app/c\"\n\nfunc B() { c.C() }\n",
+		"c/c.go": "package c\n\nimport \"example.com/app/a\"\n\nfunc C() { a.A() }\n",
+		// A self-import is not a cycle
+		"self/self.go": "package self\n\nimport _ \"example.com/app/self\"\n\nfunc S() {}\n",
+	}, nil)
+
+	cycles := diagnosticsOfType(report, DiagnosticCyclicDependency)
+	if len(cycles) != 2 {
+		t.Fatalf("got %d cycle diagnostics, want 2: %+v", len(cycles), cycles)
+	}
+
+	want := []struct {
+		packages []string
+		ring     []string
+	}{
+		{[]string{"", "util"}, []string{"", "util", ""}},
+		{[]string{"a", "b", "c"}, []string{"a", "b", "c", "a"}},
+	}
+	for i, d := range cycles {
+		evidence := d.Evidence.(CyclicDependencyEvidence)
+		if d.Severity != "Critical" {
+			t.Errorf("cycle %d severity = %s, want Critical", i, d.Severity)
+		}
+		if !reflect.DeepEqual(evidence.Packages, want[i].packages) || !reflect.DeepEqual(evidence.Ring, want[i].ring) {
+			t.Errorf("cycle %d = %q ring %q, want %q ring %q", i, evidence.Packages, evidence.Ring, want[i].packages, want[i].ring)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Data clump thresholds: a run of at least dataClumpMinParams parameters that recurs,
+// with the same names and types in the same order, in at least dataClumpMinFunctions functions
+const (
+	dataClumpMinParams    = 3
+	dataClumpMinFunctions = 3
+)
+
+// dataClump is a parameter run shared by several functions
+type dataClump struct {
+	Params    []Param
+	Functions []dataClumpMember
+}
+
+// dataClumpMember is a function taking a data clump
+type dataClumpMember struct {
+	Package  PackageResult
+	Function FunctionResult
+}
+
+// extractParams returns the name and normalized type of each parameter of a function.
+// Grouped parameters such as (a, b int) yield one entry per name; unnamed parameters have an empty name.
+func extractParams(funcDecl *ast.FuncDecl, pkgName string, fileImports map[string]string) []Param {
+	if funcDecl.Type.Params == nil {
+		return nil
+	}
+
+	typeParams := make(map[string]bool)
+	if funcDecl.Type.TypeParams != nil {
+		for _, field := range funcDecl.Type.TypeParams.List {
+			for _, name := range field.Names {
+				typeParams[name.Name] = true
+			}
+		}
+	}
+
+	var params []Param
+	for _, field := range funcDecl.Type.Params.List {
+		typeName := normalizeTypeName(field.Type, pkgName, fileImports, typeParams)
+		if len(field.Names) == 0 {
+			params = append(params, Param{Type: typeName})
+			continue
+		}
+		for _, name := range field.Names {
+			params = append(params, Param{Name: name.Name, Type: typeName})
+		}
+	}
+	return params
+}
+
+// normalizeTypeName writes a parameter type so the same type reads the same in every file:
+// qualified identifiers use the last element of the import path instead of the file's import alias,
+// and the package's own named types are qualified with the package name. Predeclared types and
+// type parameters are left as written.
+func normalizeTypeName(expr ast.Expr, pkgName string, fileImports map[string]string, typeParams map[string]bool) string {
+	normalize := func(e ast.Expr) string {
+		return normalizeTypeName(e, pkgName, fileImports, typeParams)
+	}
+
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if typeParams[t.Name] || types.Universe.Lookup(t.Name) != nil {
+			return t.Name
+		}
+		return pkgName + "." + t.Name
+	case *ast.SelectorExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			if importPath, ok := fileImports[ident.Name]; ok {
+				return path.Base(importPath) + "." + t.Sel.Name
+			}
+		}
+		return types.ExprString(t)
+	case *ast.StarExpr:
+		return "*" + normalize(t.X)
+	case *ast.Ellipsis:
+		return "..." + normalize(t.Elt)
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return "[]" + normalize(t.Elt)
+		}
+		return "[" + types.ExprString(t.Len) + "]" + normalize(t.Elt)
+	case *ast.MapType:
+		return "map[" + normalize(t.Key) + "]" + normalize(t.Value)
+	case *ast.ChanType:
+		switch t.Dir {
+		case ast.SEND:
+			return "chan<- " + normalize(t.Value)
+		case ast.RECV:
+			return "<-chan " + normalize(t.Value)
+		default:
+			return "chan " + normalize(t.Value)
+		}
+	case *ast.IndexExpr:
+		return normalize(t.X) + "[" + normalize(t.Index) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, len(t.Indices))
+		for i, index := range t.Indices {
+			args[i] = normalize(index)
+		}
+		return normalize(t.X) + "[" + strings.Join(args, ", ") + "]"
+	case *ast.ParenExpr:
+		return normalize(t.X)
+	default:
+		// Function, struct, and interface literals are compared as written
+		return types.ExprString(expr)
+	}
+}
+
+// findDataClumps returns the parameter runs that recur across functions, largest first.
+// Every contiguous run of named parameters is keyed by its ordered names and types;
+// a run is dropped if a longer clump contains it and is shared by the same functions.
+// Test functions are not considered.
+func findDataClumps(packages []PackageResult) []dataClump {
+	runs := make(map[string]*dataClump)
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.IsTest {
+				continue
+			}
+
+			seen := make(map[string]bool)
+			for start := 0; start < len(f.Params); start++ {
+				for end := start + dataClumpMinParams; end <= len(f.Params); end++ {
+					run := f.Params[start:end]
+					if !namedParams(run) {
+						break
+					}
+
+					key := paramsKey(run)
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+
+					clump, ok := runs[key]
+					if !ok {
+						clump = &dataClump{Params: run}
+						runs[key] = clump
+					}
+					clump.Functions = append(clump.Functions, dataClumpMember{Package: pkg, Function: f})
+				}
+			}
+		}
+	}
+
+	var candidates []*dataClump
+	for _, clump := range runs {
+		if len(clump.Functions) >= dataClumpMinFunctions {
+			candidates = append(candidates, clump)
+		}
+	}
+
+	// Largest clumps first, so subsumed runs can be dropped in one pass
+	sort.Slice(candidates, func(i, j int) bool {
+		if len(candidates[i].Params) != len(candidates[j].Params) {
+			return len(candidates[i].Params) > len(candidates[j].Params)
+		}
+		return paramsKey(candidates[i].Params) < paramsKey(candidates[j].Params)
+	})
+
+	var clumps []dataClump
+	for _, candidate := range candidates {
+		subsumed := false
+		for _, kept := range clumps {
+			if len(kept.Functions) == len(candidate.Functions) && containsParamRun(kept.Params, candidate.Params) {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			clumps = append(clumps, *candidate)
+		}
+	}
+
+	return clumps
+}
+
+// namedParams reports whether every parameter of a run has a real name (not blank or unnamed)
+func namedParams(params []Param) bool {
+	for _, p := range params {
+		if p.Name == "" || p.Name == "_" {
+			return false
+		}
+	}
+	return true
+}
+
+// paramsKey returns the ordered names and types of a parameter run, as written in a signature
+func paramsKey(params []Param) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = p.Name + " " + p.Type
+	}
+	return strings.Join(parts, ", ")
+}
+
+// containsParamRun reports whether run appears contiguously within params
+func containsParamRun(params []Param, run []Param) bool {
+	for start := 0; start+len(run) <= len(params); start++ {
+		match := true
+		for i := range run {
+			if params[start+i] != run[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDataClumpDiagnostic(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"users/users.go": `package users
+
+func Create(name string, age int, email string) {}
+
+func Update(id int, name string, age int, email string) {}
+
+// Only two functions share (id int, name string, age int)
+func Rename(id int, name string, age int) {}
+`,
+		"mail/mail.go": `package mail
+
+func Invite(name string, age int, email string, admin bool) {}
+
+// Same types, different names: not the same data
+func Notify(title string, count int, to string) {}
+`,
+	}, nil)
+
+	clumps := diagnosticsOfType(report, DiagnosticDataClump)
+	if len(clumps) != 1 {
+		t.Fatalf("got %d data clump diagnostics, want 1: %+v", len(clumps), clumps)
+	}
+	if clumps[0].TargetName != "(name string, age int, email string)" {
+		t.Errorf("TargetName = %q", clumps[0].TargetName)
+	}
+	evidence, ok := clumps[0].Evidence.(DataClumpEvidence)
+	if !ok {
+		t.Fatalf("evidence is %T, want DataClumpEvidence", clumps[0].Evidence)
+	}
+	wantFunctions := []string{"mail.Invite", "users.Create", "users.Update"}
+	if !reflect.DeepEqual(evidence.Functions, wantFunctions) {
+		t.Errorf("functions = %v, want %v", evidence.Functions, wantFunctions)
+	}
+}
+
+func TestDataClumpNormalizesQualifiedTypes(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"a/a.go": "package a\n\nimport \"time\"\n\nfunc A(start time.Time, end time.Time, loc *time.Location) {}\n",
+		"b/b.go": "package b\n\nimport t \"time\"\n\nfunc B(start t.Time, end t.Time, loc *t.Location) {}\n",
+		"c/c.go": "package c\n\nimport (\n\tclock \"time\"\n)\n\nfunc C(start, end clock.Time, loc *clock.Location) {}\n",
+	}, nil)
+
+	clumps := diagnosticsOfType(report, DiagnosticDataClump)
+	if len(clumps) != 1 {
+		t.Fatalf("got %d data clump diagnostics, want 1 across the import aliases: %+v", len(clumps), clumps)
+	}
+	if clumps[0].TargetName != "(start time.Time, end time.Time, loc *time.Location)" {
+		t.Errorf("TargetName = %q", clumps[0].TargetName)
+	}
+}
@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// MarkUnreferencedFunctions sets Unreferenced on unexported functions and methods that no other
+// declaration in the package (or its unmeasured test and generated files) mentions. Any identifier use counts as a
+// reference, not only calls, so functions passed as values (handler := doThing) stay referenced.
+// Matching is name-based: a method T.m is referenced by any use of m.
+// init, main, and functions in _test.go files are never marked.
+func MarkUnreferencedFunctions(functions []FunctionResult, pkg *ast.Package, testFiles map[string]*ast.File) {
+	// Names referenced by each top-level declaration, excluding the name it declares
+	var declRefs []map[string]bool
+	declIndex := make(map[string]int) // qualified function name -> index in declRefs
+	collect := func(file *ast.File, measured bool) {
+		for _, decl := range file.Decls {
+			refs := make(map[string]bool)
+			funcDecl, isFunc := decl.(*ast.FuncDecl)
+			ast.Inspect(decl, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && !(isFunc && ident == funcDecl.Name) {
+					refs[ident.Name] = true
+				}
+				return true
+			})
+			if isFunc && measured {
+				declIndex[qualifiedFuncName(funcDecl)] = len(declRefs)
+			}
+			declRefs = append(declRefs, refs)
+		}
+	}
+	for _, file := range pkg.Files {
+		collect(file, true)
+	}
+	for _, file := range testFiles {
+		collect(file, false)
+	}
+
+	// Functions exempt by directive (cgo exports, linkname targets)
+	exempt := make(map[string]bool)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && hasLinkDirective(funcDecl) {
+				exempt[qualifiedFuncName(funcDecl)] = true
+			}
+		}
+	}
+
+	for i := range functions {
+		f := &functions[i]
+		name := simpleFuncName(f.FuncName)
+		if ast.IsExported(name) || name == "init" || name == "main" || name == "_" || f.IsTest || exempt[f.FuncName] {
+			continue
+		}
+
+		own, declared := declIndex[f.FuncName]
+		if !declared {
+			continue
+		}
+
+		referenced := false
+		for idx, refs := range declRefs {
+			if idx != own && refs[name] {
+				referenced = true
+				break
+			}
+		}
+		f.Unreferenced = !referenced
+	}
+}
+
+// hasLinkDirective reports whether a function carries a //export or //go:linkname directive,
+// which makes it reachable from outside Go code
+func hasLinkDirective(funcDecl *ast.FuncDecl) bool {
+	if funcDecl.Doc == nil {
+		return false
+	}
+	for _, comment := range funcDecl.Doc.List {
+		if strings.HasPrefix(comment.Text, "//export ") || strings.HasPrefix(comment.Text, "//go:linkname ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import "testing"
+
+func TestDeadCode(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"app/app.go": `package app
+
+import "net/http"
+
+type server struct{}
+
+func Serve() {
+	handler := handleIndex
+	http.HandleFunc("/", handler)
+	s := server{}
+	s.start()
+}
+
+func init() {}
+
+func handleIndex(w http.ResponseWriter, r *http.Request) {}
+
+func unusedHelper() int { return 42 }
+
+func (s server) start() {}
+
+func (s server) stop() {}
+
+func usedInTest() {}
+`,
+		"app/app_test.go": `package app
+
+import "testing"
+
+func TestHelper(t *testing.T) { usedInTest() }
+`,
+	}, nil)
+
+	dead := make(map[string]bool)
+	for _, d := range diagnosticsOfType(report, DiagnosticDeadCode) {
+		dead[d.TargetName] = true
+		if d.Severity != "Info" && d.Severity != "Warning" {
+			t.Errorf("%s severity = %s, want Info or Warning", d.TargetName, d.Severity)
+		}
+	}
+
+	for name, want := range map[string]bool{
+		"app.unusedHelper": true,
+		"app.server.stop":  true,
+		"app.handleIndex":  false, // only used as a value
+		"app.server.start": false,
+		"app.init":         false,
+		"app.Serve":        false, // exported
+		"app.usedInTest":   false, // referenced from a test file
+	} {
+		if dead[name] != want {
+			t.Errorf("%s reported as dead code = %v, want %v", name, dead[name], want)
+		}
+	}
+}
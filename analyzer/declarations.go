@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"path/filepath"
+	"sort"
+)
+
+// FindDuplicateDeclarations finds top-level names declared more than once within a package.
+// parser.ParseDir merges every file in a directory, so files guarded by different build
+// constraints can legitimately declare the same name; those duplicates are marked Constrained.
+func FindDuplicateDeclarations(pkg *ast.Package, fset *token.FileSet) []DuplicateDeclaration {
+	type declSite struct {
+		kind     string
+		fileName string
+		line     int
+	}
+
+	// Traverse files in a stable order so locations are reported consistently
+	fileNames := make([]string, 0, len(pkg.Files))
+	for fileName := range pkg.Files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	sites := make(map[string][]declSite)
+	var order []string
+
+	record := func(name string, kind string, fileName string, pos token.Pos) {
+		if name == "_" {
+			return
+		}
+		if _, exists := sites[name]; !exists {
+			order = append(order, name)
+		}
+		sites[name] = append(sites[name], declSite{
+			kind:     kind,
+			fileName: fileName,
+			line:     fset.Position(pos).Line,
+		})
+	}
+
+	for _, fileName := range fileNames {
+		file := pkg.Files[fileName]
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					// init may be declared any number of times
+					if d.Name.Name != "init" {
+						record(d.Name.Name, "func", fileName, d.Pos())
+					}
+					continue
+				}
+
+				// Methods are keyed by receiver type to avoid clashing with top-level names
+				if len(d.Recv.List) > 0 {
+					recvTypeName := receiverTypeName(d.Recv.List[0].Type)
+					if recvTypeName != "" {
+						record(recvTypeName+"."+d.Name.Name, "method", fileName, d.Pos())
+					}
+				}
+
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch sp := spec.(type) {
+					case *ast.TypeSpec:
+						record(sp.Name.Name, "type", fileName, sp.Pos())
+					case *ast.ValueSpec:
+						for _, name := range sp.Names {
+							record(name.Name, d.Tok.String(), fileName, name.Pos())
+						}
+					}
+				}
+			}
+		}
+	}
+
+	var duplicates []DuplicateDeclaration
+	constrainedCache := make(map[string]bool)
+
+	for _, name := range order {
+		declared := sites[name]
+		if len(declared) < 2 {
+			continue
+		}
+
+		locations := make([]string, 0, len(declared))
+		allConstrained := true
+		for _, site := range declared {
+			locations = append(locations, fmt.Sprintf("%s:%d", site.fileName, site.line))
+
+			constrained, cached := constrainedCache[site.fileName]
+			if !cached {
+				constrained = hasBuildConstraint(site.fileName)
+				constrainedCache[site.fileName] = constrained
+			}
+			if !constrained {
+				allConstrained = false
+			}
+		}
+
+		duplicates = append(duplicates, DuplicateDeclaration{
+			Name:        name,
+			Kind:        declared[0].kind,
+			Locations:   locations,
+			Constrained: allConstrained,
+		})
+	}
+
+	return duplicates
+}
+
+// hasBuildConstraint reports whether a file is excluded from some builds,
+// either by a //go:build line or by a GOOS/GOARCH file name suffix
+func hasBuildConstraint(fileName string) bool {
+	// A context that matches no real platform and sets no tags only accepts unconstrained files
+	ctx := build.Default
+	ctx.GOOS = "none"
+	ctx.GOARCH = "none"
+	ctx.BuildTags = nil
+	ctx.ToolTags = nil
+	ctx.ReleaseTags = nil
+	ctx.CgoEnabled = true
+
+	match, err := ctx.MatchFile(filepath.Dir(fileName), filepath.Base(fileName))
+	if err != nil {
+		return false
+	}
+	return !match
+}
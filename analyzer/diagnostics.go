@@ -1,22 +1,39 @@
 package analyzer
 
-import "fmt"
+import (
+	"fmt"
+	"go/ast"
+	"path/filepath"
+	"sort"
+	"strings"
+)
 
 // PerformDiagnostics performs integrated analysis to detect anti-patterns and code smells
-func PerformDiagnostics(packages []PackageResult) []DiagnosticResult {
+func PerformDiagnostics(packages []PackageResult, cfg *Config) []DiagnosticResult {
 	var diagnostics []DiagnosticResult
 
+	cfg = cfg.withDefaults()
+
 	// Detect God Objects
-	diagnostics = append(diagnostics, detectGodObjects(packages)...)
+	diagnostics = append(diagnostics, detectGodObjects(packages, cfg)...)
 
 	// Detect Unstable Foundations
-	diagnostics = append(diagnostics, detectUnstableFoundations(packages)...)
+	diagnostics = append(diagnostics, detectUnstableFoundations(packages, cfg)...)
+
+	// Detect widely used packages that themselves depend on many others
+	diagnostics = append(diagnostics, detectShotgunSurgery(packages, cfg)...)
 
 	// Detect Overly Complex Functions
-	diagnostics = append(diagnostics, detectComplexFunctions(packages)...)
+	diagnostics = append(diagnostics, detectComplexFunctions(packages, cfg)...)
+
+	// Detect functions that are hard to follow (nesting-aware)
+	diagnostics = append(diagnostics, detectHighCognitiveComplexity(packages, cfg)...)
+
+	// Detect Deeply Nested Functions
+	diagnostics = append(diagnostics, detectDeeplyNestedFunctions(packages)...)
 
 	// Detect Ambiguous Structs
-	diagnostics = append(diagnostics, detectAmbiguousStructs(packages)...)
+	diagnostics = append(diagnostics, detectAmbiguousStructs(packages, cfg)...)
 
 	// Detect Split Responsibilities via Method Islands
 	diagnostics = append(diagnostics, detectMethodIslands(packages)...)
@@ -24,35 +41,157 @@ func PerformDiagnostics(packages []PackageResult) []DiagnosticResult {
 	// Detect Split Responsibilities via Field Clustering
 	diagnostics = append(diagnostics, detectFieldClusters(packages)...)
 
-	return diagnostics
+	// Detect Value Receiver Mutations
+	diagnostics = append(diagnostics, detectValueReceiverMutations(packages)...)
+
+	// Detect Duplicate Declarations
+	diagnostics = append(diagnostics, detectDuplicateDeclarations(packages)...)
+
+	// Detect Unused Interface Methods
+	diagnostics = append(diagnostics, detectUnusedInterfaceMethods(packages)...)
+
+	// Detect Untested Complex Functions
+	diagnostics = append(diagnostics, detectUntestedComplexFunctions(packages, cfg)...)
+
+	// Detect Dead Code (unexported functions nothing refers to)
+	diagnostics = append(diagnostics, detectDeadCode(packages)...)
+
+	// Detect struct types nothing in the project refers to
+	diagnostics = append(diagnostics, detectUnusedTypes(packages)...)
+
+	// Detect Magic Numbers
+	diagnostics = append(diagnostics, detectMagicNumbers(packages, cfg)...)
+
+	// Detect Type Assertion Cascades
+	diagnostics = append(diagnostics, detectTypeAssertionCascades(packages)...)
+
+	// Detect Long Parameter Lists
+	diagnostics = append(diagnostics, detectLongParameterList(packages, cfg)...)
+
+	// Detect exported functions with signatures that are hard to call
+	diagnostics = append(diagnostics, detectAwkwardAPIs(packages, cfg)...)
+
+	// Detect functions calling many distinct functions
+	diagnostics = append(diagnostics, detectHighFanOut(packages, cfg)...)
+
+	// Detect functions that are complex, long, and widely dependent at once
+	diagnostics = append(diagnostics, detectGodFunctions(packages)...)
+
+	// Detect functions with long bodies
+	diagnostics = append(diagnostics, detectLongFunctions(packages, cfg)...)
+
+	// Detect High Struct Complexity
+	diagnostics = append(diagnostics, detectHighStructComplexity(packages, cfg)...)
+
+	// Detect structs with too many fields or methods
+	diagnostics = append(diagnostics, detectLargeStruct(packages, cfg)...)
+
+	// Detect methods that use another struct's fields more than their own
+	diagnostics = append(diagnostics, detectFeatureEnvy(packages, cfg)...)
+
+	// Detect parameter groups that recur across functions
+	diagnostics = append(diagnostics, detectDataClumps(packages)...)
+
+	// Detect functions whose bodies are copies of each other
+	diagnostics = append(diagnostics, detectDuplicateCode(packages)...)
+
+	// Detect Too Many Return Values
+	diagnostics = append(diagnostics, detectTooManyReturnValues(packages)...)
+
+	// Detect Layering Violations
+	diagnostics = append(diagnostics, detectLayeringViolations(packages, cfg)...)
+
+	// Detect Inconsistent Error Handling
+	diagnostics = append(diagnostics, detectInconsistentErrorHandling(packages)...)
+
+	// Detect Allocations In Loops (only populated with perf hints enabled)
+	diagnostics = append(diagnostics, detectAllocationsInLoops(packages)...)
+
+	// Detect Scattered Implementations
+	diagnostics = append(diagnostics, detectScatteredImplementations(packages)...)
+
+	// Detect Poor Encapsulation
+	diagnostics = append(diagnostics, detectPoorEncapsulation(packages)...)
+
+	// Detect Underdocumented Packages
+	diagnostics = append(diagnostics, detectUnderdocumentedPackages(packages)...)
+
+	// Detect Undocumented Exports
+	diagnostics = append(diagnostics, detectUndocumentedExports(packages)...)
+
+	// Detect Instability Role Mismatches
+	diagnostics = append(diagnostics, detectRoleMismatches(packages, cfg)...)
+
+	// Detect Cyclic Dependencies between packages
+	diagnostics = append(diagnostics, detectCyclicDependencies(packages)...)
+
+	// Detect packages far from the main sequence (Zone of Pain / Zone of Uselessness)
+	diagnostics = append(diagnostics, detectMainSequenceDistance(packages, cfg)...)
+
+	// Detect packages over their complexity budget
+	diagnostics = append(diagnostics, detectComplexityBudgets(packages, cfg)...)
+
+	// Detect Anonymous Types
+	diagnostics = append(diagnostics, detectAnonymousTypes(packages)...)
+
+	// Detect Possible Map Races (only populated with experimental diagnostics enabled)
+	diagnostics = append(diagnostics, detectPossibleMapRaces(packages)...)
+
+	// Detect struct state touched without locks (only populated with experimental diagnostics enabled)
+	diagnostics = append(diagnostics, detectUnsynchronizedSharedState(packages)...)
+
+	// Replace the detectors' severities with the configured ones
+	applySeverityOverrides(diagnostics, cfg.SeverityOverrides)
+
+	// Drop diagnostics suppressed by //codehealth:ignore directives on their declaration
+	diagnostics = filterSuppressedDiagnostics(diagnostics, packages)
+
+	// Drop diagnostics of files with an ignore-file directive and of ignored packages
+	diagnostics = filterIgnoredScopes(diagnostics, packages, cfg.IgnorePackages)
+
+	// Drop diagnostics for files listed in the ignore file
+	return filterIgnoredDiagnostics(diagnostics, cfg.Ignore)
+}
+
+// applySeverityOverrides sets the severity of every diagnostic whose type has a configured override
+func applySeverityOverrides(diagnostics []DiagnosticResult, overrides map[string]string) {
+	if len(overrides) == 0 {
+		return
+	}
+	for i := range diagnostics {
+		if severity, ok := overrides[diagnostics[i].Type]; ok {
+			diagnostics[i].Severity = severity
+		}
+	}
 }
 
 // detectGodObjects detects structs with excessive responsibilities
-// Criteria: LCOM4 >= 5 AND package Ca >= 10
-func detectGodObjects(packages []PackageResult) []DiagnosticResult {
+// Criteria: LCOM4 >= GodObjectLCOM4 (default 5) AND package Ca >= GodObjectAfferent (default 10)
+func detectGodObjects(packages []PackageResult, cfg *Config) []DiagnosticResult {
 	var results []DiagnosticResult
 
 	for _, pkg := range packages {
 		// Only consider packages with high afferent coupling
-		if pkg.Afferent < 10 {
+		if pkg.Afferent < cfg.GodObjectAfferent {
 			continue
 		}
 
 		for _, s := range pkg.Structs {
-			if s.LCOM4Score >= 5 {
+			if s.LCOM4Score >= cfg.GodObjectLCOM4 {
 				results = append(results, DiagnosticResult{
-					Type:       "God Object",
+					Type:       DiagnosticGodObject,
 					TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
 					Message: fmt.Sprintf(
-						"Struct '%s' has excessive responsibilities (LCOM4=%d) and is heavily depended upon (Ca=%d). Consider splitting into smaller, focused structs.",
-						s.StructName, s.LCOM4Score, pkg.Afferent,
+						"Struct '%s' has excessive responsibilities (LCOM4=%d) and is heavily depended upon (Ca=%d). Consider splitting into smaller, focused structs.%s",
+						s.StructName, s.LCOM4Score, pkg.Afferent, splitHint(s),
 					),
 					Severity: "Critical",
-					Evidence: map[string]interface{}{
-						"lcom4_score": s.LCOM4Score,
-						"afferent":    pkg.Afferent,
-						"package":     pkg.Name,
-						"file_path":   s.FilePath,
+					Evidence: GodObjectEvidence{
+						EvidenceBase:             EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+						LCOM4Score:               s.LCOM4Score,
+						ProjectedLCOM4AfterSplit: s.ProjectedLCOM4AfterSplit,
+						SplitCandidate:           s.SplitCandidate,
+						Afferent:                 pkg.Afferent,
 					},
 					RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
 				})
@@ -64,25 +203,30 @@ func detectGodObjects(packages []PackageResult) []DiagnosticResult {
 }
 
 // detectUnstableFoundations detects packages that are heavily depended upon but unstable
-// Criteria: Ca >= 10 AND Instability >= 0.7
-func detectUnstableFoundations(packages []PackageResult) []DiagnosticResult {
+// Criteria: Ca >= UnstableFoundationAfferent (default 10) AND Instability >= UnstableFoundationInstability
+// (default 0.7) (packages below the configured size floor are skipped)
+func detectUnstableFoundations(packages []PackageResult, cfg *Config) []DiagnosticResult {
 	var results []DiagnosticResult
 
 	for _, pkg := range packages {
-		if pkg.Afferent >= 10 && pkg.Instability >= 0.7 {
+		if belowCouplingFloor(pkg, cfg) {
+			continue
+		}
+
+		if pkg.Afferent >= cfg.UnstableFoundationAfferent && pkg.Instability >= cfg.UnstableFoundationInstability {
 			results = append(results, DiagnosticResult{
-				Type:       "Unstable Foundation",
+				Type:       DiagnosticUnstableFoundation,
 				TargetName: pkg.Name,
 				Message: fmt.Sprintf(
 					"Package '%s' is heavily depended upon (Ca=%d) but highly unstable (I=%.2f). This creates a fragile foundation. Consider stabilizing this package by reducing dependencies.",
 					pkg.Name, pkg.Afferent, pkg.Instability,
 				),
 				Severity: "Critical",
-				Evidence: map[string]interface{}{
-					"afferent":    pkg.Afferent,
-					"efferent":    pkg.Efferent,
-					"instability": pkg.Instability,
-					"package":     pkg.Name,
+				Evidence: UnstableFoundationEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name},
+					Afferent:     pkg.Afferent,
+					Efferent:     pkg.Efferent,
+					Instability:  pkg.Instability,
 				},
 				RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
 			})
@@ -92,27 +236,96 @@ func detectUnstableFoundations(packages []PackageResult) []DiagnosticResult {
 	return results
 }
 
+// detectShotgunSurgery detects widely used packages that are likely to change often, so that
+// a change ripples into every dependent. Without version history, change frequency is approximated
+// by efferent coupling: a package depending on many others is dragged along by their changes.
+// A package that is widely used but depends on little (stable) is a healthy foundation.
+// Criteria: Ca >= ShotgunSurgeryAfferent (default 5) AND Ce >= ShotgunSurgeryEfferent (default 5);
+// packages already reported as Unstable Foundation and those below the size floor are skipped.
+// Either threshold set to zero disables the check.
+func detectShotgunSurgery(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+	if cfg.ShotgunSurgeryAfferent == 0 || cfg.ShotgunSurgeryEfferent == 0 {
+		return results
+	}
+
+	dependents := make(map[string][]string)
+	for _, pkg := range packages {
+		seen := make(map[string]bool)
+		for _, imported := range pkg.InternalImports {
+			if imported == pkg.Path || seen[imported] {
+				continue
+			}
+			seen[imported] = true
+			dependents[imported] = append(dependents[imported], pkg.Path)
+		}
+	}
+
+	for _, pkg := range packages {
+		if belowCouplingFloor(pkg, cfg) {
+			continue
+		}
+		if pkg.Afferent < cfg.ShotgunSurgeryAfferent || pkg.Efferent < cfg.ShotgunSurgeryEfferent {
+			continue
+		}
+		if pkg.Afferent >= cfg.UnstableFoundationAfferent && pkg.Instability >= cfg.UnstableFoundationInstability {
+			continue
+		}
+
+		importers := dependents[pkg.Path]
+		sort.Strings(importers)
+
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticShotgunSurgery,
+			TargetName: pkg.Name,
+			Message: fmt.Sprintf(
+				"Package '%s' is used by %d packages (Ca) and depends on %d packages itself (Ce). "+
+					"Changes it absorbs from its dependencies spread to all of its dependents. "+
+					"Consider narrowing its dependencies or splitting the widely used part into a stable package.",
+				pkg.Name, pkg.Afferent, pkg.Efferent,
+			),
+			Severity: "Warning",
+			Evidence: ShotgunSurgeryEvidence{
+				EvidenceBase:      EvidenceBase{Package: pkg.Name},
+				Afferent:          pkg.Afferent,
+				Efferent:          pkg.Efferent,
+				Instability:       pkg.Instability,
+				Dependents:        importers,
+				AfferentThreshold: cfg.ShotgunSurgeryAfferent,
+				EfferentThreshold: cfg.ShotgunSurgeryEfferent,
+			},
+			RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+		})
+	}
+
+	return results
+}
+
+// belowCouplingFloor reports whether a package is too small for coupling-based diagnostics
+func belowCouplingFloor(pkg PackageResult, cfg *Config) bool {
+	return pkg.TotalLoC < cfg.CouplingMinLoC || pkg.FuncCount < cfg.CouplingMinFunctions
+}
+
 // detectComplexFunctions detects functions with excessive cyclomatic complexity
-// Criteria: Complexity >= 15
-func detectComplexFunctions(packages []PackageResult) []DiagnosticResult {
+// Criteria: Complexity >= ComplexFunctionThreshold (default 15)
+func detectComplexFunctions(packages []PackageResult, cfg *Config) []DiagnosticResult {
 	var results []DiagnosticResult
 
 	for _, pkg := range packages {
 		for _, f := range pkg.Functions {
-			if f.Complexity >= 15 {
+			if f.Complexity >= cfg.ComplexFunctionThreshold {
 				results = append(results, DiagnosticResult{
-					Type:       "Overly Complex Function",
+					Type:       DiagnosticComplexFunction,
 					TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
 					Message: fmt.Sprintf(
 						"Function '%s' is too complex (Complexity=%d). High complexity makes code hard to test and maintain. Consider refactoring into smaller functions.",
 						f.FuncName, f.Complexity,
 					),
 					Severity: "Warning",
-					Evidence: map[string]interface{}{
-						"complexity": f.Complexity,
-						"function":   f.FuncName,
-						"package":    pkg.Name,
-						"file_path":  f.FilePath,
+					Evidence: ComplexFunctionEvidence{
+						EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+						Complexity:   f.Complexity,
+						Function:     f.FuncName,
 					},
 					RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
 				})
@@ -123,9 +336,81 @@ func detectComplexFunctions(packages []PackageResult) []DiagnosticResult {
 	return results
 }
 
+// detectHighCognitiveComplexity detects functions that are hard to follow
+// Criteria: CognitiveComplexity >= CognitiveComplexityThreshold (default 15)
+func detectHighCognitiveComplexity(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.CognitiveComplexityThreshold <= 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.CognitiveComplexity < cfg.CognitiveComplexityThreshold {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticCognitiveComplexity,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' is hard to follow (Cognitive Complexity=%d, Cyclomatic Complexity=%d). "+
+						"Deeply nested control flow is costly to read. Consider early returns or extracting nested blocks into functions.",
+					f.FuncName, f.CognitiveComplexity, f.Complexity,
+				),
+				Severity: "Warning",
+				Evidence: CognitiveComplexityEvidence{
+					EvidenceBase:        EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					CognitiveComplexity: f.CognitiveComplexity,
+					Complexity:          f.Complexity,
+					Threshold:           cfg.CognitiveComplexityThreshold,
+					Function:            f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectDeeplyNestedFunctions detects functions with deeply nested control flow
+// Criteria: MaxNestingDepth >= 4
+func detectDeeplyNestedFunctions(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.MaxNestingDepth < 4 {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticDeeplyNested,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' nests control flow %d levels deep. Deep nesting is hard to follow; consider guard clauses or extracting the inner blocks.",
+					f.FuncName, f.MaxNestingDepth,
+				),
+				Severity: "Warning",
+				Evidence: DeeplyNestedEvidence{
+					EvidenceBase:    EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					MaxNestingDepth: f.MaxNestingDepth,
+					Function:        f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
 // detectAmbiguousStructs detects structs with low cohesion and complex methods
-// Criteria: LCOM4 >= 3 AND at least one method with Complexity >= 10
-func detectAmbiguousStructs(packages []PackageResult) []DiagnosticResult {
+// Criteria: LCOM4 >= AmbiguousStructLCOM4 (default 3) AND at least one method with
+// Complexity >= AmbiguousStructComplexity (default 10)
+func detectAmbiguousStructs(packages []PackageResult, cfg *Config) []DiagnosticResult {
 	var results []DiagnosticResult
 
 	for _, pkg := range packages {
@@ -136,7 +421,7 @@ func detectAmbiguousStructs(packages []PackageResult) []DiagnosticResult {
 		}
 
 		for _, s := range pkg.Structs {
-			if s.LCOM4Score < 3 {
+			if s.LCOM4Score < cfg.AmbiguousStructLCOM4 {
 				continue
 			}
 
@@ -149,7 +434,7 @@ func detectAmbiguousStructs(packages []PackageResult) []DiagnosticResult {
 			for funcName, complexity := range methodComplexity {
 				// Check if function name starts with struct name (method naming)
 				if len(funcName) > len(structPrefix) && funcName[:len(structPrefix)] == structPrefix {
-					if complexity >= 10 {
+					if complexity >= cfg.AmbiguousStructComplexity {
 						hasComplexMethod = true
 						complexMethods = append(complexMethods, funcName)
 					}
@@ -158,18 +443,19 @@ func detectAmbiguousStructs(packages []PackageResult) []DiagnosticResult {
 
 			if hasComplexMethod {
 				results = append(results, DiagnosticResult{
-					Type:       "Ambiguous Struct",
+					Type:       DiagnosticAmbiguousStruct,
 					TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
 					Message: fmt.Sprintf(
-						"Struct '%s' has unclear responsibilities (LCOM4=%d) and contains complex logic. This suggests mixed concerns. Consider refactoring.",
-						s.StructName, s.LCOM4Score,
+						"Struct '%s' has unclear responsibilities (LCOM4=%d) and contains complex logic. This suggests mixed concerns. Consider refactoring.%s",
+						s.StructName, s.LCOM4Score, splitHint(s),
 					),
 					Severity: "Warning",
-					Evidence: map[string]interface{}{
-						"lcom4_score":      s.LCOM4Score,
-						"complex_methods":  complexMethods,
-						"package":          pkg.Name,
-						"file_path":        s.FilePath,
+					Evidence: AmbiguousStructEvidence{
+						EvidenceBase:             EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+						LCOM4Score:               s.LCOM4Score,
+						ProjectedLCOM4AfterSplit: s.ProjectedLCOM4AfterSplit,
+						SplitCandidate:           s.SplitCandidate,
+						ComplexMethods:           complexMethods,
 					},
 					RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
 				})
@@ -180,6 +466,17 @@ func detectAmbiguousStructs(packages []PackageResult) []DiagnosticResult {
 	return results
 }
 
+// splitHint describes the projected LCOM4 improvement of extracting the struct's largest component
+func splitHint(s StructResult) string {
+	if len(s.SplitCandidate) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(
+		" Extracting [%s] into its own struct would reduce LCOM4 from %d to %d.",
+		strings.Join(s.SplitCandidate, ", "), s.LCOM4Score, s.ProjectedLCOM4AfterSplit,
+	)
+}
+
 // detectMethodIslands detects structs with multiple isolated private method clusters
 // Criteria: MethodClusters.HasMultipleIslands == true (>= 2 clusters)
 func detectMethodIslands(packages []PackageResult) []DiagnosticResult {
@@ -204,7 +501,7 @@ func detectMethodIslands(packages []PackageResult) []DiagnosticResult {
 			}
 
 			results = append(results, DiagnosticResult{
-				Type:       "Split Responsibility (Method Islands)",
+				Type:       DiagnosticMethodIslands,
 				TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
 				Message: fmt.Sprintf(
 					"Struct '%s' has %d isolated groups of private methods, suggesting %d distinct responsibilities. "+
@@ -213,12 +510,11 @@ func detectMethodIslands(packages []PackageResult) []DiagnosticResult {
 					s.StructName, mc.ClusterCount, mc.ClusterCount, clusterSummary,
 				),
 				Severity: "Warning",
-				Evidence: map[string]interface{}{
-					"cluster_count":         mc.ClusterCount,
-					"total_private_methods": mc.TotalPrivateMethods,
-					"clusters":              mc.Clusters,
-					"package":               pkg.Name,
-					"file_path":             s.FilePath,
+				Evidence: MethodIslandsEvidence{
+					EvidenceBase:        EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+					ClusterCount:        mc.ClusterCount,
+					TotalPrivateMethods: mc.TotalPrivateMethods,
+					Clusters:            mc.Clusters,
 				},
 				RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
 			})
@@ -248,7 +544,7 @@ func detectFieldClusters(packages []PackageResult) []DiagnosticResult {
 			}
 
 			results = append(results, DiagnosticResult{
-				Type:       "Split Responsibility (Field Clusters)",
+				Type:       DiagnosticFieldClusters,
 				TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
 				Message: fmt.Sprintf(
 					"Struct '%s' shows %d distinct responsibility patterns in method-field usage (PCA analysis). "+
@@ -256,14 +552,227 @@ func detectFieldClusters(packages []PackageResult) []DiagnosticResult {
 					s.StructName, fm.EstimatedClusters, fm.Recommendations,
 				),
 				Severity: severity,
-				Evidence: map[string]interface{}{
-					"estimated_clusters": fm.EstimatedClusters,
-					"explained_variance": fm.ExplainedVariance,
-					"method_count":       len(fm.MethodNames),
-					"field_count":        len(fm.FieldNames),
-					"package":            pkg.Name,
-					"file_path":          s.FilePath,
-					"recommendations":    fm.Recommendations,
+				Evidence: FieldClustersEvidence{
+					EvidenceBase:      EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+					EstimatedClusters: fm.EstimatedClusters,
+					ExplainedVariance: fm.ExplainedVariance,
+					MethodCount:       len(fm.MethodNames),
+					FieldCount:        len(fm.FieldNames),
+					Recommendations:   fm.Recommendations,
+				},
+				RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectValueReceiverMutations detects value-receiver methods that write to struct fields
+// Criteria: a method with a value receiver assigns to a receiver field (the write is lost)
+func detectValueReceiverMutations(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			for _, m := range s.ValueReceiverMutations {
+				results = append(results, DiagnosticResult{
+					Type:       DiagnosticValueReceiverMutation,
+					TargetName: fmt.Sprintf("%s.%s.%s", pkg.Name, s.StructName, m.Method),
+					Message: fmt.Sprintf(
+						"Method '%s.%s' has a value receiver but writes to field(s) %s. "+
+							"The writes modify a copy and are lost when the method returns. Consider using a pointer receiver.",
+						s.StructName, m.Method, strings.Join(m.Fields, ", "),
+					),
+					Severity: "Warning",
+					Evidence: ValueReceiverMutationEvidence{
+						EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+						Method:       m.Method,
+						Fields:       m.Fields,
+					},
+					RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// detectDuplicateDeclarations detects top-level names declared in more than one file of a package
+// Criteria: the same name is declared twice; Critical unless every declaring file is build-constrained
+func detectDuplicateDeclarations(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, d := range pkg.DuplicateDeclarations {
+			severity := "Critical"
+			reason := "This does not compile and metrics for this name may be double-counted."
+			if d.Constrained {
+				severity = "Warning"
+				reason = "All declaring files have build constraints, so this is likely intentional, but metrics for this name may be double-counted."
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticDuplicateDeclaration,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, d.Name),
+				Message: fmt.Sprintf(
+					"%s '%s' is declared %d times in package '%s' (%s). %s",
+					d.Kind, d.Name, len(d.Locations), pkg.Name, strings.Join(d.Locations, ", "), reason,
+				),
+				Severity: severity,
+				Evidence: DuplicateDeclarationEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name},
+					Name:         d.Name,
+					Kind:         d.Kind,
+					Locations:    d.Locations,
+					Constrained:  d.Constrained,
+				},
+				RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectUnusedInterfaceMethods detects interface methods that no caller in the project invokes
+// Criteria: no selector in the analyzed code uses the method name (intra-project, name-based)
+func detectUnusedInterfaceMethods(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, iface := range pkg.Interfaces {
+			for _, method := range iface.UnusedMethods {
+				results = append(results, DiagnosticResult{
+					Type:       DiagnosticUnusedInterfaceMethod,
+					TargetName: fmt.Sprintf("%s.%s.%s", pkg.Name, iface.Name, method),
+					Message: fmt.Sprintf(
+						"Method '%s' of interface '%s' is never called within the project. "+
+							"The interface may be wider than its callers need; consider removing the method or splitting the interface.",
+						method, iface.Name,
+					),
+					Severity: "Info",
+					Evidence: UnusedInterfaceMethodEvidence{
+						EvidenceBase:  EvidenceBase{Package: pkg.Name, FilePath: iface.FilePath},
+						Interface:     iface.Name,
+						Method:        method,
+						MethodCount:   len(iface.Methods),
+						UnusedMethods: iface.UnusedMethods,
+					},
+					RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// detectUntestedComplexFunctions detects complex functions that no test references
+// Criteria: Complexity >= 10 AND not reachable from any _test.go file
+// Severity: Critical when Complexity >= ComplexFunctionThreshold (also an Overly Complex Function), Warning otherwise
+func detectUntestedComplexFunctions(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			// Test code is not expected to be tested itself
+			if f.Complexity < 10 || f.HasTestReference || f.IsTest {
+				continue
+			}
+
+			severity := "Warning"
+			if f.Complexity >= cfg.ComplexFunctionThreshold {
+				severity = "Critical"
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticUntestedComplexFunction,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' is complex (Complexity=%d) and is not referenced by any test, directly or through its callers. "+
+						"Complex untested code carries the highest regression risk. Add tests before refactoring it.",
+					f.FuncName, f.Complexity,
+				),
+				Severity: severity,
+				Evidence: ComplexFunctionEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					Complexity:   f.Complexity,
+					Function:     f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectDeadCode detects unexported functions that nothing in their package refers to
+// Criteria: unexported, not init/main/test code, and no identifier use outside its own declaration
+// (so no callers: a recursive call to itself does not keep a function alive)
+func detectDeadCode(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if !f.Unreferenced {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticDeadCode,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' (%s) is unexported and nothing in the package refers to it. "+
+						"Unused code still has to be read and maintained. Consider deleting it.",
+					f.FuncName, f.FilePath,
+				),
+				Severity: "Info",
+				Evidence: DeadCodeEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					Function:     f.FuncName,
+					LoC:          f.LoC,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectUnusedTypes detects struct types that are declared but never used
+// Criteria: Unreferenced (no use of the type anywhere in the project outside its own declaration
+// and method receivers, and no project interface it implements; see unused_types.go)
+func detectUnusedTypes(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			if !s.Unreferenced {
+				continue
+			}
+
+			exported := ast.IsExported(s.StructName)
+			hint := "Consider deleting it."
+			if exported {
+				hint = "If it is not part of the package's public API for other modules, consider deleting it."
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticUnusedType,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
+				Message: fmt.Sprintf(
+					"Struct '%s' (%s) is never instantiated or referred to anywhere in the project. %s",
+					s.StructName, s.FilePath, hint,
+				),
+				Severity: "Info",
+				Evidence: UnusedTypeEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+					Struct:       s.StructName,
+					Exported:     exported,
 				},
 				RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
 			})
@@ -272,3 +781,1098 @@ func detectFieldClusters(packages []PackageResult) []DiagnosticResult {
 
 	return results
 }
+
+// detectMagicNumbers detects functions with many unnamed numeric literals
+// Criteria: MagicNumbers >= MagicNumberThreshold (default 5)
+func detectMagicNumbers(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.MagicNumberThreshold <= 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.MagicNumbers < cfg.MagicNumberThreshold {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticMagicNumber,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' uses %d unnamed numeric literals. Consider replacing them with named constants to document their meaning.",
+					f.FuncName, f.MagicNumbers,
+				),
+				Severity: "Info",
+				Evidence: MagicNumberEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					MagicNumbers: f.MagicNumbers,
+					Threshold:    cfg.MagicNumberThreshold,
+					Function:     f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectTypeAssertionCascades detects functions that type-assert one value to many concrete types,
+// which suggests behavior that belongs behind an interface method
+// Criteria: the same expression is asserted to >= 3 distinct types (type switches excluded)
+func detectTypeAssertionCascades(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if len(f.AssertedTypes) < 3 {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticTypeAssertionCascade,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' type-asserts '%s' to %d different types (%s). Consider moving the varying behavior into an interface method.",
+					f.FuncName, f.AssertionSubject, len(f.AssertedTypes), strings.Join(f.AssertedTypes, ", "),
+				),
+				Severity: "Info",
+				Evidence: TypeAssertionCascadeEvidence{
+					EvidenceBase:  EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					Subject:       f.AssertionSubject,
+					AssertedTypes: f.AssertedTypes,
+					Function:      f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectLayeringViolations detects imports that break the architecture rules in arch.yaml
+// Criteria: an internal import points to a higher layer or matches a deny rule
+func detectLayeringViolations(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.Arch == nil {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, imp := range pkg.InternalImports {
+			rule := cfg.Arch.checkImport(pkg.Path, imp)
+			if rule == "" {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticLayeringViolation,
+				TargetName: pkg.Name,
+				Message: fmt.Sprintf(
+					"Package '%s' imports '%s', which is forbidden by the architecture rule '%s'. Invert the dependency or move the shared code to a lower layer.",
+					pkg.Path, imp, rule,
+				),
+				Severity: "Critical",
+				Evidence: LayeringViolationEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name},
+					Import:       imp,
+					Rule:         rule,
+				},
+				RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectLongParameterList detects functions that take many parameters (a data clump)
+// Criteria: ParamCount >= long parameter list threshold (default 5)
+func detectLongParameterList(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.LongParameterListThreshold <= 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.ParamCount < cfg.LongParameterListThreshold {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticLongParameterList,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' takes %d parameters. Long parameter lists are easy to misorder and often travel together. "+
+						"Consider grouping related parameters into a struct.",
+					f.FuncName, f.ParamCount,
+				),
+				Severity: "Warning",
+				Evidence: LongParameterListEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					ParamCount:   f.ParamCount,
+					Threshold:    cfg.LongParameterListThreshold,
+					Function:     f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectAwkwardAPIs detects exported functions whose signatures are hard to call correctly
+// Criteria: SurfaceComplexity >= SurfaceComplexityThreshold (default 9; 0 disables the check),
+// exported functions and methods outside test files only
+func detectAwkwardAPIs(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.SurfaceComplexityThreshold <= 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.IsTest || !ast.IsExported(simpleFuncName(f.FuncName)) || f.SurfaceComplexity < cfg.SurfaceComplexityThreshold {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticAwkwardAPI,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' has a surface complexity of %d (threshold: %d): %d parameters and %d results. "+
+						"Callers must get many arguments and results right; consider an options struct, a result struct, or splitting the function.",
+					f.FuncName, f.SurfaceComplexity, cfg.SurfaceComplexityThreshold, f.ParamCount, f.ResultCount,
+				),
+				Severity: "Info",
+				Evidence: AwkwardAPIEvidence{
+					EvidenceBase:      EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					SurfaceComplexity: f.SurfaceComplexity,
+					ParamCount:        f.ParamCount,
+					ResultTypes:       f.ResultTypes,
+					Threshold:         cfg.SurfaceComplexityThreshold,
+					Function:          f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectHighFanOut detects functions that call many distinct functions and methods, which makes them
+// sensitive to changes in everything they call
+// Criteria: FanOut >= HighFanOutThreshold (default 20; 0 disables the check)
+func detectHighFanOut(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.HighFanOutThreshold <= 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.FanOut < cfg.HighFanOutThreshold {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticHighFanOut,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' calls %d distinct functions and methods (threshold: %d). A change to any of them may require changing it. "+
+						"Consider delegating groups of calls to helpers, or splitting the function by responsibility.",
+					f.FuncName, f.FanOut, cfg.HighFanOutThreshold,
+				),
+				Severity: "Warning",
+				Evidence: HighFanOutEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					FanOut:       f.FanOut,
+					Afferent:     f.Afferent,
+					Threshold:    cfg.HighFanOutThreshold,
+					Function:     f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectGodFunctions detects functions doing too much: complex, long, and reaching into many packages at once
+// Criteria: complexity, LoC, and DependencyCount each >= 70% of their reference (10, 50, 5), and
+// weighted score 0.4*complexity/10 + 0.3*LoC/50 + 0.3*dependencies/5 >= 1.0 (see god_function.go)
+func detectGodFunctions(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			score, ok := godFunctionScore(f)
+			if !ok || score < godFunctionScoreThreshold {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticGodFunction,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' has complexity %d, %d lines, and uses %d packages (score %.2f). "+
+						"It is doing too much at once; consider splitting it into steps with narrower dependencies.",
+					f.FuncName, f.Complexity, f.LoC, f.DependencyCount, score,
+				),
+				Severity: "Warning",
+				Evidence: GodFunctionEvidence{
+					EvidenceBase:    EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					Function:        f.FuncName,
+					Complexity:      f.Complexity,
+					LoC:             f.LoC,
+					DependencyCount: f.DependencyCount,
+					Score:           score,
+					Threshold:       godFunctionScoreThreshold,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectLongFunctions detects functions whose bodies are too long to take in at once
+// Criteria: LoC (body lines) >= long_function_threshold (default 60), unless a single composite
+// literal (a table of data) spans at least half of the body
+func detectLongFunctions(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.LongFunctionThreshold <= 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.LoC < cfg.LongFunctionThreshold || f.LiteralLoC*2 >= f.LoC {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticLongFunction,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' is %d lines long (threshold: %d). Long functions tend to mix several steps. "+
+						"Consider extracting the steps into well-named functions.",
+					f.FuncName, f.LoC, cfg.LongFunctionThreshold,
+				),
+				Severity: "Warning",
+				Evidence: LongFunctionEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					Function:     f.FuncName,
+					LoC:          f.LoC,
+					Threshold:    cfg.LongFunctionThreshold,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectHighStructComplexity detects structs whose methods add up to a lot of logic
+// Criteria: WMC (sum of method cyclomatic complexities) >= wmc_threshold
+func detectHighStructComplexity(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.WMCThreshold <= 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			if s.WMC < cfg.WMCThreshold {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticHighStructComplexity,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
+				Message: fmt.Sprintf(
+					"Struct '%s' has a WMC of %d: its methods add up to a large amount of branching logic. "+
+						"Consider moving parts of that logic into smaller collaborating types.",
+					s.StructName, s.WMC,
+				),
+				Severity: "Warning",
+				Evidence: HighStructComplexityEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+					Struct:       s.StructName,
+					WMC:          s.WMC,
+					Threshold:    cfg.WMCThreshold,
+				},
+				RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectLargeStruct detects structs that have accumulated too many fields or methods,
+// which often marks a God Object even when LCOM4 is low
+// Criteria: FieldCount > large_struct_fields (default 15) OR MethodCount > large_struct_methods (default 20)
+func detectLargeStruct(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			var reasons []string
+			if cfg.LargeStructFields > 0 && s.FieldCount > cfg.LargeStructFields {
+				reasons = append(reasons, fmt.Sprintf("%d fields", s.FieldCount))
+			}
+			if cfg.LargeStructMethods > 0 && s.MethodCount > cfg.LargeStructMethods {
+				reasons = append(reasons, fmt.Sprintf("%d methods", s.MethodCount))
+			}
+			if len(reasons) == 0 {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticLargeStruct,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
+				Message: fmt.Sprintf(
+					"Struct '%s' has %s. Large structs tend to collect unrelated responsibilities. "+
+						"Consider grouping related fields and the methods using them into smaller types.",
+					s.StructName, strings.Join(reasons, " and "),
+				),
+				Severity: "Warning",
+				Evidence: LargeStructEvidence{
+					EvidenceBase:    EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+					Struct:          s.StructName,
+					FieldCount:      s.FieldCount,
+					MethodCount:     s.MethodCount,
+					FieldThreshold:  cfg.LargeStructFields,
+					MethodThreshold: cfg.LargeStructMethods,
+				},
+				RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectFeatureEnvy detects methods that work mostly on another struct's data
+// Criteria: reads >= 3 fields of another package struct, at least feature_envy_margin more than
+// the receiver fields it accesses (adapter and constructor-like methods are not considered)
+func detectFeatureEnvy(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if cfg.FeatureEnvyMargin <= 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			for _, envy := range s.FeatureEnvy {
+				if len(envy.EnviedFields) < 3 || len(envy.EnviedFields)-envy.OwnFields < cfg.FeatureEnvyMargin {
+					continue
+				}
+
+				results = append(results, DiagnosticResult{
+					Type:       DiagnosticFeatureEnvy,
+					TargetName: fmt.Sprintf("%s.%s.%s", pkg.Name, s.StructName, envy.Method),
+					Message: fmt.Sprintf(
+						"Method '%s.%s' reads %d fields of '%s' (%s) but only %d of its own. "+
+							"Consider moving it, or the part using those fields, to '%s'.",
+						s.StructName, envy.Method, len(envy.EnviedFields), envy.EnviedStruct,
+						strings.Join(envy.EnviedFields, ", "), envy.OwnFields, envy.EnviedStruct,
+					),
+					Severity: "Info",
+					Evidence: FeatureEnvyEvidence{
+						EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: envy.FilePath},
+						Struct:       s.StructName,
+						Method:       envy.Method,
+						Line:         envy.Line,
+						OwnFields:    envy.OwnFields,
+						EnviedStruct: envy.EnviedStruct,
+						EnviedFields: envy.EnviedFields,
+						Margin:       cfg.FeatureEnvyMargin,
+					},
+					RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// detectDataClumps detects groups of parameters that travel together, suggesting a missing type
+// Criteria: the same run of >= 3 parameters (names and types, in order) in >= 3 non-test functions
+// anywhere in the project; a run contained in a longer clump of the same functions is not reported
+func detectDataClumps(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, clump := range findDataClumps(packages) {
+		members := clump.Functions
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].Package.Path != members[j].Package.Path {
+				return members[i].Package.Path < members[j].Package.Path
+			}
+			return members[i].Function.FuncName < members[j].Function.FuncName
+		})
+
+		parameters := make([]string, len(clump.Params))
+		names := make([]string, len(clump.Params))
+		for i, p := range clump.Params {
+			parameters[i] = p.Name + " " + p.Type
+			names[i] = p.Name
+		}
+
+		var functions, locations []string
+		for _, m := range members {
+			functions = append(functions, fmt.Sprintf("%s.%s", m.Package.Name, m.Function.FuncName))
+			locations = append(locations, fmt.Sprintf("%s:%d", m.Function.FilePath, m.Function.Line))
+		}
+
+		first := members[0]
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticDataClump,
+			TargetName: fmt.Sprintf("(%s)", strings.Join(parameters, ", ")),
+			Message: fmt.Sprintf(
+				"Parameters (%s) are passed together to %d functions: %s. "+
+					"Data that always travels together is a missing type; consider a struct holding %s.",
+				strings.Join(parameters, ", "), len(members), strings.Join(functions, ", "), strings.Join(names, ", "),
+			),
+			Severity: "Info",
+			Evidence: DataClumpEvidence{
+				EvidenceBase: EvidenceBase{Package: first.Package.Name, FilePath: first.Function.FilePath},
+				Parameters:   parameters,
+				Functions:    functions,
+				Locations:    locations,
+			},
+			RelatedPath: fmt.Sprintf("#function-%s-%s", first.Package.Path, first.Function.FuncName),
+		})
+	}
+
+	return results
+}
+
+// detectDuplicateCode detects functions whose bodies are the same code with different names
+// Criteria: >= 2 non-test functions with identical body fingerprints (structure and operators,
+// ignoring identifier names and literal values) and >= 20 statements (see clones.go)
+func detectDuplicateCode(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, group := range findDuplicateBodies(packages) {
+		members := group.Functions
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].Package.Path != members[j].Package.Path {
+				return members[i].Package.Path < members[j].Package.Path
+			}
+			return members[i].Function.FuncName < members[j].Function.FuncName
+		})
+
+		var functions, locations []string
+		for _, m := range members {
+			functions = append(functions, fmt.Sprintf("%s.%s", m.Package.Name, m.Function.FuncName))
+			locations = append(locations, fmt.Sprintf("%s:%d", m.Function.FilePath, m.Function.Line))
+		}
+
+		first := members[0]
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticDuplicateCode,
+			TargetName: functions[0],
+			Message: fmt.Sprintf(
+				"%d functions share the same %d-statement body, differing only in names and literals: %s. "+
+					"Consider extracting the common logic into one function.",
+				len(members), group.Statements, strings.Join(functions, ", "),
+			),
+			Severity: "Warning",
+			Evidence: DuplicateCodeEvidence{
+				EvidenceBase: EvidenceBase{Package: first.Package.Name, FilePath: first.Function.FilePath},
+				Functions:    functions,
+				Locations:    locations,
+				Statements:   group.Statements,
+				Threshold:    duplicateCodeMinStatements,
+			},
+			RelatedPath: fmt.Sprintf("#function-%s-%s", first.Package.Path, first.Function.FuncName),
+		})
+	}
+
+	return results
+}
+
+// detectTooManyReturnValues detects functions returning so many values that a result struct would be clearer
+// Criteria: >= 4 returned values, every result counted (a trailing error or bool is exempt only
+// in the two-value (T, error) and (T, bool) forms)
+func detectTooManyReturnValues(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if significantResultCount(f.ResultTypes) < 4 {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticTooManyReturnValues,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' returns %d values (%s). Consider returning a result struct instead.",
+					f.FuncName, f.ResultCount, strings.Join(f.ResultTypes, ", "),
+				),
+				Severity: "Info",
+				Evidence: TooManyReturnValuesEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					ResultCount:  f.ResultCount,
+					ResultTypes:  f.ResultTypes,
+					Function:     f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectAllocationsInLoops detects functions that allocate inside loop bodies (performance hint)
+// Criteria: at least one for/range body contains make, new, a slice/map/pointer literal, or an
+// append to an unpreallocated slice (requires perf hints to be enabled)
+func detectAllocationsInLoops(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if len(f.LoopAllocations) == 0 {
+				continue
+			}
+
+			var locations []string
+			for _, loop := range f.LoopAllocations {
+				locations = append(locations, fmt.Sprintf("line %d (%s)", loop.Line, strings.Join(loop.Allocations, ", ")))
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticAllocationInLoop,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' allocates inside %d loop(s): %s. Consider hoisting or preallocating if this is a hot path.",
+					f.FuncName, len(f.LoopAllocations), strings.Join(locations, "; "),
+				),
+				Severity: "Info",
+				Evidence: AllocationInLoopEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					Function:     f.FuncName,
+					Loops:        f.LoopAllocations,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectInconsistentErrorHandling detects packages that mix error construction styles
+// Criteria: >= 5 error constructions in >= 2 styles, with no style used for 60% or more of them
+func detectInconsistentErrorHandling(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		total := 0
+		for _, count := range pkg.ErrorStyles {
+			total += count
+		}
+		if total < 5 || len(pkg.ErrorStyles) < 2 {
+			continue
+		}
+
+		dominant, share := dominantErrorStyle(pkg.ErrorStyles)
+		if share >= 0.6 {
+			continue
+		}
+
+		styles := make([]string, 0, len(pkg.ErrorStyles))
+		for style, count := range pkg.ErrorStyles {
+			styles = append(styles, fmt.Sprintf("%s: %d", style, count))
+		}
+		sort.Strings(styles)
+
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticInconsistentErrors,
+			TargetName: pkg.Name,
+			Message: fmt.Sprintf(
+				"Package '%s' mixes error construction styles (%s); the most common, %s, covers only %.0f%%. Consider settling on one style.",
+				pkg.Name, strings.Join(styles, ", "), dominant, share*100,
+			),
+			Severity: "Info",
+			Evidence: InconsistentErrorsEvidence{
+				EvidenceBase:  EvidenceBase{Package: pkg.Name},
+				ErrorStyles:   pkg.ErrorStyles,
+				DominantStyle: dominant,
+				DominantShare: share,
+			},
+			RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+		})
+	}
+
+	return results
+}
+
+// detectScatteredImplementations detects structs whose methods are spread across many files
+// Criteria: methods declared in >= 5 distinct files
+func detectScatteredImplementations(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			if len(s.MethodFiles) < 5 {
+				continue
+			}
+
+			fileNames := make([]string, len(s.MethodFiles))
+			for i, filePath := range s.MethodFiles {
+				fileNames[i] = filepath.Base(filePath)
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticScatteredImplementation,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
+				Message: fmt.Sprintf(
+					"Struct '%s' has methods spread across %d files (%s). "+
+						"Readers must jump between files to understand it. Consider grouping its methods into fewer files.",
+					s.StructName, len(s.MethodFiles), strings.Join(fileNames, ", "),
+				),
+				Severity: "Info",
+				Evidence: ScatteredImplementationEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+					MethodFiles:  s.MethodFiles,
+				},
+				RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectPoorEncapsulation detects packages that export nearly all of their declarations
+// Criteria: >= 10 top-level declarations AND exported ratio >= 0.9
+func detectPoorEncapsulation(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		if pkg.TotalDecls < 10 || pkg.ExportedRatio < 0.9 {
+			continue
+		}
+
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticPoorEncapsulation,
+			TargetName: pkg.Name,
+			Message: fmt.Sprintf(
+				"Package '%s' exports %d of its %d top-level declarations (%.0f%%), leaving no distinction between API and implementation. "+
+					"Consider unexporting helpers that are not meant for other packages.",
+				pkg.Name, pkg.ExportedDecls, pkg.TotalDecls, pkg.ExportedRatio*100,
+			),
+			Severity: "Info",
+			Evidence: PoorEncapsulationEvidence{
+				EvidenceBase:  EvidenceBase{Package: pkg.Name},
+				ExportedDecls: pkg.ExportedDecls,
+				TotalDecls:    pkg.TotalDecls,
+				ExportedRatio: pkg.ExportedRatio,
+			},
+			RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+		})
+	}
+
+	return results
+}
+
+// detectUnderdocumentedPackages detects packages with a large exported API but almost no doc comments
+// Criteria: >= 5 exported declarations AND exported ratio >= 0.5 AND doc comment density < 0.05
+func detectUnderdocumentedPackages(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		if pkg.ExportedDecls < 5 || pkg.ExportedRatio < 0.5 || pkg.DocCommentDensity >= 0.05 {
+			continue
+		}
+
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticUnderdocumentedPackage,
+			TargetName: pkg.Name,
+			Message: fmt.Sprintf(
+				"Package '%s' exports %d declarations (%.0f%% of its top-level declarations) but doc comments cover only %.1f%% of its source lines. "+
+					"Consider documenting the exported API.",
+				pkg.Name, pkg.ExportedDecls, pkg.ExportedRatio*100, pkg.DocCommentDensity*100,
+			),
+			Severity: "Info",
+			Evidence: UnderdocumentedPackageEvidence{
+				EvidenceBase:      EvidenceBase{Package: pkg.Name},
+				ExportedDecls:     pkg.ExportedDecls,
+				ExportedRatio:     pkg.ExportedRatio,
+				DocCommentDensity: pkg.DocCommentDensity,
+			},
+			RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+		})
+	}
+
+	return results
+}
+
+// detectUndocumentedExports reports each exported declaration missing a doc comment
+// Criteria: exported func, method of an exported type, type, or top-level var/const without a doc
+// comment on itself or its declaration group (generated and test files are skipped)
+func detectUndocumentedExports(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, u := range pkg.UndocumentedExports {
+			relatedPath := fmt.Sprintf("#package-%s", pkg.Path)
+			if u.Kind == "func" || u.Kind == "method" {
+				relatedPath = fmt.Sprintf("#function-%s-%s", pkg.Path, u.Name)
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticUndocumentedExport,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, u.Name),
+				Message: fmt.Sprintf(
+					"Exported %s '%s' (%s:%d) has no doc comment (%d undocumented in package '%s').",
+					u.Kind, u.Name, filepath.Base(u.FilePath), u.Line, len(pkg.UndocumentedExports), pkg.Name,
+				),
+				Severity: "Info",
+				Evidence: UndocumentedExportEvidence{
+					EvidenceBase:        EvidenceBase{Package: pkg.Name, FilePath: u.FilePath},
+					Name:                u.Name,
+					Kind:                u.Kind,
+					Line:                u.Line,
+					PackageUndocumented: len(pkg.UndocumentedExports),
+				},
+				RelatedPath: relatedPath,
+			})
+		}
+	}
+
+	return results
+}
+
+// detectPossibleMapRaces detects map fields that goroutines may access without synchronization (experimental)
+// Criteria: >= 2 methods access a map field without Lock/RLock, at least one writes it, and at least
+// one is started with a go statement (requires experimental diagnostics to be enabled)
+func detectPossibleMapRaces(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			for _, race := range s.MapRaces {
+				results = append(results, DiagnosticResult{
+					Type:       DiagnosticPossibleMapRace,
+					TargetName: fmt.Sprintf("%s.%s.%s", pkg.Name, s.StructName, race.Field),
+					Message: fmt.Sprintf(
+						"Map field '%s.%s' is written by %s and accessed by %s without a lock, and %s run(s) as a goroutine. "+
+							"Concurrent map writes crash at runtime; consider guarding the map with a sync.Mutex.",
+						s.StructName, race.Field, strings.Join(race.Writers, ", "), strings.Join(race.Methods, ", "),
+						strings.Join(race.Goroutines, ", "),
+					),
+					Severity: "Info",
+					Evidence: PossibleMapRaceEvidence{
+						EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+						Field:        race.Field,
+						Methods:      race.Methods,
+						Writers:      race.Writers,
+						Goroutines:   race.Goroutines,
+					},
+					RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+// detectUnsynchronizedSharedState detects structs whose fields may be touched concurrently without a lock (experimental)
+// Criteria: the struct has a sync.Mutex/RWMutex field and a method accesses other fields without Lock/RLock,
+// or a method starts a goroutine touching fields without Lock/RLock (requires experimental diagnostics; see shared_state.go)
+func detectUnsynchronizedSharedState(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			if len(s.UnsynchronizedAccesses) == 0 {
+				continue
+			}
+
+			var descriptions []string
+			for _, access := range s.UnsynchronizedAccesses {
+				description := fmt.Sprintf("%s (%s)", access.Method, strings.Join(access.Fields, ", "))
+				if access.Goroutine {
+					description += " in a goroutine"
+				}
+				descriptions = append(descriptions, description)
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticUnsynchronizedState,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
+				Message: fmt.Sprintf(
+					"Struct '%s' may have its fields accessed concurrently without a lock: %s. "+
+						"Guard these accesses with the struct's lock (or add a sync.Mutex), or confirm they happen before the struct is shared.",
+					s.StructName, strings.Join(descriptions, "; "),
+				),
+				Severity: "Info",
+				Evidence: UnsynchronizedStateEvidence{
+					EvidenceBase: EvidenceBase{Package: pkg.Name, FilePath: s.FilePath},
+					Struct:       s.StructName,
+					Accesses:     s.UnsynchronizedAccesses,
+				},
+				RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectRoleMismatches detects packages whose instability contradicts their configured role
+// Criteria: role "stable" with Instability >= 0.7, or role "unstable" with Instability <= 0.3
+// (packages without dependencies or below the configured size floor are skipped)
+func detectRoleMismatches(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	if len(cfg.PackageRoles) == 0 {
+		return results
+	}
+
+	for _, pkg := range packages {
+		if pkg.Afferent+pkg.Efferent == 0 || belowCouplingFloor(pkg, cfg) {
+			continue
+		}
+
+		role, pattern := cfg.packageRole(pkg.Path)
+
+		var advice string
+		switch {
+		case role == RoleStable && pkg.Instability >= 0.7:
+			advice = "A stable package should depend on little and be depended upon. Consider moving its outgoing dependencies behind interfaces it owns."
+		case role == RoleUnstable && pkg.Instability <= 0.3:
+			advice = "Other packages should not build on an unstable package. Consider moving what they depend on into a stable package."
+		default:
+			continue
+		}
+
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticRoleMismatch,
+			TargetName: pkg.Name,
+			Message: fmt.Sprintf(
+				"Package '%s' has the role '%s' (pattern '%s') but an instability of %.2f (Ca=%d, Ce=%d). %s",
+				pkg.Name, role, pattern, pkg.Instability, pkg.Afferent, pkg.Efferent, advice,
+			),
+			Severity: "Warning",
+			Evidence: RoleMismatchEvidence{
+				EvidenceBase: EvidenceBase{Package: pkg.Name},
+				Role:         role,
+				Pattern:      pattern,
+				Afferent:     pkg.Afferent,
+				Efferent:     pkg.Efferent,
+				Instability:  pkg.Instability,
+			},
+			RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+		})
+	}
+
+	return results
+}
+
+// detectCyclicDependencies detects project packages that import each other
+// Criteria: the package is the first member of an import cycle (one result per cycle)
+func detectCyclicDependencies(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		cycle := pkg.ImportCycle
+		if cycle == nil || cycle.Packages[0] != pkg.Path {
+			continue
+		}
+
+		members := make([]string, len(cycle.Packages))
+		for i, member := range cycle.Packages {
+			members[i] = displayPackagePath(member)
+		}
+
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticCyclicDependency,
+			TargetName: pkg.Name,
+			Message: fmt.Sprintf(
+				"Packages %s form an import cycle (%s). The Go compiler rejects import cycles, "+
+					"and packages that depend on each other cannot be changed or tested in isolation. "+
+					"Consider moving the shared types into a package both can import, or inverting a dependency with an interface.",
+				strings.Join(members, ", "), formatImportRing(cycle.Ring),
+			),
+			Severity: "Critical",
+			Evidence: CyclicDependencyEvidence{
+				EvidenceBase: EvidenceBase{Package: pkg.Name},
+				Packages:     cycle.Packages,
+				Ring:         cycle.Ring,
+			},
+			RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+		})
+	}
+
+	return results
+}
+
+// detectMainSequenceDistance detects packages far from the main sequence (A + I = 1).
+// Zone of Pain: concrete and stable (A + I < 1), hard to change because others depend on its details.
+// Zone of Uselessness: abstract and unstable (A + I > 1), abstractions nobody depends on.
+// Criteria: declares types AND has package coupling AND distance >= 0.5 (Warning at >= 0.7)
+func detectMainSequenceDistance(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		if pkg.TotalTypes == 0 || pkg.Afferent+pkg.Efferent == 0 || belowCouplingFloor(pkg, cfg) || pkg.Distance < 0.5 {
+			continue
+		}
+
+		severity := "Info"
+		if pkg.Distance >= 0.7 {
+			severity = "Warning"
+		}
+
+		diagnosticType := DiagnosticZoneOfPain
+		advice := "It is concrete and depended upon, so every change ripples to its dependents. Consider introducing interfaces for what other packages use."
+		if pkg.Abstractness+pkg.Instability > 1 {
+			diagnosticType = DiagnosticZoneOfUselessness
+			advice = "Its abstractions have few dependents. Consider removing unused interfaces or moving them next to their consumers."
+		}
+
+		results = append(results, DiagnosticResult{
+			Type:       diagnosticType,
+			TargetName: pkg.Name,
+			Message: fmt.Sprintf(
+				"Package '%s' is far from the main sequence (Abstractness=%.2f, Instability=%.2f, Distance=%.2f). %s",
+				pkg.Name, pkg.Abstractness, pkg.Instability, pkg.Distance, advice,
+			),
+			Severity: severity,
+			Evidence: MainSequenceEvidence{
+				EvidenceBase:  EvidenceBase{Package: pkg.Name},
+				AbstractTypes: pkg.AbstractTypes,
+				TotalTypes:    pkg.TotalTypes,
+				Abstractness:  pkg.Abstractness,
+				Afferent:      pkg.Afferent,
+				Efferent:      pkg.Efferent,
+				Instability:   pkg.Instability,
+				Distance:      pkg.Distance,
+			},
+			RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+		})
+	}
+
+	return results
+}
+
+// detectAnonymousTypes detects functions that write many struct or interface types inline
+// Criteria: >= 3 non-empty anonymous struct/interface types in the signature and body
+func detectAnonymousTypes(packages []PackageResult) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.AnonymousTypes < 3 {
+				continue
+			}
+
+			results = append(results, DiagnosticResult{
+				Type:       DiagnosticAnonymousType,
+				TargetName: fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Message: fmt.Sprintf(
+					"Function '%s' declares %d anonymous struct or interface types inline. "+
+						"Inline types cannot be reused or documented; consider declaring them as named types.",
+					f.FuncName, f.AnonymousTypes,
+				),
+				Severity: "Info",
+				Evidence: AnonymousTypeEvidence{
+					EvidenceBase:   EvidenceBase{Package: pkg.Name, FilePath: f.FilePath},
+					AnonymousTypes: f.AnonymousTypes,
+					Function:       f.FuncName,
+				},
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+	}
+
+	return results
+}
+
+// detectComplexityBudgets detects packages where too many functions are complex
+// Criteria: more than complexity_budget.max_percent of the package's functions have a complexity
+// above complexity_budget.threshold (requires a configured budget)
+func detectComplexityBudgets(packages []PackageResult, cfg *Config) []DiagnosticResult {
+	var results []DiagnosticResult
+
+	budget := cfg.ComplexityBudget
+	if budget == nil {
+		return results
+	}
+
+	for _, pkg := range packages {
+		if len(pkg.Functions) == 0 {
+			continue
+		}
+
+		var overThreshold []FunctionResult
+		for _, f := range pkg.Functions {
+			if f.Complexity > budget.Threshold {
+				overThreshold = append(overThreshold, f)
+			}
+		}
+
+		percent := float64(len(overThreshold)) / float64(len(pkg.Functions)) * 100
+		if percent <= budget.MaxPercent {
+			continue
+		}
+
+		// Most overThreshold first
+		sort.SliceStable(overThreshold, func(i, j int) bool {
+			return overThreshold[i].Complexity > overThreshold[j].Complexity
+		})
+		names := make([]string, len(overThreshold))
+		for i, f := range overThreshold {
+			names[i] = f.FuncName
+		}
+
+		results = append(results, DiagnosticResult{
+			Type:       DiagnosticComplexityBudget,
+			TargetName: pkg.Name,
+			Message: fmt.Sprintf(
+				"Package '%s' has %d of %d functions (%.1f%%) with complexity above %d, exceeding the budget of %g%%. "+
+					"Simplify the most overThreshold ones first: %s.",
+				pkg.Name, len(overThreshold), len(pkg.Functions), percent, budget.Threshold, budget.MaxPercent,
+				strings.Join(names, ", "),
+			),
+			Severity: "Warning",
+			Evidence: ComplexityBudgetEvidence{
+				EvidenceBase:     EvidenceBase{Package: pkg.Name},
+				Threshold:        budget.Threshold,
+				MaxPercent:       budget.MaxPercent,
+				Percent:          percent,
+				FunctionCount:    len(pkg.Functions),
+				OverThreshold:    len(overThreshold),
+				ComplexFunctions: names,
+			},
+			RelatedPath: fmt.Sprintf("#package-%s", pkg.Path),
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,326 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// ReportDiff describes the changes between two analysis reports
+type ReportDiff struct {
+	Summary          []MetricDelta      `json:"summary"`           // Project-level metric changes
+	AddedPackages    []string           `json:"added_packages"`    // Package paths only in the new report
+	RemovedPackages  []string           `json:"removed_packages"`  // Package paths only in the old report
+	MovedPackages    []PackageMove      `json:"moved_packages"`    // Packages matched by name across a path change
+	PackageChanges   []EntityDelta      `json:"package_changes"`   // Packages whose metrics changed
+	StructChanges    []EntityDelta      `json:"struct_changes"`    // Structs whose metrics changed
+	FunctionChanges  []EntityDelta      `json:"function_changes"`  // Functions whose metrics changed
+	AddedFunctions   []string           `json:"added_functions"`   // Functions only in the new report ("path.Func")
+	RemovedFunctions []string           `json:"removed_functions"` // Functions only in the old report ("path.Func")
+	NewDiagnostics   []DiagnosticResult `json:"new_diagnostics"`   // Diagnostics only in the new report
+	FixedDiagnostics []DiagnosticResult `json:"fixed_diagnostics"` // Diagnostics only in the old report
+}
+
+// PackageMove represents a package whose directory changed between reports
+type PackageMove struct {
+	From string `json:"from"` // Package path in the old report
+	To   string `json:"to"`   // Package path in the new report
+}
+
+// EntityDelta holds the metric changes of a single package, struct, or function
+type EntityDelta struct {
+	Package string        `json:"package"` // Package path
+	Name    string        `json:"name"`    // Struct or function name (empty for packages)
+	Changes []MetricDelta `json:"changes"` // Changed metrics
+}
+
+// MetricDelta represents the change of a single metric
+type MetricDelta struct {
+	Metric string  `json:"metric"` // Metric name
+	Old    float64 `json:"old"`    // Value in the old report
+	New    float64 `json:"new"`    // Value in the new report
+	Delta  float64 `json:"delta"`  // New - Old
+}
+
+// CompareReports compares two reports and returns the changes from oldReport to newReport
+func CompareReports(oldReport, newReport *Report) *ReportDiff {
+	diff := &ReportDiff{
+		Summary:          compareSummary(oldReport, newReport),
+		AddedPackages:    []string{},
+		RemovedPackages:  []string{},
+		MovedPackages:    []PackageMove{},
+		PackageChanges:   []EntityDelta{},
+		StructChanges:    []EntityDelta{},
+		FunctionChanges:  []EntityDelta{},
+		AddedFunctions:   []string{},
+		RemovedFunctions: []string{},
+		NewDiagnostics:   []DiagnosticResult{},
+		FixedDiagnostics: []DiagnosticResult{},
+	}
+
+	oldPackages := make(map[string]PackageResult)
+	for _, pkg := range oldReport.Packages {
+		oldPackages[pkg.Path] = pkg
+	}
+	newPackages := make(map[string]PackageResult)
+	for _, pkg := range newReport.Packages {
+		newPackages[pkg.Path] = pkg
+	}
+
+	// Packages that moved keep their metrics history: old path -> new path
+	moved := matchMovedPackages(oldPackages, newPackages)
+	movedFrom := make(map[string]string)
+	for oldPath, newPath := range moved {
+		movedFrom[newPath] = oldPath
+		diff.MovedPackages = append(diff.MovedPackages, PackageMove{From: oldPath, To: newPath})
+	}
+
+	for path, newPkg := range newPackages {
+		oldPath := path
+		if from, exists := movedFrom[path]; exists {
+			oldPath = from
+		}
+		oldPkg, exists := oldPackages[oldPath]
+		if !exists {
+			diff.AddedPackages = append(diff.AddedPackages, path)
+			continue
+		}
+
+		// Package-level metrics
+		changes := collectChanges([]MetricDelta{
+			{Metric: "afferent", Old: float64(oldPkg.Afferent), New: float64(newPkg.Afferent)},
+			{Metric: "efferent", Old: float64(oldPkg.Efferent), New: float64(newPkg.Efferent)},
+			{Metric: "instability", Old: oldPkg.Instability, New: newPkg.Instability},
+			{Metric: "dependency_depth", Old: float64(oldPkg.DependencyDepth), New: float64(newPkg.DependencyDepth)},
+			{Metric: "total_loc", Old: float64(oldPkg.TotalLoC), New: float64(newPkg.TotalLoC)},
+			{Metric: "source_loc", Old: float64(oldPkg.SourceLoC), New: float64(newPkg.SourceLoC)},
+			{Metric: "func_count", Old: float64(oldPkg.FuncCount), New: float64(newPkg.FuncCount)},
+		})
+		if len(changes) > 0 {
+			diff.PackageChanges = append(diff.PackageChanges, EntityDelta{Package: path, Changes: changes})
+		}
+
+		// Struct-level metrics
+		oldStructs := make(map[string]StructResult)
+		for _, s := range oldPkg.Structs {
+			oldStructs[s.StructName] = s
+		}
+		for _, s := range newPkg.Structs {
+			if old, exists := oldStructs[s.StructName]; exists {
+				changes := collectChanges([]MetricDelta{
+					{Metric: "lcom4", Old: float64(old.LCOM4Score), New: float64(s.LCOM4Score)},
+				})
+				if len(changes) > 0 {
+					diff.StructChanges = append(diff.StructChanges, EntityDelta{Package: path, Name: s.StructName, Changes: changes})
+				}
+			}
+		}
+
+		// Function-level metrics
+		oldFunctions := make(map[string]FunctionResult)
+		for _, f := range oldPkg.Functions {
+			oldFunctions[f.FuncName] = f
+		}
+		newFunctions := make(map[string]bool)
+		for _, f := range newPkg.Functions {
+			newFunctions[f.FuncName] = true
+
+			old, exists := oldFunctions[f.FuncName]
+			if !exists {
+				diff.AddedFunctions = append(diff.AddedFunctions, qualifiedName(path, f.FuncName))
+				continue
+			}
+
+			changes := collectChanges([]MetricDelta{
+				{Metric: "complexity", Old: float64(old.Complexity), New: float64(f.Complexity)},
+				{Metric: "loc", Old: float64(old.LoC), New: float64(f.LoC)},
+			})
+			if len(changes) > 0 {
+				diff.FunctionChanges = append(diff.FunctionChanges, EntityDelta{Package: path, Name: f.FuncName, Changes: changes})
+			}
+		}
+		for name := range oldFunctions {
+			if !newFunctions[name] {
+				diff.RemovedFunctions = append(diff.RemovedFunctions, qualifiedName(path, name))
+			}
+		}
+	}
+
+	for path := range oldPackages {
+		if _, exists := newPackages[path]; !exists && moved[path] == "" {
+			diff.RemovedPackages = append(diff.RemovedPackages, path)
+		}
+	}
+
+	// Diagnostics are matched by type, target, and location (old locations follow moved packages)
+	oldDiagnostics := make(map[string]bool)
+	for _, d := range oldReport.Diagnostics {
+		oldDiagnostics[diagnosticKey(relocateDiagnostic(d, moved))] = true
+	}
+	newDiagnostics := make(map[string]bool)
+	for _, d := range newReport.Diagnostics {
+		newDiagnostics[diagnosticKey(d)] = true
+		if !oldDiagnostics[diagnosticKey(d)] {
+			diff.NewDiagnostics = append(diff.NewDiagnostics, d)
+		}
+	}
+	for _, d := range oldReport.Diagnostics {
+		if !newDiagnostics[diagnosticKey(relocateDiagnostic(d, moved))] {
+			diff.FixedDiagnostics = append(diff.FixedDiagnostics, d)
+		}
+	}
+
+	sortReportDiff(diff)
+
+	return diff
+}
+
+// matchMovedPackages pairs packages that exist only in the old report with packages that exist
+// only in the new report when they have the same package name and the name is unambiguous on
+// both sides, so a directory move is not reported as a removal plus an addition.
+// It returns old path -> new path.
+func matchMovedPackages(oldPackages, newPackages map[string]PackageResult) map[string]string {
+	removedByName := make(map[string][]string)
+	for path, pkg := range oldPackages {
+		if _, exists := newPackages[path]; !exists {
+			removedByName[pkg.Name] = append(removedByName[pkg.Name], path)
+		}
+	}
+	addedByName := make(map[string][]string)
+	for path, pkg := range newPackages {
+		if _, exists := oldPackages[path]; !exists {
+			addedByName[pkg.Name] = append(addedByName[pkg.Name], path)
+		}
+	}
+
+	moved := make(map[string]string)
+	for name, removed := range removedByName {
+		if added := addedByName[name]; len(removed) == 1 && len(added) == 1 {
+			moved[removed[0]] = added[0]
+		}
+	}
+	return moved
+}
+
+// relocateDiagnostic returns the diagnostic with its RelatedPath rewritten to the package's new path
+// if the package moved
+func relocateDiagnostic(d DiagnosticResult, moved map[string]string) DiagnosticResult {
+	for oldPath, newPath := range moved {
+		for _, kind := range []string{"package", "struct", "function"} {
+			prefix := "#" + kind + "-" + oldPath
+			if d.RelatedPath == prefix {
+				d.RelatedPath = "#" + kind + "-" + newPath
+				return d
+			}
+			// Struct and function names never contain "-", so the rest identifies the declaration
+			if name, ok := strings.CutPrefix(d.RelatedPath, prefix+"-"); ok && !strings.Contains(name, "-") {
+				d.RelatedPath = "#" + kind + "-" + newPath + "-" + name
+				return d
+			}
+		}
+	}
+	return d
+}
+
+// compareSummary compares project-level totals
+func compareSummary(oldReport, newReport *Report) []MetricDelta {
+	summary := []MetricDelta{
+		{Metric: "health_score", Old: oldReport.HealthScore, New: newReport.HealthScore},
+		{Metric: "total_loc", Old: float64(oldReport.TotalLoC), New: float64(newReport.TotalLoC)},
+		{Metric: "packages", Old: float64(len(oldReport.Packages)), New: float64(len(newReport.Packages))},
+		{Metric: "structs", Old: float64(countStructs(oldReport)), New: float64(countStructs(newReport))},
+		{Metric: "functions", Old: float64(countFunctions(oldReport)), New: float64(countFunctions(newReport))},
+		{Metric: "diagnostics", Old: float64(len(oldReport.Diagnostics)), New: float64(len(newReport.Diagnostics))},
+	}
+	for _, severity := range []string{"Critical", "Warning", "Info"} {
+		summary = append(summary, MetricDelta{
+			Metric: "diagnostics_" + severity,
+			Old:    float64(countSeverity(oldReport, severity)),
+			New:    float64(countSeverity(newReport, severity)),
+		})
+	}
+
+	for i := range summary {
+		summary[i].Delta = summary[i].New - summary[i].Old
+	}
+	return summary
+}
+
+// collectChanges fills in deltas and keeps only metrics that changed
+func collectChanges(metrics []MetricDelta) []MetricDelta {
+	var changes []MetricDelta
+	for _, m := range metrics {
+		if m.New != m.Old {
+			m.Delta = m.New - m.Old
+			changes = append(changes, m)
+		}
+	}
+	return changes
+}
+
+// diagnosticKey identifies a diagnostic across reports
+func diagnosticKey(d DiagnosticResult) string {
+	return d.Type + "|" + d.TargetName + "|" + d.RelatedPath
+}
+
+// qualifiedName joins a package path and a name for display
+func qualifiedName(pkgPath string, name string) string {
+	if pkgPath == "" {
+		return name
+	}
+	return pkgPath + "." + name
+}
+
+// countStructs counts all structs in a report
+func countStructs(report *Report) int {
+	count := 0
+	for _, pkg := range report.Packages {
+		count += len(pkg.Structs)
+	}
+	return count
+}
+
+// countFunctions counts all functions in a report
+func countFunctions(report *Report) int {
+	count := 0
+	for _, pkg := range report.Packages {
+		count += len(pkg.Functions)
+	}
+	return count
+}
+
+// countSeverity counts diagnostics with the given severity
+func countSeverity(report *Report, severity string) int {
+	count := 0
+	for _, d := range report.Diagnostics {
+		if d.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// sortReportDiff sorts every list in the diff for stable output
+func sortReportDiff(diff *ReportDiff) {
+	sort.Strings(diff.AddedPackages)
+	sort.Strings(diff.RemovedPackages)
+	sort.Slice(diff.MovedPackages, func(i, j int) bool {
+		return diff.MovedPackages[i].From < diff.MovedPackages[j].From
+	})
+	sort.Strings(diff.AddedFunctions)
+	sort.Strings(diff.RemovedFunctions)
+
+	for _, deltas := range [][]EntityDelta{diff.PackageChanges, diff.StructChanges, diff.FunctionChanges} {
+		sort.Slice(deltas, func(i, j int) bool {
+			if deltas[i].Package != deltas[j].Package {
+				return deltas[i].Package < deltas[j].Package
+			}
+			return deltas[i].Name < deltas[j].Name
+		})
+	}
+
+	for _, diagnostics := range [][]DiagnosticResult{diff.NewDiagnostics, diff.FixedDiagnostics} {
+		sort.Slice(diagnostics, func(i, j int) bool {
+			return diagnosticKey(diagnostics[i]) < diagnosticKey(diagnostics[j])
+		})
+	}
+}
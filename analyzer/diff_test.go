@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareReports(t *testing.T) {
+	complexParse := DiagnosticResult{
+		Type:        DiagnosticComplexFunction,
+		TargetName:  "parser.Parse",
+		Severity:    "Warning",
+		RelatedPath: "#function-internal/parser-Parse",
+	}
+	godObject := DiagnosticResult{
+		Type:        DiagnosticGodObject,
+		TargetName:  "store.Store",
+		Severity:    "Critical",
+		RelatedPath: "#struct-store-Store",
+	}
+
+	oldReport := &Report{
+		Packages: []PackageResult{
+			{Name: "parser", Path: "internal/parser", Functions: []FunctionResult{{FuncName: "Parse", Complexity: 22, LoC: 80}}},
+			{Name: "store", Path: "store", Structs: []StructResult{{StructName: "Store", LCOM4Score: 2}}},
+		},
+		Diagnostics: []DiagnosticResult{complexParse},
+	}
+	newReport := &Report{
+		Packages: []PackageResult{
+			// parser moved out of internal/ and Parse was simplified
+			{Name: "parser", Path: "parser", Functions: []FunctionResult{{FuncName: "Parse", Complexity: 6, LoC: 30}, {FuncName: "scan", Complexity: 4}}},
+			{Name: "store", Path: "store", Structs: []StructResult{{StructName: "Store", LCOM4Score: 6}}},
+		},
+		Diagnostics: []DiagnosticResult{godObject},
+	}
+
+	diff := CompareReports(oldReport, newReport)
+
+	if want := []PackageMove{{From: "internal/parser", To: "parser"}}; !reflect.DeepEqual(diff.MovedPackages, want) {
+		t.Errorf("moved packages = %+v, want %+v", diff.MovedPackages, want)
+	}
+	if len(diff.AddedPackages) != 0 || len(diff.RemovedPackages) != 0 {
+		t.Errorf("a moved package was reported as added %v or removed %v", diff.AddedPackages, diff.RemovedPackages)
+	}
+
+	if len(diff.NewDiagnostics) != 1 || diff.NewDiagnostics[0].Type != DiagnosticGodObject {
+		t.Errorf("new diagnostics = %+v, want the God Object", diff.NewDiagnostics)
+	}
+	if len(diff.FixedDiagnostics) != 1 || diff.FixedDiagnostics[0].TargetName != "parser.Parse" {
+		t.Errorf("fixed diagnostics = %+v, want the complex function", diff.FixedDiagnostics)
+	}
+
+	wantFunction := EntityDelta{Package: "parser", Name: "Parse", Changes: []MetricDelta{
+		{Metric: "complexity", Old: 22, New: 6, Delta: -16},
+		{Metric: "loc", Old: 80, New: 30, Delta: -50},
+	}}
+	if len(diff.FunctionChanges) != 1 || !reflect.DeepEqual(diff.FunctionChanges[0], wantFunction) {
+		t.Errorf("function changes = %+v, want %+v", diff.FunctionChanges, wantFunction)
+	}
+	if want := []string{"parser.scan"}; !reflect.DeepEqual(diff.AddedFunctions, want) {
+		t.Errorf("added functions = %v, want %v", diff.AddedFunctions, want)
+	}
+
+	wantStruct := EntityDelta{Package: "store", Name: "Store", Changes: []MetricDelta{{Metric: "lcom4", Old: 2, New: 6, Delta: 4}}}
+	if len(diff.StructChanges) != 1 || !reflect.DeepEqual(diff.StructChanges[0], wantStruct) {
+		t.Errorf("struct changes = %+v, want %+v", diff.StructChanges, wantStruct)
+	}
+}
+
+func TestCompareIdenticalReports(t *testing.T) {
+	report := &Report{
+		Packages:    []PackageResult{{Name: "app", Path: "app", Functions: []FunctionResult{{FuncName: "Run", Complexity: 3}}}},
+		Diagnostics: []DiagnosticResult{{Type: DiagnosticComplexFunction, TargetName: "app.Run"}},
+	}
+
+	diff := CompareReports(report, report)
+	if len(diff.NewDiagnostics)+len(diff.FixedDiagnostics)+len(diff.FunctionChanges)+len(diff.AddedFunctions)+len(diff.RemovedFunctions) != 0 {
+		t.Errorf("identical reports produced changes: %+v", diff)
+	}
+}
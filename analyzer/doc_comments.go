@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// FindUndocumentedExports finds exported functions, methods, types, and top-level variables and
+// constants without a doc comment. A declaration in a group is documented by the group's comment too.
+// Methods of unexported types, test files, and generated files ("// Code generated ... DO NOT EDIT.")
+// are skipped.
+func FindUndocumentedExports(pkg *ast.Package, fset *token.FileSet) []UndocumentedExport {
+	var results []UndocumentedExport
+
+	add := func(name string, kind string, fileName string, pos token.Pos) {
+		results = append(results, UndocumentedExport{
+			Name:     name,
+			Kind:     kind,
+			FilePath: fileName,
+			Line:     fset.Position(pos).Line,
+		})
+	}
+
+	for fileName, file := range pkg.Files {
+		if isTestFile(fileName) || ast.IsGenerated(file) {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if !d.Name.IsExported() || d.Doc != nil {
+					continue
+				}
+				if d.Recv == nil {
+					add(d.Name.Name, "func", fileName, d.Name.Pos())
+					continue
+				}
+				if recvName := receiverTypeName(d.Recv.List[0].Type); ast.IsExported(recvName) {
+					add(recvName+"."+d.Name.Name, "method", fileName, d.Name.Pos())
+				}
+			case *ast.GenDecl:
+				if d.Doc != nil {
+					continue
+				}
+				for _, spec := range d.Specs {
+					switch sp := spec.(type) {
+					case *ast.TypeSpec:
+						if sp.Name.IsExported() && sp.Doc == nil {
+							add(sp.Name.Name, "type", fileName, sp.Name.Pos())
+						}
+					case *ast.ValueSpec:
+						if sp.Doc != nil {
+							continue
+						}
+						kind := "var"
+						if d.Tok == token.CONST {
+							kind = "const"
+						}
+						for _, name := range sp.Names {
+							if name.IsExported() {
+								add(name.Name, kind, fileName, name.Pos())
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].FilePath != results[j].FilePath {
+			return results[i].FilePath < results[j].FilePath
+		}
+		return results[i].Line < results[j].Line
+	})
+
+	return results
+}
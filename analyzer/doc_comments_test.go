@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCommentDensity(t *testing.T) {
+	var documented, undocumented strings.Builder
+	documented.WriteString("// Package documented is well documented\npackage documented\n")
+	undocumented.WriteString("package undocumented\n")
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(&documented, "\n// F%d returns its index\nfunc F%d() int {\n\treturn %d // inline\n}\n", i, i, i)
+		fmt.Fprintf(&undocumented, "\nfunc F%d() int {\n\treturn %d\n}\n", i, i)
+	}
+
+	report := analyzeFixture(t, map[string]string{
+		"documented/documented.go":     documented.String(),
+		"undocumented/undocumented.go": undocumented.String(),
+	}, nil)
+
+	// 16 source lines: the package clause and three lines per function
+	doc := findPackage(t, report, "documented")
+	if math.Abs(doc.DocCommentDensity-6.0/16.0) > 1e-9 || math.Abs(doc.CommentDensity-11.0/16.0) > 1e-9 {
+		t.Errorf("documented: doc density %.3f, comment density %.3f, want %.3f and %.3f",
+			doc.DocCommentDensity, doc.CommentDensity, 6.0/16.0, 11.0/16.0)
+	}
+
+	undoc := findPackage(t, report, "undocumented")
+	if undoc.DocCommentDensity != 0 || undoc.CommentDensity != 0 {
+		t.Errorf("undocumented: doc density %.3f, comment density %.3f, want 0", undoc.DocCommentDensity, undoc.CommentDensity)
+	}
+
+	underdocumented := diagnosticsOfType(report, DiagnosticUnderdocumentedPackage)
+	if len(underdocumented) != 1 || underdocumented[0].TargetName != "undocumented" {
+		t.Errorf("Underdocumented Package diagnostics = %+v, want one for undocumented", underdocumented)
+	}
+}
+
+func TestUndocumentedExports(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"api/api.go": `package api
+
+// Documented has a doc comment
+func Documented() {}
+
+func Undocumented() {}
+
+type Client struct{}
+
+func (c *Client) Do() {}
+
+func unexported() {}
+
+var Timeout = 5
+
+// Grouped constants share the declaration's doc comment
+const (
+	A = 1
+	B = 2
+)
+`,
+		"api/api.pb.go": `// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package api
+
+func Generated() {}
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "api")
+	got := make(map[string]int)
+	for _, u := range pkg.UndocumentedExports {
+		got[u.Kind+" "+u.Name] = u.Line
+	}
+	want := map[string]int{"func Undocumented": 6, "type Client": 8, "method Client.Do": 10, "var Timeout": 14}
+	if len(got) != len(want) {
+		t.Errorf("undocumented exports = %v, want %v", got, want)
+	}
+	for key, line := range want {
+		if got[key] != line {
+			t.Errorf("%s reported at line %d, want %d (all: %v)", key, got[key], line, got)
+		}
+	}
+
+	diagnostics := diagnosticsOfType(report, DiagnosticUndocumentedExport)
+	if len(diagnostics) != len(want) {
+		t.Fatalf("got %d Undocumented Export diagnostics, want %d", len(diagnostics), len(want))
+	}
+	for _, d := range diagnostics {
+		evidence := d.Evidence.(UndocumentedExportEvidence)
+		if d.Severity != "Info" || evidence.PackageUndocumented != len(want) {
+			t.Errorf("%s: severity %s, package count %d, want Info and %d", d.TargetName, d.Severity, evidence.PackageUndocumented, len(want))
+		}
+	}
+}
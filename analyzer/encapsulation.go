@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"go/ast"
+)
+
+// CountExportedDeclarations counts the package's top-level functions, types, variables and
+// constants, returning how many are exported and the total.
+// Methods, init functions and blank identifiers are not counted.
+func CountExportedDeclarations(pkg *ast.Package) (exported int, total int) {
+	count := func(name *ast.Ident) {
+		if name.Name == "_" {
+			return
+		}
+		total++
+		if name.IsExported() {
+			exported++
+		}
+	}
+
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil && d.Name.Name != "init" {
+					count(d.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch sp := spec.(type) {
+					case *ast.TypeSpec:
+						count(sp.Name)
+					case *ast.ValueSpec:
+						for _, name := range sp.Names {
+							count(name)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return exported, total
+}
@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// Error construction styles (PackageResult.ErrorStyles keys)
+const (
+	ErrorStyleErrorsNew  = "errors.New"            // errors.New("...")
+	ErrorStyleErrorf     = "fmt.Errorf"            // fmt.Errorf without %w
+	ErrorStyleErrorfWrap = "fmt.Errorf %w"         // fmt.Errorf wrapping with %w
+	ErrorStylePkgErrors  = "github.com/pkg/errors" // errors.Wrap, errors.Wrapf, etc. from github.com/pkg/errors
+)
+
+// CountErrorStyles tallies how errors are constructed inside the package's function bodies.
+// Package-level sentinel declarations (var ErrX = errors.New(...)) are not counted, since
+// sentinels complement rather than compete with the wrapping style.
+func CountErrorStyles(pkg *ast.Package) map[string]int {
+	styles := make(map[string]int)
+
+	for _, file := range pkg.Files {
+		fileImports := buildFileImportMap(file)
+
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+
+			ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if style := errorStyleOf(call, fileImports); style != "" {
+					styles[style]++
+				}
+				return true
+			})
+		}
+	}
+
+	if len(styles) == 0 {
+		return nil
+	}
+	return styles
+}
+
+// errorStyleOf returns the error construction style of a call, or "" if it does not construct an error
+func errorStyleOf(call *ast.CallExpr, fileImports map[string]string) string {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	switch fileImports[ident.Name] {
+	case "errors":
+		if selector.Sel.Name == "New" {
+			return ErrorStyleErrorsNew
+		}
+	case "fmt":
+		if selector.Sel.Name != "Errorf" {
+			return ""
+		}
+		if len(call.Args) > 0 {
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && strings.Contains(lit.Value, "%w") {
+				return ErrorStyleErrorfWrap
+			}
+		}
+		return ErrorStyleErrorf
+	case "github.com/pkg/errors":
+		switch selector.Sel.Name {
+		case "New", "Errorf", "Wrap", "Wrapf", "WithMessage", "WithMessagef", "WithStack":
+			return ErrorStylePkgErrors
+		}
+	}
+	return ""
+}
+
+// dominantErrorStyle returns the most used style and its share of all error constructions
+func dominantErrorStyle(styles map[string]int) (string, float64) {
+	names := make([]string, 0, len(styles))
+	total := 0
+	for name, count := range styles {
+		names = append(names, name)
+		total += count
+	}
+	if total == 0 {
+		return "", 0
+	}
+
+	// Sort for a stable choice between equally used styles
+	sort.Strings(names)
+	dominant := names[0]
+	for _, name := range names[1:] {
+		if styles[name] > styles[dominant] {
+			dominant = name
+		}
+	}
+	return dominant, float64(styles[dominant]) / float64(total)
+}
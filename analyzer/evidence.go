@@ -0,0 +1,559 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Diagnostic types (DiagnosticResult.Type)
+const (
+	DiagnosticGodObject               = "God Object"
+	DiagnosticUnstableFoundation      = "Unstable Foundation"
+	DiagnosticComplexFunction         = "Overly Complex Function"
+	DiagnosticAmbiguousStruct         = "Ambiguous Struct"
+	DiagnosticMethodIslands           = "Split Responsibility (Method Islands)"
+	DiagnosticFieldClusters           = "Split Responsibility (Field Clusters)"
+	DiagnosticValueReceiverMutation   = "Value Receiver Mutation"
+	DiagnosticDuplicateDeclaration    = "Duplicate Declaration"
+	DiagnosticUnusedInterfaceMethod   = "Unused Interface Method"
+	DiagnosticUntestedComplexFunction = "Untested Complex Function"
+	DiagnosticMagicNumber             = "Magic Number"
+	DiagnosticTypeAssertionCascade    = "Type Assertion Cascade"
+	DiagnosticTooManyReturnValues     = "Too Many Return Values"
+	DiagnosticLayeringViolation       = "Layering Violation"
+	DiagnosticAllocationInLoop        = "Allocation In Loop"
+	DiagnosticInconsistentErrors      = "Inconsistent Error Handling"
+	DiagnosticScatteredImplementation = "Scattered Implementation"
+	DiagnosticPoorEncapsulation       = "Poor Encapsulation"
+	DiagnosticPossibleMapRace         = "Possible Map Race"
+	DiagnosticRoleMismatch            = "Instability Role Mismatch"
+	DiagnosticAnonymousType           = "Anonymous Type"
+	DiagnosticComplexityBudget        = "Complexity Budget Exceeded"
+	DiagnosticCognitiveComplexity     = "High Cognitive Complexity"
+	DiagnosticDeeplyNested            = "Deeply Nested Function"
+	DiagnosticZoneOfPain              = "Zone of Pain"
+	DiagnosticZoneOfUselessness       = "Zone of Uselessness"
+	DiagnosticCyclicDependency        = "Cyclic Dependency"
+	DiagnosticLongParameterList       = "Long Parameter List"
+	DiagnosticDeadCode                = "Dead Code"
+	DiagnosticHighStructComplexity    = "High Struct Complexity"
+	DiagnosticUnderdocumentedPackage  = "Underdocumented Package"
+	DiagnosticUndocumentedExport      = "Undocumented Export"
+	DiagnosticFeatureEnvy             = "Feature Envy"
+	DiagnosticLongFunction            = "Long Function"
+	DiagnosticLargeStruct             = "Large Struct"
+	DiagnosticDataClump               = "Data Clump"
+	DiagnosticGodFunction             = "God Function"
+	DiagnosticDuplicateCode           = "Duplicate Code"
+	DiagnosticShotgunSurgery          = "Shotgun Surgery"
+	DiagnosticUnusedType              = "Unused Type"
+	DiagnosticHighFanOut              = "High Fan-Out"
+	DiagnosticUnsynchronizedState     = "Unsynchronized Shared State"
+	DiagnosticAwkwardAPI              = "Awkward API"
+)
+
+// Evidence is the typed data supporting a diagnosis. Each diagnostic type has its own
+// evidence struct (e.g. GodObjectEvidence for "God Object"); use a type switch to read it.
+type Evidence interface {
+	// SourceFiles returns the files the diagnosis refers to (empty for package-level diagnoses)
+	SourceFiles() []string
+}
+
+// EvidenceBase holds the fields shared by all evidence types
+type EvidenceBase struct {
+	Package  string `json:"package"`             // Package name
+	FilePath string `json:"file_path,omitempty"` // Source file path (empty for package-level diagnoses)
+}
+
+// SourceFiles implements Evidence
+func (e EvidenceBase) SourceFiles() []string {
+	if e.FilePath == "" {
+		return nil
+	}
+	return []string{e.FilePath}
+}
+
+// GodObjectEvidence supports a "God Object" diagnosis
+type GodObjectEvidence struct {
+	EvidenceBase
+	LCOM4Score               int      `json:"lcom4_score"`
+	ProjectedLCOM4AfterSplit int      `json:"projected_lcom4_after_split"`
+	SplitCandidate           []string `json:"split_candidate"`
+	Afferent                 int      `json:"afferent"`
+}
+
+// UnstableFoundationEvidence supports an "Unstable Foundation" diagnosis
+type UnstableFoundationEvidence struct {
+	EvidenceBase
+	Afferent    int     `json:"afferent"`
+	Efferent    int     `json:"efferent"`
+	Instability float64 `json:"instability"`
+}
+
+// ShotgunSurgeryEvidence supports a "Shotgun Surgery" diagnosis
+type ShotgunSurgeryEvidence struct {
+	EvidenceBase
+	Afferent          int      `json:"afferent"`
+	Efferent          int      `json:"efferent"`
+	Instability       float64  `json:"instability"`
+	Dependents        []string `json:"dependents"` // Project packages importing this package, sorted
+	AfferentThreshold int      `json:"afferent_threshold"`
+	EfferentThreshold int      `json:"efferent_threshold"`
+}
+
+// ComplexFunctionEvidence supports "Overly Complex Function" and "Untested Complex Function" diagnoses
+type ComplexFunctionEvidence struct {
+	EvidenceBase
+	Complexity int    `json:"complexity"`
+	Function   string `json:"function"`
+}
+
+// AmbiguousStructEvidence supports an "Ambiguous Struct" diagnosis
+type AmbiguousStructEvidence struct {
+	EvidenceBase
+	LCOM4Score               int      `json:"lcom4_score"`
+	ProjectedLCOM4AfterSplit int      `json:"projected_lcom4_after_split"`
+	SplitCandidate           []string `json:"split_candidate"`
+	ComplexMethods           []string `json:"complex_methods"`
+}
+
+// MethodIslandsEvidence supports a "Split Responsibility (Method Islands)" diagnosis
+type MethodIslandsEvidence struct {
+	EvidenceBase
+	ClusterCount        int             `json:"cluster_count"`
+	TotalPrivateMethods int             `json:"total_private_methods"`
+	Clusters            []MethodCluster `json:"clusters"`
+}
+
+// FieldClustersEvidence supports a "Split Responsibility (Field Clusters)" diagnosis
+type FieldClustersEvidence struct {
+	EvidenceBase
+	EstimatedClusters int       `json:"estimated_clusters"`
+	ExplainedVariance []float64 `json:"explained_variance"`
+	MethodCount       int       `json:"method_count"`
+	FieldCount        int       `json:"field_count"`
+	Recommendations   string    `json:"recommendations"`
+}
+
+// ValueReceiverMutationEvidence supports a "Value Receiver Mutation" diagnosis
+type ValueReceiverMutationEvidence struct {
+	EvidenceBase
+	Method string   `json:"method"`
+	Fields []string `json:"fields"`
+}
+
+// DuplicateDeclarationEvidence supports a "Duplicate Declaration" diagnosis
+type DuplicateDeclarationEvidence struct {
+	EvidenceBase
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"`
+	Locations   []string `json:"locations"` // "file:line" of each declaration
+	Constrained bool     `json:"constrained"`
+}
+
+// SourceFiles implements Evidence, returning the file of every declaration
+func (e DuplicateDeclarationEvidence) SourceFiles() []string {
+	return locationFiles(e.Locations)
+}
+
+// locationFiles strips the ":line" suffix from "file:line" locations
+func locationFiles(locations []string) []string {
+	files := make([]string, 0, len(locations))
+	for _, location := range locations {
+		if idx := strings.LastIndex(location, ":"); idx > 0 {
+			location = location[:idx]
+		}
+		files = append(files, location)
+	}
+	return files
+}
+
+// UnusedInterfaceMethodEvidence supports an "Unused Interface Method" diagnosis
+type UnusedInterfaceMethodEvidence struct {
+	EvidenceBase
+	Interface     string   `json:"interface"`
+	Method        string   `json:"method"`
+	MethodCount   int      `json:"method_count"`
+	UnusedMethods []string `json:"unused_methods"`
+}
+
+// MagicNumberEvidence supports a "Magic Number" diagnosis
+type MagicNumberEvidence struct {
+	EvidenceBase
+	MagicNumbers int    `json:"magic_numbers"`
+	Threshold    int    `json:"threshold"`
+	Function     string `json:"function"`
+}
+
+// TypeAssertionCascadeEvidence supports a "Type Assertion Cascade" diagnosis
+type TypeAssertionCascadeEvidence struct {
+	EvidenceBase
+	Subject       string   `json:"subject"`
+	AssertedTypes []string `json:"asserted_types"`
+	Function      string   `json:"function"`
+}
+
+// TooManyReturnValuesEvidence supports a "Too Many Return Values" diagnosis
+type TooManyReturnValuesEvidence struct {
+	EvidenceBase
+	ResultCount int      `json:"result_count"`
+	ResultTypes []string `json:"result_types"`
+	Function    string   `json:"function"`
+}
+
+// LayeringViolationEvidence supports a "Layering Violation" diagnosis
+type LayeringViolationEvidence struct {
+	EvidenceBase
+	Import string `json:"import"`
+	Rule   string `json:"rule"`
+}
+
+// AllocationInLoopEvidence supports an "Allocation In Loop" diagnosis
+type AllocationInLoopEvidence struct {
+	EvidenceBase
+	Function string           `json:"function"`
+	Loops    []LoopAllocation `json:"loops"`
+}
+
+// InconsistentErrorsEvidence supports an "Inconsistent Error Handling" diagnosis
+type InconsistentErrorsEvidence struct {
+	EvidenceBase
+	ErrorStyles   map[string]int `json:"error_styles"`
+	DominantStyle string         `json:"dominant_style"`
+	DominantShare float64        `json:"dominant_share"`
+}
+
+// ScatteredImplementationEvidence supports a "Scattered Implementation" diagnosis
+type ScatteredImplementationEvidence struct {
+	EvidenceBase
+	MethodFiles []string `json:"method_files"`
+}
+
+// SourceFiles implements Evidence, returning the struct's file and every file declaring its methods
+func (e ScatteredImplementationEvidence) SourceFiles() []string {
+	files := e.EvidenceBase.SourceFiles()
+	for _, filePath := range e.MethodFiles {
+		if filePath != e.FilePath {
+			files = append(files, filePath)
+		}
+	}
+	return files
+}
+
+// PoorEncapsulationEvidence supports a "Poor Encapsulation" diagnosis
+type PoorEncapsulationEvidence struct {
+	EvidenceBase
+	ExportedDecls int     `json:"exported_decls"`
+	TotalDecls    int     `json:"total_decls"`
+	ExportedRatio float64 `json:"exported_ratio"`
+}
+
+// PossibleMapRaceEvidence supports a "Possible Map Race" diagnosis
+type PossibleMapRaceEvidence struct {
+	EvidenceBase
+	Field      string   `json:"field"`
+	Methods    []string `json:"methods"`
+	Writers    []string `json:"writers"`
+	Goroutines []string `json:"goroutines"`
+}
+
+// UnsynchronizedStateEvidence supports an "Unsynchronized Shared State" diagnosis
+type UnsynchronizedStateEvidence struct {
+	EvidenceBase
+	Struct   string                 `json:"struct"`
+	Accesses []UnsynchronizedAccess `json:"accesses"`
+}
+
+// RoleMismatchEvidence supports an "Instability Role Mismatch" diagnosis
+type RoleMismatchEvidence struct {
+	EvidenceBase
+	Role        string  `json:"role"`
+	Pattern     string  `json:"pattern"`
+	Afferent    int     `json:"afferent"`
+	Efferent    int     `json:"efferent"`
+	Instability float64 `json:"instability"`
+}
+
+// AnonymousTypeEvidence supports an "Anonymous Type" diagnosis
+type AnonymousTypeEvidence struct {
+	EvidenceBase
+	AnonymousTypes int    `json:"anonymous_types"`
+	Function       string `json:"function"`
+}
+
+// ComplexityBudgetEvidence supports a "Complexity Budget Exceeded" diagnosis
+type ComplexityBudgetEvidence struct {
+	EvidenceBase
+	Threshold        int      `json:"threshold"`
+	MaxPercent       float64  `json:"max_percent"`
+	Percent          float64  `json:"percent"`
+	FunctionCount    int      `json:"function_count"`
+	OverThreshold    int      `json:"over_threshold"`
+	ComplexFunctions []string `json:"complex_functions"`
+}
+
+// CognitiveComplexityEvidence supports a "High Cognitive Complexity" diagnosis
+type CognitiveComplexityEvidence struct {
+	EvidenceBase
+	CognitiveComplexity int    `json:"cognitive_complexity"`
+	Complexity          int    `json:"complexity"`
+	Threshold           int    `json:"threshold"`
+	Function            string `json:"function"`
+}
+
+// DeeplyNestedEvidence supports a "Deeply Nested Function" diagnosis
+type DeeplyNestedEvidence struct {
+	EvidenceBase
+	MaxNestingDepth int    `json:"max_nesting_depth"`
+	Function        string `json:"function"`
+}
+
+// MainSequenceEvidence supports a "Zone of Pain" or "Zone of Uselessness" diagnosis
+type MainSequenceEvidence struct {
+	EvidenceBase
+	AbstractTypes int     `json:"abstract_types"`
+	TotalTypes    int     `json:"total_types"`
+	Abstractness  float64 `json:"abstractness"`
+	Afferent      int     `json:"afferent"`
+	Efferent      int     `json:"efferent"`
+	Instability   float64 `json:"instability"`
+	Distance      float64 `json:"distance"`
+}
+
+// CyclicDependencyEvidence supports a "Cyclic Dependency" diagnosis
+type CyclicDependencyEvidence struct {
+	EvidenceBase
+	Packages []string `json:"packages"`
+	Ring     []string `json:"ring"`
+}
+
+// LongParameterListEvidence supports a "Long Parameter List" diagnosis
+type LongParameterListEvidence struct {
+	EvidenceBase
+	ParamCount int    `json:"param_count"`
+	Threshold  int    `json:"threshold"`
+	Function   string `json:"function"`
+}
+
+// AwkwardAPIEvidence supports an "Awkward API" diagnosis
+type AwkwardAPIEvidence struct {
+	EvidenceBase
+	SurfaceComplexity int      `json:"surface_complexity"`
+	ParamCount        int      `json:"param_count"`
+	ResultTypes       []string `json:"result_types,omitempty"`
+	Threshold         int      `json:"threshold"`
+	Function          string   `json:"function"`
+}
+
+// HighFanOutEvidence supports a "High Fan-Out" diagnosis
+type HighFanOutEvidence struct {
+	EvidenceBase
+	FanOut    int    `json:"fan_out"`
+	Afferent  int    `json:"afferent"`
+	Threshold int    `json:"threshold"`
+	Function  string `json:"function"`
+}
+
+// DeadCodeEvidence supports a "Dead Code" diagnosis
+type DeadCodeEvidence struct {
+	EvidenceBase
+	Function string `json:"function"`
+	LoC      int    `json:"loc"`
+}
+
+// UnusedTypeEvidence supports an "Unused Type" diagnosis
+type UnusedTypeEvidence struct {
+	EvidenceBase
+	Struct   string `json:"struct"`
+	Exported bool   `json:"exported"`
+}
+
+// HighStructComplexityEvidence supports a "High Struct Complexity" diagnosis
+type HighStructComplexityEvidence struct {
+	EvidenceBase
+	Struct    string `json:"struct"`
+	WMC       int    `json:"wmc"`
+	Threshold int    `json:"threshold"`
+}
+
+// UnderdocumentedPackageEvidence supports an "Underdocumented Package" diagnosis
+type UnderdocumentedPackageEvidence struct {
+	EvidenceBase
+	ExportedDecls     int     `json:"exported_decls"`
+	ExportedRatio     float64 `json:"exported_ratio"`
+	DocCommentDensity float64 `json:"doc_comment_density"`
+}
+
+// UndocumentedExportEvidence supports an "Undocumented Export" diagnosis
+type UndocumentedExportEvidence struct {
+	EvidenceBase
+	Name                string `json:"name"`
+	Kind                string `json:"kind"`
+	Line                int    `json:"line"`
+	PackageUndocumented int    `json:"package_undocumented"`
+}
+
+// FeatureEnvyEvidence supports a "Feature Envy" diagnosis
+type FeatureEnvyEvidence struct {
+	EvidenceBase
+	Struct       string   `json:"struct"`
+	Method       string   `json:"method"`
+	Line         int      `json:"line"`
+	OwnFields    int      `json:"own_fields"`
+	EnviedStruct string   `json:"envied_struct"`
+	EnviedFields []string `json:"envied_fields"`
+	Margin       int      `json:"margin"`
+}
+
+// LongFunctionEvidence supports a "Long Function" diagnosis
+type LongFunctionEvidence struct {
+	EvidenceBase
+	Function  string `json:"function"`
+	LoC       int    `json:"loc"`
+	Threshold int    `json:"threshold"`
+}
+
+// LargeStructEvidence supports a "Large Struct" diagnosis
+type LargeStructEvidence struct {
+	EvidenceBase
+	Struct          string `json:"struct"`
+	FieldCount      int    `json:"field_count"`
+	MethodCount     int    `json:"method_count"`
+	FieldThreshold  int    `json:"field_threshold"`
+	MethodThreshold int    `json:"method_threshold"`
+}
+
+// DataClumpEvidence supports a "Data Clump" diagnosis. EvidenceBase holds the first function's package and file.
+type DataClumpEvidence struct {
+	EvidenceBase
+	Parameters []string `json:"parameters"` // "name type" of each parameter in the clump, in order
+	Functions  []string `json:"functions"`  // "package.Function" of each function taking the clump
+	Locations  []string `json:"locations"`  // "file:line" of each function
+}
+
+// SourceFiles implements Evidence, returning the file of every function taking the clump
+func (e DataClumpEvidence) SourceFiles() []string {
+	return locationFiles(e.Locations)
+}
+
+// GodFunctionEvidence supports a "God Function" diagnosis
+type GodFunctionEvidence struct {
+	EvidenceBase
+	Function        string  `json:"function"`
+	Complexity      int     `json:"complexity"`
+	LoC             int     `json:"loc"`
+	DependencyCount int     `json:"dependency_count"`
+	Score           float64 `json:"score"`
+	Threshold       float64 `json:"threshold"`
+}
+
+// DuplicateCodeEvidence supports a "Duplicate Code" diagnosis. EvidenceBase holds the first function's package and file.
+type DuplicateCodeEvidence struct {
+	EvidenceBase
+	Functions  []string `json:"functions"`  // "package.Function" of each function sharing the body
+	Locations  []string `json:"locations"`  // "file:line" of each function
+	Statements int      `json:"statements"` // Statements in the shared body
+	Threshold  int      `json:"threshold"`  // Minimum statements compared
+}
+
+// SourceFiles implements Evidence, returning the file of every duplicated function
+func (e DuplicateCodeEvidence) SourceFiles() []string {
+	return locationFiles(e.Locations)
+}
+
+// GenericEvidence holds evidence of a diagnostic type this version does not know,
+// e.g. when reading a report written by a newer version
+type GenericEvidence map[string]interface{}
+
+// SourceFiles implements Evidence
+func (e GenericEvidence) SourceFiles() []string {
+	if filePath, ok := e["file_path"].(string); ok && filePath != "" {
+		return []string{filePath}
+	}
+	return nil
+}
+
+// evidenceTypes maps each known diagnostic type to its evidence struct
+var evidenceTypes = map[string]Evidence{
+	DiagnosticGodObject:               GodObjectEvidence{},
+	DiagnosticUnstableFoundation:      UnstableFoundationEvidence{},
+	DiagnosticComplexFunction:         ComplexFunctionEvidence{},
+	DiagnosticAmbiguousStruct:         AmbiguousStructEvidence{},
+	DiagnosticMethodIslands:           MethodIslandsEvidence{},
+	DiagnosticFieldClusters:           FieldClustersEvidence{},
+	DiagnosticValueReceiverMutation:   ValueReceiverMutationEvidence{},
+	DiagnosticDuplicateDeclaration:    DuplicateDeclarationEvidence{},
+	DiagnosticUnusedInterfaceMethod:   UnusedInterfaceMethodEvidence{},
+	DiagnosticUntestedComplexFunction: ComplexFunctionEvidence{},
+	DiagnosticMagicNumber:             MagicNumberEvidence{},
+	DiagnosticTypeAssertionCascade:    TypeAssertionCascadeEvidence{},
+	DiagnosticTooManyReturnValues:     TooManyReturnValuesEvidence{},
+	DiagnosticLayeringViolation:       LayeringViolationEvidence{},
+	DiagnosticAllocationInLoop:        AllocationInLoopEvidence{},
+	DiagnosticInconsistentErrors:      InconsistentErrorsEvidence{},
+	DiagnosticScatteredImplementation: ScatteredImplementationEvidence{},
+	DiagnosticPoorEncapsulation:       PoorEncapsulationEvidence{},
+	DiagnosticPossibleMapRace:         PossibleMapRaceEvidence{},
+	DiagnosticUnsynchronizedState:     UnsynchronizedStateEvidence{},
+	DiagnosticRoleMismatch:            RoleMismatchEvidence{},
+	DiagnosticAnonymousType:           AnonymousTypeEvidence{},
+	DiagnosticComplexityBudget:        ComplexityBudgetEvidence{},
+	DiagnosticCognitiveComplexity:     CognitiveComplexityEvidence{},
+	DiagnosticDeeplyNested:            DeeplyNestedEvidence{},
+	DiagnosticZoneOfPain:              MainSequenceEvidence{},
+	DiagnosticZoneOfUselessness:       MainSequenceEvidence{},
+	DiagnosticCyclicDependency:        CyclicDependencyEvidence{},
+	DiagnosticLongParameterList:       LongParameterListEvidence{},
+	DiagnosticHighFanOut:              HighFanOutEvidence{},
+	DiagnosticAwkwardAPI:              AwkwardAPIEvidence{},
+	DiagnosticDeadCode:                DeadCodeEvidence{},
+	DiagnosticHighStructComplexity:    HighStructComplexityEvidence{},
+	DiagnosticUnderdocumentedPackage:  UnderdocumentedPackageEvidence{},
+	DiagnosticUndocumentedExport:      UndocumentedExportEvidence{},
+	DiagnosticFeatureEnvy:             FeatureEnvyEvidence{},
+	DiagnosticLongFunction:            LongFunctionEvidence{},
+	DiagnosticLargeStruct:             LargeStructEvidence{},
+	DiagnosticDataClump:               DataClumpEvidence{},
+	DiagnosticGodFunction:             GodFunctionEvidence{},
+	DiagnosticDuplicateCode:           DuplicateCodeEvidence{},
+	DiagnosticShotgunSurgery:          ShotgunSurgeryEvidence{},
+	DiagnosticUnusedType:              UnusedTypeEvidence{},
+}
+
+// UnmarshalJSON decodes a diagnostic, choosing the evidence struct from its type.
+// Evidence of unknown diagnostic types is decoded as GenericEvidence.
+func (d *DiagnosticResult) UnmarshalJSON(data []byte) error {
+	type diagnosticAlias DiagnosticResult
+	var raw struct {
+		diagnosticAlias
+		Evidence json.RawMessage `json:"evidence"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*d = DiagnosticResult(raw.diagnosticAlias)
+	d.Evidence = nil
+	if len(raw.Evidence) == 0 || string(raw.Evidence) == "null" {
+		return nil
+	}
+
+	if prototype, ok := evidenceTypes[d.Type]; ok {
+		// Decode into a new value of the same struct type
+		evidence := reflect.New(reflect.TypeOf(prototype))
+		if err := json.Unmarshal(raw.Evidence, evidence.Interface()); err != nil {
+			return fmt.Errorf("failed to decode %s evidence: %w", d.Type, err)
+		}
+		d.Evidence = evidence.Elem().Interface().(Evidence)
+		return nil
+	}
+
+	var generic GenericEvidence
+	if err := json.Unmarshal(raw.Evidence, &generic); err != nil {
+		return fmt.Errorf("failed to decode %s evidence: %w", d.Type, err)
+	}
+	d.Evidence = generic
+	return nil
+}
@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// regexExcludePrefix marks an exclude pattern as a regular expression
+const regexExcludePrefix = "regex:"
+
+// excludeMatcher decides which directories parsePackages skips.
+//
+// Each pattern is either
+//   - a glob matched like .health-ignore patterns: without "/" it matches a directory name at
+//     any depth ("build", "*.pb"); with "/" it is anchored at the root ("internal/generated",
+//     "internal/**"), and "**" matches any number of directories
+//   - "regex:" followed by a regular expression that must match the whole slash-separated
+//     path relative to the root ("regex:^cmd/.*-tool$")
+//
+// A directory is excluded if any pattern matches it; everything below it is skipped too.
+type excludeMatcher struct {
+	globs   []string
+	regexes []*regexp.Regexp
+}
+
+// newExcludeMatcher validates and compiles exclude patterns
+func newExcludeMatcher(patterns []string) (*excludeMatcher, error) {
+	m := &excludeMatcher{}
+	for _, pattern := range patterns {
+		if expr, isRegex := strings.CutPrefix(pattern, regexExcludePrefix); isRegex {
+			re, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+			}
+			m.regexes = append(m.regexes, re)
+			continue
+		}
+
+		glob := strings.Trim(filepath.ToSlash(pattern), "/")
+		if glob == "" {
+			continue
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		m.globs = append(m.globs, glob)
+	}
+	return m, nil
+}
+
+// matches reports whether a directory, given by its slash-separated path relative to the root, is excluded
+func (m *excludeMatcher) matches(relPath string) bool {
+	for _, glob := range m.globs {
+		if matchPathPattern(glob, relPath) {
+			return true
+		}
+	}
+	for _, re := range m.regexes {
+		if re.MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
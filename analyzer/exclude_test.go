@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestExcludeMatcher(t *testing.T) {
+	tests := []struct {
+		pattern string
+		relPath string
+		want    bool
+	}{
+		{"*.pb", "api.pb", true},
+		{"*.pb", "proto/api.pb", true},
+		{"*.pb", "api.pbx", false},
+		{"internal/**", "internal", true},
+		{"internal/**", "internal/store/sql", true},
+		{"internal/**", "pkg/internal", false},
+		{"regex:cmd/.*-tool", "cmd/gen-tool", true},
+		{"regex:cmd/.*-tool", "cmd/gen-tool/sub", false},
+		{"regex:cmd/.*-tool", "tools/cmd/gen-tool", false},
+		{"gen", "gen", true},
+		{"gen", "pkg/gen", true},
+		{"gen", "generated", false}, // no partial matches on similar names
+		{"internal/generated", "internal/generated", true},
+		{"internal/generated", "pkg/internal/generated", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.relPath, func(t *testing.T) {
+			m, err := newExcludeMatcher([]string{tt.pattern})
+			if err != nil {
+				t.Fatalf("newExcludeMatcher: %v", err)
+			}
+			if got := m.matches(tt.relPath); got != tt.want {
+				t.Errorf("matches(%q) = %v, want %v", tt.relPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeMatcherRejectsInvalidPatterns(t *testing.T) {
+	for _, pattern := range []string{"regex:(", "[a-"} {
+		if _, err := newExcludeMatcher([]string{pattern}); err == nil {
+			t.Errorf("pattern %q accepted, want an error", pattern)
+		}
+	}
+}
+
+func TestCollectSourceDirsExcludes(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"main.go":                     "package main\n",
+		"api.pb/api.go":               "package api\n",
+		"internal/store/store.go":     "package store\n",
+		"generated/gen.go":            "package generated\n",
+		"vendor/example.com/x/x.go":   "package x\n",
+		"testdata/fixture/fixture.go": "package fixture\n",
+		".hidden/h.go":                "package hidden\n",
+	})
+
+	dirs, err := collectSourceDirs(root, []string{"*.pb", "internal/**", "gen"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+
+	if want := []string{".", "generated"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("source dirs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// adapterMethodPrefixes are name prefixes of methods that convert or build other values;
+// reading another struct's fields is their purpose, not feature envy
+var adapterMethodPrefixes = []string{"New", "From", "To", "As", "Convert", "Copy", "Clone", "Build", "Make"}
+
+// applyFeatureEnvy records, for each struct, the methods that read more fields of another struct
+// declared in the package than they access on their own receiver.
+//
+// Other structs are reached through parameters and local variables declared with the struct's type
+// (T or *T, "var x T", "x := T{...}", "x := &T{...}"); only reads count, since populating another
+// value is what constructors do. Adapter-like methods are skipped: getters and setters, names starting
+// with a conversion prefix (New, From, To, ...), methods returning the other struct's type, and methods
+// whose body is a single composite literal return.
+func applyFeatureEnvy(structs []StructResult, pkg *ast.Package, fset *token.FileSet) {
+	structFields := make(map[string]map[string]bool)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				fieldMap := make(map[string]bool)
+				for _, field := range extractFields(structType) {
+					fieldMap[field] = true
+				}
+				structFields[typeSpec.Name.Name] = fieldMap
+			}
+		}
+	}
+
+	envy := make(map[string][]FeatureEnvy)
+	for fileName, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 || funcDecl.Body == nil {
+				continue
+			}
+
+			structName := receiverTypeName(funcDecl.Recv.List[0].Type)
+			ownFields, ok := structFields[structName]
+			if !ok || isAdapterMethod(funcDecl, structFields) {
+				continue
+			}
+			recvName := ""
+			if len(funcDecl.Recv.List[0].Names) > 0 {
+				recvName = funcDecl.Recv.List[0].Names[0].Name
+			}
+
+			ownCount := 0
+			if recvName != "" && recvName != "_" {
+				ownCount = len(findFieldUsageWeighted(funcDecl.Body, recvName, ownFields))
+			}
+
+			// Fields read per other struct, across every variable of that type
+			enviedFields := make(map[string]map[string]bool)
+			for varName, typeName := range localStructVars(funcDecl, structFields) {
+				if typeName == structName {
+					continue
+				}
+				for field, weight := range findFieldUsageWeighted(funcDecl.Body, varName, structFields[typeName]) {
+					if weight&1 == 0 {
+						continue
+					}
+					if enviedFields[typeName] == nil {
+						enviedFields[typeName] = make(map[string]bool)
+					}
+					enviedFields[typeName][field] = true
+				}
+			}
+
+			// Report the most envied struct (ties broken by name)
+			best := ""
+			for typeName, fields := range enviedFields {
+				if best == "" || len(fields) > len(enviedFields[best]) ||
+					(len(fields) == len(enviedFields[best]) && typeName < best) {
+					best = typeName
+				}
+			}
+			if best == "" || len(enviedFields[best]) <= ownCount {
+				continue
+			}
+
+			fields := make([]string, 0, len(enviedFields[best]))
+			for field := range enviedFields[best] {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+
+			envy[structName] = append(envy[structName], FeatureEnvy{
+				Method:       funcDecl.Name.Name,
+				FilePath:     fileName,
+				Line:         fset.Position(funcDecl.Pos()).Line,
+				OwnFields:    ownCount,
+				EnviedStruct: best,
+				EnviedFields: fields,
+			})
+		}
+	}
+
+	for i := range structs {
+		methods := envy[structs[i].StructName]
+		sort.Slice(methods, func(a, b int) bool {
+			return methods[a].Method < methods[b].Method
+		})
+		structs[i].FeatureEnvy = methods
+	}
+}
+
+// localStructVars maps the parameters and local variables of a function declared with the type
+// of a package struct (T or *T) to that struct's name
+func localStructVars(funcDecl *ast.FuncDecl, structFields map[string]map[string]bool) map[string]string {
+	vars := make(map[string]string)
+
+	structName := func(expr ast.Expr) string {
+		if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			expr = unary.X
+		}
+		if star, ok := expr.(*ast.StarExpr); ok {
+			expr = star.X
+		}
+		if ident, ok := expr.(*ast.Ident); ok {
+			if _, isStruct := structFields[ident.Name]; isStruct {
+				return ident.Name
+			}
+		}
+		return ""
+	}
+
+	for _, field := range funcDecl.Type.Params.List {
+		if name := structName(field.Type); name != "" {
+			for _, ident := range field.Names {
+				vars[ident.Name] = name
+			}
+		}
+	}
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.ValueSpec:
+			if node.Type == nil {
+				return true
+			}
+			if name := structName(node.Type); name != "" {
+				for _, ident := range node.Names {
+					vars[ident.Name] = name
+				}
+			}
+		case *ast.AssignStmt:
+			if node.Tok != token.DEFINE || len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, rhs := range node.Rhs {
+				ident, ok := node.Lhs[i].(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if unary, ok := rhs.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+					rhs = unary.X
+				}
+				if lit, ok := rhs.(*ast.CompositeLit); ok {
+					if name := structName(lit.Type); name != "" {
+						vars[ident.Name] = name
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	return vars
+}
+
+// isAdapterMethod reports whether a method looks like a getter, setter, constructor, or conversion,
+// whose purpose is to read or build another value
+func isAdapterMethod(funcDecl *ast.FuncDecl, structFields map[string]map[string]bool) bool {
+	name := funcDecl.Name.Name
+	if isUtilityMethod(name) {
+		return true
+	}
+
+	for _, prefix := range adapterMethodPrefixes {
+		for _, p := range []string{prefix, strings.ToLower(prefix[:1]) + prefix[1:]} {
+			if name == p {
+				return true
+			}
+			if strings.HasPrefix(name, p) && unicode.IsUpper(rune(name[len(p)])) {
+				return true
+			}
+		}
+	}
+
+	// Returning a package struct means building it from the inputs
+	if funcDecl.Type.Results != nil {
+		for _, field := range funcDecl.Type.Results.List {
+			expr := field.Type
+			if star, ok := expr.(*ast.StarExpr); ok {
+				expr = star.X
+			}
+			if ident, ok := expr.(*ast.Ident); ok {
+				if _, isStruct := structFields[ident.Name]; isStruct {
+					return true
+				}
+			}
+		}
+	}
+
+	// A body that only returns a composite literal maps one value onto another
+	if len(funcDecl.Body.List) == 1 {
+		if ret, ok := funcDecl.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			result := ret.Results[0]
+			if unary, ok := result.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				result = unary.X
+			}
+			if _, ok := result.(*ast.CompositeLit); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package analyzer
+
+import "testing"
+
+func TestFeatureEnvy(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"shop/shop.go": `package shop
+
+type Order struct {
+	Qty      int
+	Price    int
+	Discount int
+}
+
+type Invoice struct {
+	tax   int
+	lines []int
+}
+
+// Total reads three fields of the order and one of its own
+func (inv *Invoice) Total(o *Order) int {
+	return o.Qty*o.Price - o.Discount + inv.tax
+}
+
+// FromOrder is an adapter: reading the order is its purpose
+func (inv *Invoice) FromOrder(o Order) {
+	inv.lines = append(inv.lines, o.Qty, o.Price, o.Discount)
+}
+
+// Fill writes the order's fields rather than reading them
+func (inv *Invoice) Fill(o *Order) {
+	o.Qty, o.Price, o.Discount = 1, 2, 3
+}
+
+// Balanced reads as many of its own fields as of the order's
+func (inv *Invoice) Balanced(o Order) int {
+	return o.Qty + o.Price + o.Discount + inv.tax + len(inv.lines)
+}
+`,
+	}, nil)
+
+	invoice := findStruct(t, findPackage(t, report, "shop"), "Invoice")
+	envious := make(map[string]FeatureEnvy)
+	for _, envy := range invoice.FeatureEnvy {
+		envious[envy.Method] = envy
+	}
+	if _, ok := envious["FromOrder"]; ok {
+		t.Errorf("adapter method FromOrder recorded as feature envy")
+	}
+	if _, ok := envious["Fill"]; ok {
+		t.Errorf("Fill only writes the order's fields, recorded as feature envy")
+	}
+
+	total, ok := envious["Total"]
+	if !ok {
+		t.Fatalf("Total not recorded as feature envy: %+v", invoice.FeatureEnvy)
+	}
+	if total.EnviedStruct != "Order" || len(total.EnviedFields) != 3 || total.OwnFields != 1 {
+		t.Errorf("Total envy = %+v, want 3 Order fields against 1 own field", total)
+	}
+
+	diagnostics := diagnosticsOfType(report, DiagnosticFeatureEnvy)
+	if len(diagnostics) != 1 || diagnostics[0].TargetName != "shop.Invoice.Total" {
+		t.Errorf("Feature Envy diagnostics = %+v, want one for Invoice.Total", diagnostics)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"go/ast"
 	"go/token"
 	"math"
+	"math/rand"
 	"sort"
 )
 
-// AnalyzeFieldMatrix analyzes method×field usage patterns using matrix analysis and PCA
-func AnalyzeFieldMatrix(structName string, structType *ast.StructType, file *ast.File, fset *token.FileSet, fields []string) *FieldMatrixAnalysis {
+// AnalyzeFieldMatrix analyzes method×field usage patterns using matrix analysis and PCA.
+// rng seeds the power iteration start vectors; nil uses a fixed uniform start vector.
+func AnalyzeFieldMatrix(structName string, structType *ast.StructType, file *ast.File, fset *token.FileSet, fields []string, rng *rand.Rand) *FieldMatrixAnalysis {
 	// Return empty result if too few fields (PCA unstable)
 	if len(fields) < 3 {
 		return &FieldMatrixAnalysis{
@@ -76,7 +78,7 @@ func AnalyzeFieldMatrix(structName string, structType *ast.StructType, file *ast
 	}
 
 	// Perform PCA to estimate number of clusters
-	estimatedClusters, explainedVariance := estimateClustersViaPCA(matrix)
+	estimatedClusters, explainedVariance := estimateClustersViaPCA(matrix, rng)
 
 	// Generate recommendations
 	recommendations := generateRecommendations(estimatedClusters, len(methodNames), len(fields), explainedVariance)
@@ -127,19 +129,9 @@ func extractMethodsWithFields(structName string, file *ast.File, structFields []
 		}
 
 		for _, recv := range funcDecl.Recv.List {
-			var recvTypeName string
+			recvTypeName := receiverTypeName(recv.Type)
 			var recvName string
 
-			// Get receiver type name
-			switch t := recv.Type.(type) {
-			case *ast.Ident:
-				recvTypeName = t.Name
-			case *ast.StarExpr:
-				if ident, ok := t.X.(*ast.Ident); ok {
-					recvTypeName = ident.Name
-				}
-			}
-
 			// Get receiver variable name
 			if len(recv.Names) > 0 {
 				recvName = recv.Names[0].Name
@@ -183,19 +175,9 @@ func extractMethodsWithFieldsWeighted(structName string, file *ast.File, structF
 		}
 
 		for _, recv := range funcDecl.Recv.List {
-			var recvTypeName string
+			recvTypeName := receiverTypeName(recv.Type)
 			var recvName string
 
-			// Get receiver type name
-			switch t := recv.Type.(type) {
-			case *ast.Ident:
-				recvTypeName = t.Name
-			case *ast.StarExpr:
-				if ident, ok := t.X.(*ast.Ident); ok {
-					recvTypeName = ident.Name
-				}
-			}
-
 			// Get receiver variable name
 			if len(recv.Names) > 0 {
 				recvName = recv.Names[0].Name
@@ -267,6 +249,18 @@ func findFieldUsageWeighted(body *ast.BlockStmt, recvName string, fieldMap map[s
 			return false // Don't traverse children again
 		}
 
+		// Check for increments/decrements (read+write)
+		if incDec, ok := n.(*ast.IncDecStmt); ok {
+			if selector, ok := incDec.X.(*ast.SelectorExpr); ok {
+				if ident, ok := selector.X.(*ast.Ident); ok {
+					if ident.Name == recvName && fieldMap[selector.Sel.Name] {
+						fieldUsage[selector.Sel.Name] = 3
+						return false
+					}
+				}
+			}
+		}
+
 		// Check for reads (selector expressions not in assignments)
 		if selector, ok := n.(*ast.SelectorExpr); ok {
 			if ident, ok := selector.X.(*ast.Ident); ok {
@@ -327,7 +321,7 @@ func buildUsageMatrix(methods []methodFieldUsage, fields []string) ([][]int, []s
 }
 
 // estimateClustersViaPCA estimates the number of responsibility clusters using PCA
-func estimateClustersViaPCA(matrix [][]int) (int, []float64) {
+func estimateClustersViaPCA(matrix [][]int, rng *rand.Rand) (int, []float64) {
 	// Convert int matrix to float64 for calculations
 	floatMatrix := make([][]float64, len(matrix))
 	for i := range matrix {
@@ -344,7 +338,7 @@ func estimateClustersViaPCA(matrix [][]int) (int, []float64) {
 	covMatrix := computeCovarianceMatrix(centeredMatrix)
 
 	// Compute eigenvalues (simplified approach using power iteration)
-	eigenvalues := computeTopEigenvalues(covMatrix, 5)
+	eigenvalues := computeTopEigenvalues(covMatrix, 5, rng)
 
 	// Calculate explained variance ratios
 	totalVariance := 0.0
@@ -430,7 +424,7 @@ func computeCovarianceMatrix(matrix [][]float64) [][]float64 {
 }
 
 // computeTopEigenvalues computes the top k eigenvalues using power iteration
-func computeTopEigenvalues(matrix [][]float64, k int) []float64 {
+func computeTopEigenvalues(matrix [][]float64, k int, rng *rand.Rand) []float64 {
 	if len(matrix) == 0 {
 		return nil
 	}
@@ -445,7 +439,7 @@ func computeTopEigenvalues(matrix [][]float64, k int) []float64 {
 
 	for iter := 0; iter < k; iter++ {
 		// Use power iteration to find dominant eigenvalue
-		eigenvalue := powerIteration(workMatrix, 100)
+		eigenvalue := powerIteration(workMatrix, 100, rng)
 
 		if eigenvalue <= 1e-10 {
 			break // No more significant eigenvalues
@@ -461,19 +455,31 @@ func computeTopEigenvalues(matrix [][]float64, k int) []float64 {
 	return eigenvalues
 }
 
-// powerIteration finds the dominant eigenvalue using power iteration
-func powerIteration(matrix [][]float64, maxIter int) float64 {
+// powerIteration finds the dominant eigenvalue using power iteration.
+// The start vector is uniform when rng is nil, and a random unit vector drawn from rng otherwise.
+func powerIteration(matrix [][]float64, maxIter int, rng *rand.Rand) float64 {
 	if len(matrix) == 0 {
 		return 0
 	}
 
 	n := len(matrix)
 
-	// Initialize with random vector
+	// Initialize the start vector
 	v := make([]float64, n)
 	for i := range v {
 		v[i] = 1.0 / math.Sqrt(float64(n))
 	}
+	if rng != nil {
+		norm := 0.0
+		for i := range v {
+			v[i] = rng.Float64() + 0.1 // Keep every component positive so no eigenvector is missed
+			norm += v[i] * v[i]
+		}
+		norm = math.Sqrt(norm)
+		for i := range v {
+			v[i] /= norm
+		}
+	}
 
 	var eigenvalue float64
 
@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFixture writes files (relative path to content) under a temporary directory and returns it.
+// A go.mod for module example.com/app is added unless the files include one.
+func writeFixture(t testing.TB, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if _, ok := files["go.mod"]; !ok {
+		files = mergeFiles(files, map[string]string{"go.mod": "module example.com/app\n\ngo 1.24\n"})
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// mergeFiles returns a new fixture holding the files of all the given fixtures; later ones win
+func mergeFiles(fixtures ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, files := range fixtures {
+		for name, content := range files {
+			merged[name] = content
+		}
+	}
+	return merged
+}
+
+// analyzeFixture writes a fixture and analyzes it with the given configuration (nil for defaults)
+func analyzeFixture(t *testing.T, files map[string]string, cfg *Config) *Report {
+	t.Helper()
+
+	report, err := AnalyzeWithOptions(writeFixture(t, files), AnalyzeOptions{Config: cfg})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	return report
+}
+
+// findPackage returns the package result with the given path, failing the test if it is missing
+func findPackage(t *testing.T, report *Report, path string) *PackageResult {
+	t.Helper()
+
+	for i := range report.Packages {
+		if report.Packages[i].Path == path {
+			return &report.Packages[i]
+		}
+	}
+	t.Fatalf("package %q not found", path)
+	return nil
+}
+
+// findFunction returns the function result with the given name, failing the test if it is missing
+func findFunction(t *testing.T, pkg *PackageResult, name string) *FunctionResult {
+	t.Helper()
+
+	for i := range pkg.Functions {
+		if pkg.Functions[i].FuncName == name {
+			return &pkg.Functions[i]
+		}
+	}
+	t.Fatalf("function %q not found in package %q", name, pkg.Path)
+	return nil
+}
+
+// findStruct returns the struct result with the given name, failing the test if it is missing
+func findStruct(t *testing.T, pkg *PackageResult, name string) *StructResult {
+	t.Helper()
+
+	for i := range pkg.Structs {
+		if pkg.Structs[i].StructName == name {
+			return &pkg.Structs[i]
+		}
+	}
+	t.Fatalf("struct %q not found in package %q", name, pkg.Path)
+	return nil
+}
+
+// diagnosticsOfType returns the diagnostics of one type
+func diagnosticsOfType(report *Report, diagnosticType string) []DiagnosticResult {
+	var results []DiagnosticResult
+	for _, d := range report.Diagnostics {
+		if d.Type == diagnosticType {
+			results = append(results, d)
+		}
+	}
+	return results
+}
+
+// parseFunc parses src, the declarations of a file in package p, and returns its first function
+func parseFunc(t *testing.T, src string) *ast.FuncDecl {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "p.go", "package p\n\n"+src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			return funcDecl
+		}
+	}
+	t.Fatal("no function declared")
+	return nil
+}
@@ -0,0 +1,34 @@
+package analyzer
+
+// God Function scoring. Each metric is divided by its reference value, so 1.0 means "as high as
+// the reference"; the weighted sum of the three ratios is the function's score.
+// Complexity weighs most because branching is what makes a long function hard to follow.
+const (
+	godFunctionComplexityRef    = 10.0 // Cyclomatic complexity
+	godFunctionLoCRef           = 50.0 // Body lines
+	godFunctionDependenciesRef  = 5.0  // Distinct packages used (DependencyCount)
+	godFunctionComplexityWeight = 0.4
+	godFunctionLoCWeight        = 0.3
+	godFunctionDepsWeight       = 0.3
+
+	// godFunctionMinRatio is the ratio every metric must reach, so one extreme metric
+	// (a long but linear function) cannot carry the score alone
+	godFunctionMinRatio = 0.7
+
+	// godFunctionScoreThreshold is the score at which a function is a God Function
+	godFunctionScoreThreshold = 1.0
+)
+
+// godFunctionScore returns the weighted God Function score of a function, and false if
+// any of its metrics is below godFunctionMinRatio of the reference
+func godFunctionScore(f FunctionResult) (float64, bool) {
+	complexity := float64(f.Complexity) / godFunctionComplexityRef
+	loc := float64(f.LoC) / godFunctionLoCRef
+	deps := float64(f.DependencyCount) / godFunctionDependenciesRef
+
+	score := godFunctionComplexityWeight*complexity + godFunctionLoCWeight*loc + godFunctionDepsWeight*deps
+	if complexity < godFunctionMinRatio || loc < godFunctionMinRatio || deps < godFunctionMinRatio {
+		return score, false
+	}
+	return score, true
+}
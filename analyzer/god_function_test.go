@@ -0,0 +1,43 @@
+package analyzer
+
+import "testing"
+
+func TestDetectGodFunctions(t *testing.T) {
+	packages := []PackageResult{{
+		Name: "app",
+		Path: "app",
+		Functions: []FunctionResult{
+			// Every metric above its reference: 0.4*1.2 + 0.3*1.2 + 0.3*1.2 = 1.2
+			{FuncName: "Everything", Complexity: 12, LoC: 60, DependencyCount: 6},
+			// Very long but linear and self-contained: the other ratios are below the minimum
+			{FuncName: "LongOnly", Complexity: 2, LoC: 300, DependencyCount: 1},
+			// Every metric close to its reference, but the score stays at 0.8
+			{FuncName: "Busy", Complexity: 8, LoC: 40, DependencyCount: 4},
+		},
+	}}
+
+	results := detectGodFunctions(packages)
+	if len(results) != 1 || results[0].TargetName != "app.Everything" {
+		t.Fatalf("got %+v, want one diagnostic for app.Everything", results)
+	}
+
+	evidence, ok := results[0].Evidence.(GodFunctionEvidence)
+	if !ok {
+		t.Fatalf("evidence is %T, want GodFunctionEvidence", results[0].Evidence)
+	}
+	if evidence.Complexity != 12 || evidence.LoC != 60 || evidence.DependencyCount != 6 {
+		t.Errorf("evidence = %+v, want complexity 12, LoC 60, and 6 dependencies", evidence)
+	}
+	if evidence.Score < 1.19 || evidence.Score > 1.21 {
+		t.Errorf("score = %.3f, want 1.2", evidence.Score)
+	}
+}
+
+func TestGodFunctionScoreRequiresEveryMetric(t *testing.T) {
+	if score, ok := godFunctionScore(FunctionResult{Complexity: 2, LoC: 300, DependencyCount: 1}); ok {
+		t.Errorf("long-only function qualified with score %.2f", score)
+	}
+	if _, ok := godFunctionScore(FunctionResult{Complexity: 7, LoC: 35, DependencyCount: 4}); !ok {
+		t.Errorf("function at 70%% of every reference did not qualify")
+	}
+}
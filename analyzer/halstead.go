@@ -0,0 +1,172 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+)
+
+// halsteadCounts holds the occurrences of each distinct operator and operand of a function
+type halsteadCounts struct {
+	operators map[string]int
+	operands  map[string]int
+}
+
+// sumCounts adds up the occurrences in a count map
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+// calculateHalstead calculates a function's Halstead volume and effort:
+//
+//	volume V = N * log2(n)   with length N = N1 + N2 and vocabulary n = n1 + n2
+//	effort E = D * V         with difficulty D = (n1 / 2) * (N2 / n2)
+//
+// where n1/n2 are the distinct operators/operands and N1/N2 their total occurrences.
+//
+// See countHalstead for which AST nodes are operators and operands.
+func calculateHalstead(funcDecl *ast.FuncDecl) (volume float64, effort float64) {
+	counts := countHalstead(funcDecl)
+
+	n1, n2 := len(counts.operators), len(counts.operands)
+	totalOperators, totalOperands := sumCounts(counts.operators), sumCounts(counts.operands)
+	if n1+n2 < 2 || n2 == 0 {
+		return 0, 0
+	}
+
+	volume = float64(totalOperators+totalOperands) * math.Log2(float64(n1+n2))
+	difficulty := float64(n1) / 2 * float64(totalOperands) / float64(n2)
+	return volume, difficulty * volume
+}
+
+// countHalstead counts the operators and operands of a function declaration, signature included.
+//
+// Operands are identifiers (variables, functions, types, fields; keyed by name) and literals
+// (keyed by kind and value, so 1 and "1" differ).
+//
+// Operators are:
+//   - arithmetic, logical, comparison, bitwise, and channel operators (+, &&, ==, <<, <-, ...),
+//     unary operators (!, -, &, ^), and pointer dereference or pointer types (*)
+//   - assignment and increment operators (=, :=, +=, ++, ...)
+//   - keywords introducing statements and declarations: func, return, if, else, for, range,
+//     switch, case, default, select, go, defer, break, continue, goto, fallthrough, var, const,
+//     type, struct, interface, map, chan
+//   - punctuation forming expressions: calls "()", indexing "[]", slicing "[:]", selectors ".",
+//     type assertions ".()", composite literals "{}", key-value pairs ":", array/slice types "[]T",
+//     and variadic "..."
+//
+// Parentheses of grouping, braces of blocks, and commas are not counted.
+func countHalstead(funcDecl *ast.FuncDecl) halsteadCounts {
+	counts := halsteadCounts{
+		operators: make(map[string]int),
+		operands:  make(map[string]int),
+	}
+	operator := func(op string) { counts.operators[op]++ }
+
+	counts.operators["func"]++
+
+	ast.Inspect(funcDecl, func(n ast.Node) bool {
+		switch x := n.(type) {
+		// Operands
+		case *ast.Ident:
+			counts.operands[x.Name]++
+		case *ast.BasicLit:
+			counts.operands[x.Kind.String()+":"+x.Value]++
+
+		// Expressions
+		case *ast.BinaryExpr:
+			operator(x.Op.String())
+		case *ast.UnaryExpr:
+			operator("unary " + x.Op.String())
+		case *ast.StarExpr:
+			operator("*")
+		case *ast.CallExpr:
+			operator("()")
+			if x.Ellipsis.IsValid() {
+				operator("...")
+			}
+		case *ast.IndexExpr, *ast.IndexListExpr:
+			operator("[]")
+		case *ast.SliceExpr:
+			operator("[:]")
+		case *ast.SelectorExpr:
+			operator(".")
+		case *ast.TypeAssertExpr:
+			operator(".()")
+		case *ast.CompositeLit:
+			operator("{}")
+		case *ast.KeyValueExpr:
+			operator(":")
+		case *ast.FuncLit:
+			operator("func")
+
+		// Types
+		case *ast.ArrayType:
+			operator("[]T")
+		case *ast.Ellipsis:
+			operator("...")
+		case *ast.MapType:
+			operator("map")
+		case *ast.ChanType:
+			operator("chan")
+		case *ast.StructType:
+			operator("struct")
+		case *ast.InterfaceType:
+			operator("interface")
+
+		// Statements
+		case *ast.AssignStmt:
+			operator(x.Tok.String())
+		case *ast.IncDecStmt:
+			operator(x.Tok.String())
+		case *ast.SendStmt:
+			operator("<-")
+		case *ast.ReturnStmt:
+			operator("return")
+		case *ast.IfStmt:
+			operator("if")
+			if x.Else != nil {
+				operator("else")
+			}
+		case *ast.ForStmt:
+			operator("for")
+		case *ast.RangeStmt:
+			operator("for")
+			operator("range")
+			if x.Tok != token.ILLEGAL {
+				operator(x.Tok.String())
+			}
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			operator("switch")
+		case *ast.SelectStmt:
+			operator("select")
+		case *ast.CaseClause:
+			if x.List == nil {
+				operator("default")
+			} else {
+				operator("case")
+			}
+		case *ast.CommClause:
+			if x.Comm == nil {
+				operator("default")
+			} else {
+				operator("case")
+			}
+		case *ast.GoStmt:
+			operator("go")
+		case *ast.DeferStmt:
+			operator("defer")
+		case *ast.BranchStmt:
+			operator(x.Tok.String())
+		case *ast.GenDecl:
+			operator(x.Tok.String())
+		}
+		return true
+	})
+
+	return counts
+}
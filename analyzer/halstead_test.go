@@ -0,0 +1,29 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCountHalstead(t *testing.T) {
+	// Operators: func, return, +, * (n1 = 4, N1 = 5 with + twice)
+	// Operands: scale, a, b, k, int, 2 (n2 = 6, N2 = 10 with a, b, k, and int twice)
+	funcDecl := parseFunc(t, `func scale(a, b, k int) int {
+	return a*k + b + 2
+}`)
+
+	counts := countHalstead(funcDecl)
+	n1, n2 := len(counts.operators), len(counts.operands)
+	N1, N2 := sumCounts(counts.operators), sumCounts(counts.operands)
+	if n1 != 4 || n2 != 6 || N1 != 5 || N2 != 10 {
+		t.Errorf("n1=%d n2=%d N1=%d N2=%d, want 4 6 5 10 (operators %v, operands %v)",
+			n1, n2, N1, N2, counts.operators, counts.operands)
+	}
+
+	volume, effort := calculateHalstead(funcDecl)
+	wantVolume := 15 * math.Log2(10)
+	wantEffort := (4.0 / 2 * 10.0 / 6) * wantVolume
+	if math.Abs(volume-wantVolume) > 1e-6 || math.Abs(effort-wantEffort) > 1e-6 {
+		t.Errorf("volume %.3f, effort %.3f, want %.3f and %.3f", volume, effort, wantVolume, wantEffort)
+	}
+}
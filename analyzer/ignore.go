@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the file at the project root listing paths whose diagnostics are suppressed
+const IgnoreFileName = ".health-ignore"
+
+// IgnoreList holds glob patterns for files that are analyzed (and counted for coupling)
+// but never reported in diagnostics.
+//
+// Patterns are matched against slash-separated paths relative to the project root:
+//   - a pattern without "/" matches any file or directory name at any depth ("*_gen.go", "legacy")
+//   - a pattern with "/" is anchored at the root ("internal/old/*.go")
+//   - "**" matches any number of directories ("pkg/**/mock_*.go")
+//   - a pattern matching a directory matches every file below it
+//
+// Blank lines and lines starting with "#" are ignored.
+type IgnoreList struct {
+	root     string
+	patterns []string
+}
+
+// LoadIgnoreFile reads IgnoreFileName from the project root, returning an empty list if it is absent
+func LoadIgnoreFile(rootPath string) (*IgnoreList, error) {
+	absRoot, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	list := &IgnoreList{root: absRoot}
+
+	data, err := os.ReadFile(filepath.Join(rootPath, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		pattern := strings.Trim(filepath.ToSlash(line), "/")
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern %q: %w", IgnoreFileName, lineNum, line, err)
+		}
+		list.patterns = append(list.patterns, pattern)
+	}
+
+	return list, nil
+}
+
+// Matches reports whether a file path (absolute or relative to the project root) is ignored
+func (l *IgnoreList) Matches(filePath string) bool {
+	if l == nil || len(l.patterns) == 0 || filePath == "" {
+		return false
+	}
+
+	relPath := filePath
+	if filepath.IsAbs(filePath) {
+		rel, err := filepath.Rel(l.root, filePath)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return false
+		}
+		relPath = rel
+	}
+
+	for _, pattern := range l.patterns {
+		if matchPathPattern(pattern, relPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchPathPattern matches a slash-separated relative path against a pattern.
+// A pattern without "/" matches any name at any depth; otherwise it is anchored at the root.
+// "**" matches any number of directories, and a pattern matching a directory matches everything below it.
+func matchPathPattern(pattern string, relPath string) bool {
+	segments := strings.Split(filepath.ToSlash(relPath), "/")
+	patternSegments := strings.Split(pattern, "/")
+
+	// Unanchored patterns may match starting at any directory level
+	if !strings.Contains(pattern, "/") {
+		for start := range segments {
+			if matchSegments(patternSegments, segments[start:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return matchSegments(patternSegments, segments)
+}
+
+// matchSegments matches pattern segments against a prefix of the path segments.
+// Matching a prefix means a pattern naming a directory also matches everything below it.
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(segments); skip++ {
+			if matchSegments(pattern[1:], segments[skip:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// filterIgnoredDiagnostics drops diagnostics whose source file matches the ignore list.
+// Diagnostics spanning several files (duplicate declarations) are dropped only if every location is ignored;
+// package-level diagnostics without a file are always kept.
+func filterIgnoredDiagnostics(diagnostics []DiagnosticResult, ignore *IgnoreList) []DiagnosticResult {
+	if ignore == nil || len(ignore.patterns) == 0 {
+		return diagnostics
+	}
+
+	var kept []DiagnosticResult
+	for _, d := range diagnostics {
+		if !isIgnoredDiagnostic(d, ignore) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// isIgnoredDiagnostic reports whether all source files referenced by the diagnostic are ignored
+func isIgnoredDiagnostic(d DiagnosticResult, ignore *IgnoreList) bool {
+	if d.Evidence == nil {
+		return false
+	}
+
+	files := d.Evidence.SourceFiles()
+	if len(files) == 0 {
+		return false
+	}
+
+	for _, filePath := range files {
+		if !ignore.Matches(filePath) {
+			return false
+		}
+	}
+	return true
+}
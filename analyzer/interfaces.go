@@ -0,0 +1,476 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// implicitlyCalledMethods are methods commonly invoked by the standard library through
+// interface conversion (fmt, io, net/http, sort, encoding/json), so they rarely appear as call sites
+var implicitlyCalledMethods = map[string]bool{
+	"Error":         true,
+	"String":        true,
+	"GoString":      true,
+	"Format":        true,
+	"Read":          true,
+	"Write":         true,
+	"Close":         true,
+	"ServeHTTP":     true,
+	"Len":           true,
+	"Less":          true,
+	"Swap":          true,
+	"MarshalJSON":   true,
+	"UnmarshalJSON": true,
+	"MarshalText":   true,
+	"UnmarshalText": true,
+}
+
+// ExtractInterfaces extracts all interface type declarations in a package
+func ExtractInterfaces(pkg *ast.Package, fset *token.FileSet) []InterfaceResult {
+	var results []InterfaceResult
+
+	for fileName, file := range pkg.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			typeSpec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+
+			ifaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				return true
+			}
+
+			result := InterfaceResult{
+				Name:     typeSpec.Name.Name,
+				FilePath: fileName,
+				Methods:  []string{},
+			}
+
+			for _, field := range ifaceType.Methods.List {
+				if _, isFunc := field.Type.(*ast.FuncType); isFunc {
+					// Explicit method
+					for _, name := range field.Names {
+						result.Methods = append(result.Methods, name.Name)
+					}
+					continue
+				}
+
+				// Embedded interface or type constraint element
+				switch t := field.Type.(type) {
+				case *ast.Ident:
+					result.Embeds = append(result.Embeds, t.Name)
+				case *ast.SelectorExpr:
+					if ident, ok := t.X.(*ast.Ident); ok {
+						result.Embeds = append(result.Embeds, ident.Name+"."+t.Sel.Name)
+					}
+				}
+			}
+
+			results = append(results, result)
+			return true
+		})
+	}
+
+	// Sort for consistent output
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+}
+
+// collectSelectorNames collects every selector name (x.Name) used anywhere in the project.
+// Without type information a method is considered used if any selector shares its name.
+func collectSelectorNames(packages map[string]*ParsedPackage) map[string]bool {
+	names := make(map[string]bool)
+
+	for _, pkg := range packages {
+		files := make([]*ast.File, 0, len(pkg.Package.Files)+len(pkg.GeneratedFiles))
+		for _, file := range pkg.Package.Files {
+			files = append(files, file)
+		}
+		for _, file := range pkg.GeneratedFiles {
+			files = append(files, file)
+		}
+
+		for _, file := range files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if selector, ok := n.(*ast.SelectorExpr); ok {
+					names[selector.Sel.Name] = true
+				}
+				return true
+			})
+		}
+	}
+
+	return names
+}
+
+// markUnusedInterfaceMethods records, for each interface, the methods no caller in the project uses
+func markUnusedInterfaceMethods(packageResults []PackageResult, usedSelectors map[string]bool) {
+	for i := range packageResults {
+		for j := range packageResults[i].Interfaces {
+			iface := &packageResults[i].Interfaces[j]
+			iface.UnusedMethods = nil
+
+			for _, method := range iface.Methods {
+				if !usedSelectors[method] && !implicitlyCalledMethods[method] {
+					iface.UnusedMethods = append(iface.UnusedMethods, method)
+				}
+			}
+		}
+	}
+}
+
+// methodShape is a method signature reduced to what structural matching compares:
+// parameter and result counts (types are not resolved without type information)
+type methodShape struct {
+	params  int
+	results int
+}
+
+// typeRef is a reference to a named project type, keyed by "pkgPath.Name"
+type typeRef struct {
+	key     string
+	pointer bool
+}
+
+// namedType holds the declarations needed to build a named type's method sets
+type namedType struct {
+	pkgPath     string
+	name        string
+	isInterface bool
+	unresolved  bool                   // Interfaces only: embeds a type outside the project or a type constraint
+	methods     map[string]methodShape // Explicit interface methods or value-receiver methods
+	ptrMethods  map[string]methodShape // Pointer-receiver methods
+	embeds      []typeRef              // Embedded interfaces or embedded struct fields
+}
+
+// markInterfaceImplementers records, for each interface, the project types whose method sets
+// contain all of its methods, matched structurally by name and parameter/result counts.
+//
+// Embedded interfaces are expanded; an interface embedding a type outside the project, or
+// containing type constraint elements, cannot be matched and gets no implementers. Methods with
+// value receivers belong to both T and *T, pointer receivers only to *T, so an implementer is
+// listed as "T" when its value implements the interface and as "*T" when only the pointer does.
+// Methods promoted through embedded struct fields count. Empty interfaces are not matched.
+func markInterfaceImplementers(packageResults []PackageResult, packages map[string]*ParsedPackage, modules projectModules) {
+	types := collectNamedTypes(packages, modules)
+
+	var concrete []string
+	for key, t := range types {
+		if !t.isInterface {
+			concrete = append(concrete, key)
+		}
+	}
+	sort.Strings(concrete)
+
+	for i := range packageResults {
+		pkg := &packageResults[i]
+		for j := range pkg.Interfaces {
+			iface := &pkg.Interfaces[j]
+			iface.Implementers = nil
+
+			required, ok := interfaceMethodSet(types, qualifiedTypeName(pkg.Path, iface.Name), make(map[string]bool))
+			if !ok || len(required) == 0 {
+				continue
+			}
+
+			for _, key := range concrete {
+				t := types[key]
+				switch {
+				case hasMethods(methodSet(types, key, false, make(map[string]bool)), required):
+					iface.Implementers = append(iface.Implementers, qualifiedTypeName(t.pkgPath, t.name))
+				case hasMethods(methodSet(types, key, true, make(map[string]bool)), required):
+					iface.Implementers = append(iface.Implementers, "*"+qualifiedTypeName(t.pkgPath, t.name))
+				}
+			}
+		}
+	}
+}
+
+// qualifiedTypeName returns "pkgPath.Name", or just the name for the root package
+func qualifiedTypeName(pkgPath string, name string) string {
+	if pkgPath == "" {
+		return name
+	}
+	return pkgPath + "." + name
+}
+
+// collectNamedTypes indexes the package-level named types of every project package,
+// including generated files, with their methods and embedded types
+func collectNamedTypes(packages map[string]*ParsedPackage, modules projectModules) map[string]*namedType {
+	types := make(map[string]*namedType)
+
+	// Package names by path, to resolve imports declared without an alias
+	packageNames := make(map[string]string)
+	for pkgPath, pkg := range packages {
+		packageNames[pkgPath] = pkg.Package.Name
+	}
+
+	lookup := func(pkgPath, name string) *namedType {
+		key := qualifiedTypeName(pkgPath, name)
+		t, ok := types[key]
+		if !ok {
+			t = &namedType{pkgPath: pkgPath, name: name}
+			types[key] = t
+		}
+		return t
+	}
+
+	for pkgPath, pkg := range packages {
+		if strings.HasSuffix(pkgPath, "_test") {
+			continue
+		}
+
+		files := make([]*ast.File, 0, len(pkg.Package.Files)+len(pkg.GeneratedFiles))
+		for _, file := range pkg.Package.Files {
+			files = append(files, file)
+		}
+		for _, file := range pkg.GeneratedFiles {
+			files = append(files, file)
+		}
+
+		for _, file := range files {
+			imports := fileImportPaths(file, modules, packageNames)
+
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.GenDecl:
+					if d.Tok != token.TYPE {
+						continue
+					}
+					for _, spec := range d.Specs {
+						typeSpec := spec.(*ast.TypeSpec)
+						if typeSpec.Assign.IsValid() {
+							// Aliases share the aliased type's method set
+							continue
+						}
+						t := lookup(pkgPath, typeSpec.Name.Name)
+						collectTypeBody(t, typeSpec.Type, pkgPath, imports)
+					}
+
+				case *ast.FuncDecl:
+					if d.Recv == nil || len(d.Recv.List) == 0 {
+						continue
+					}
+					recvType := d.Recv.List[0].Type
+					recvName := receiverTypeName(recvType)
+					if recvName == "" {
+						continue
+					}
+					_, pointer := recvType.(*ast.StarExpr)
+					t := lookup(pkgPath, recvName)
+					shape := funcShape(d.Type)
+					if pointer {
+						if t.ptrMethods == nil {
+							t.ptrMethods = make(map[string]methodShape)
+						}
+						t.ptrMethods[d.Name.Name] = shape
+					} else {
+						if t.methods == nil {
+							t.methods = make(map[string]methodShape)
+						}
+						t.methods[d.Name.Name] = shape
+					}
+				}
+			}
+		}
+	}
+
+	return types
+}
+
+// collectTypeBody records the explicit methods and embedded types of a type declaration
+func collectTypeBody(t *namedType, expr ast.Expr, pkgPath string, imports map[string]string) {
+	switch typ := expr.(type) {
+	case *ast.InterfaceType:
+		t.isInterface = true
+		if t.methods == nil {
+			t.methods = make(map[string]methodShape)
+		}
+		for _, field := range typ.Methods.List {
+			if funcType, isFunc := field.Type.(*ast.FuncType); isFunc {
+				for _, name := range field.Names {
+					t.methods[name.Name] = funcShape(funcType)
+				}
+				continue
+			}
+			ref, ok := resolveTypeRef(field.Type, pkgPath, imports)
+			if !ok || ref.pointer {
+				t.unresolved = true
+				continue
+			}
+			t.embeds = append(t.embeds, ref)
+		}
+
+	case *ast.StructType:
+		for _, field := range typ.Fields.List {
+			if len(field.Names) > 0 {
+				continue
+			}
+			if ref, ok := resolveTypeRef(field.Type, pkgPath, imports); ok {
+				t.embeds = append(t.embeds, ref)
+			}
+		}
+	}
+}
+
+// fileImportPaths maps the names a file uses for imported project packages to their package paths
+func fileImportPaths(file *ast.File, modules projectModules, packageNames map[string]string) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		pkgPath, ok := modules.packagePath(importPath)
+		if !ok {
+			continue
+		}
+
+		name := packageNames[pkgPath]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name != "" && name != "_" && name != "." {
+			imports[name] = pkgPath
+		}
+	}
+	return imports
+}
+
+// resolveTypeRef resolves a (possibly pointer or generic) type expression naming a project type
+func resolveTypeRef(expr ast.Expr, pkgPath string, imports map[string]string) (typeRef, bool) {
+	pointer := false
+	if star, ok := expr.(*ast.StarExpr); ok {
+		pointer = true
+		expr = star.X
+	}
+	switch idx := expr.(type) {
+	case *ast.IndexExpr:
+		expr = idx.X
+	case *ast.IndexListExpr:
+		expr = idx.X
+	}
+
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return typeRef{key: qualifiedTypeName(pkgPath, t.Name), pointer: pointer}, true
+	case *ast.SelectorExpr:
+		ident, ok := t.X.(*ast.Ident)
+		if !ok {
+			return typeRef{}, false
+		}
+		importedPath, ok := imports[ident.Name]
+		if !ok {
+			return typeRef{}, false
+		}
+		return typeRef{key: qualifiedTypeName(importedPath, t.Sel.Name), pointer: pointer}, true
+	}
+	return typeRef{}, false
+}
+
+// funcShape counts the parameters and results of a function type
+func funcShape(funcType *ast.FuncType) methodShape {
+	return methodShape{
+		params:  fieldCount(funcType.Params),
+		results: fieldCount(funcType.Results),
+	}
+}
+
+// fieldCount counts the entries of a parameter or result list ("a, b int" counts two)
+func fieldCount(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+	count := 0
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			count++
+		} else {
+			count += len(field.Names)
+		}
+	}
+	return count
+}
+
+// interfaceMethodSet returns an interface's methods including those of embedded interfaces,
+// and false if any embedded interface cannot be resolved within the project
+func interfaceMethodSet(types map[string]*namedType, key string, visiting map[string]bool) (map[string]methodShape, bool) {
+	t, ok := types[key]
+	if !ok || !t.isInterface || t.unresolved {
+		return nil, false
+	}
+	if visiting[key] {
+		return nil, false
+	}
+	visiting[key] = true
+	defer delete(visiting, key)
+
+	set := make(map[string]methodShape, len(t.methods))
+	for name, shape := range t.methods {
+		set[name] = shape
+	}
+	for _, embed := range t.embeds {
+		embedded, ok := interfaceMethodSet(types, embed.key, visiting)
+		if !ok {
+			return nil, false
+		}
+		for name, shape := range embedded {
+			if _, exists := set[name]; !exists {
+				set[name] = shape
+			}
+		}
+	}
+	return set, true
+}
+
+// methodSet returns the method set of a concrete type T, or of *T when pointer is set.
+// Methods promoted from embedded fields are included: an embedded *E or interface contributes
+// all of its methods, an embedded E contributes its value methods to T and all methods to *T.
+func methodSet(types map[string]*namedType, key string, pointer bool, visiting map[string]bool) map[string]methodShape {
+	set := make(map[string]methodShape)
+	t, ok := types[key]
+	if !ok || visiting[key] {
+		return set
+	}
+	visiting[key] = true
+	defer delete(visiting, key)
+
+	if t.isInterface {
+		if methods, ok := interfaceMethodSet(types, key, make(map[string]bool)); ok {
+			return methods
+		}
+		return set
+	}
+
+	for name, shape := range t.methods {
+		set[name] = shape
+	}
+	if pointer {
+		for name, shape := range t.ptrMethods {
+			set[name] = shape
+		}
+	}
+
+	// Explicit methods shadow promoted ones
+	for _, embed := range t.embeds {
+		for name, shape := range methodSet(types, embed.key, pointer || embed.pointer, visiting) {
+			if _, exists := set[name]; !exists {
+				set[name] = shape
+			}
+		}
+	}
+	return set
+}
+
+// hasMethods reports whether a method set contains every required method with a matching shape
+func hasMethods(set map[string]methodShape, required map[string]methodShape) bool {
+	for name, shape := range required {
+		if got, ok := set[name]; !ok || got != shape {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterfaceImplementers(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"geo/geo.go": `package geo
+
+type Shape interface {
+	Area() float64
+	Name() string
+}
+
+type Named interface{ Name() string }
+
+// Solid embeds Named, so implementers need Volume and Name
+type Solid interface {
+	Named
+	Volume() float64
+}
+
+// Circle implements Shape with value receivers
+type Circle struct{ r float64 }
+
+func (c Circle) Area() float64 { return 3 * c.r * c.r }
+func (c Circle) Name() string  { return "circle" }
+
+// Square implements Shape only through its pointer
+type Square struct{ s float64 }
+
+func (q *Square) Area() float64 { return q.s * q.s }
+func (q *Square) Name() string  { return "square" }
+
+// Line has Name but not Area
+type Line struct{}
+
+func (Line) Name() string { return "line" }
+`,
+		"solid/solid.go": `package solid
+
+import "example.com/app/geo"
+
+type Cube struct{ geo.Square }
+
+func (c *Cube) Volume() float64 { return 0 }
+`,
+	}, nil)
+
+	geo := findPackage(t, report, "geo")
+	if len(geo.Interfaces) != 3 {
+		t.Fatalf("geo declares %d interfaces, want 3", len(geo.Interfaces))
+	}
+	implementers := make(map[string][]string)
+	for _, iface := range geo.Interfaces {
+		implementers[iface.Name] = iface.Implementers
+	}
+
+	want := map[string][]string{
+		// Cube gets Area and Name promoted from the embedded Square
+		"Shape": {"geo.Circle", "*geo.Square", "*solid.Cube"},
+		"Named": {"geo.Circle", "geo.Line", "*geo.Square", "*solid.Cube"},
+		"Solid": {"*solid.Cube"},
+	}
+	if !reflect.DeepEqual(implementers, want) {
+		t.Errorf("implementers = %v, want %v", implementers, want)
+	}
+}
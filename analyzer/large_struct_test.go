@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLargeStructDiagnostic(t *testing.T) {
+	var src strings.Builder
+	src.WriteString("package model\n\ntype Record struct {\n")
+	for i := 0; i < 16; i++ {
+		fmt.Fprintf(&src, "\tF%d int\n", i)
+	}
+	src.WriteString("}\n\ntype Client struct {\n\tconn string\n}\n")
+	for i := 0; i < 21; i++ {
+		fmt.Fprintf(&src, "\nfunc (c *Client) M%d() string { return c.conn }\n", i)
+	}
+	src.WriteString("\ntype Small struct {\n\tA int\n}\n\nfunc (s Small) Get() int { return s.A }\n")
+
+	report := analyzeFixture(t, map[string]string{"model/model.go": src.String()}, nil)
+
+	pkg := findPackage(t, report, "model")
+	if record := findStruct(t, pkg, "Record"); record.FieldCount != 16 || record.MethodCount != 0 {
+		t.Errorf("Record fields = %d, methods = %d, want 16 and 0", record.FieldCount, record.MethodCount)
+	}
+	if client := findStruct(t, pkg, "Client"); client.FieldCount != 1 || client.MethodCount != 21 {
+		t.Errorf("Client fields = %d, methods = %d, want 1 and 21", client.FieldCount, client.MethodCount)
+	}
+
+	byTarget := make(map[string]LargeStructEvidence)
+	for _, d := range diagnosticsOfType(report, DiagnosticLargeStruct) {
+		evidence, ok := d.Evidence.(LargeStructEvidence)
+		if !ok {
+			t.Fatalf("evidence is %T, want LargeStructEvidence", d.Evidence)
+		}
+		byTarget[d.TargetName] = evidence
+	}
+	if len(byTarget) != 2 {
+		t.Fatalf("Large Struct targets = %v, want model.Record and model.Client", byTarget)
+	}
+	if e, ok := byTarget["model.Record"]; !ok || e.FieldCount != 16 || e.FieldThreshold != 15 {
+		t.Errorf("model.Record evidence = %+v, want 16 fields over 15", e)
+	}
+	if e, ok := byTarget["model.Client"]; !ok || e.MethodCount != 21 || e.MethodThreshold != 20 {
+		t.Errorf("model.Client evidence = %+v, want 21 methods over 20", e)
+	}
+}
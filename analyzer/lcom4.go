@@ -3,16 +3,41 @@ package analyzer
 import (
 	"go/ast"
 	"go/token"
+	"math/rand"
+	"sort"
+	"strings"
 )
 
 // CalculateLCOM4 calculates the LCOM4 metric for all structs in the provided AST
-func CalculateLCOM4(pkg *ast.Package, fset *token.FileSet) []StructResult {
+func CalculateLCOM4(pkg *ast.Package, fset *token.FileSet, cfg *Config) []StructResult {
 	var results []StructResult
+	cfg = cfg.withDefaults()
+
+	// Methods may be declared in any file of the package
+	methodFiles := collectMethodFiles(pkg)
+	methodCounts := countMethods(pkg)
+
+	// Methods started as goroutines (experimental map race heuristic)
+	var launched map[string]map[string]bool
+	if cfg.Experimental {
+		launched = collectGoroutineMethods(pkg)
+	}
 
 	// Traverse all files in the package
 	for fileName, file := range pkg.Files {
+		// Doc comment of the current ungrouped type declaration, which belongs to its single spec
+		var declDoc *ast.CommentGroup
+
 		// Find all struct types
 		ast.Inspect(file, func(n ast.Node) bool {
+			if genDecl, ok := n.(*ast.GenDecl); ok {
+				declDoc = nil
+				if !genDecl.Lparen.IsValid() {
+					declDoc = genDecl.Doc
+				}
+				return true
+			}
+
 			typeSpec, ok := n.(*ast.TypeSpec)
 			if !ok {
 				return true
@@ -24,7 +49,21 @@ func CalculateLCOM4(pkg *ast.Package, fset *token.FileSet) []StructResult {
 			}
 
 			// Calculate LCOM4 for this struct
-			result := calculateStructLCOM4(typeSpec.Name.Name, structType, file, fset, fileName)
+			result := calculateStructLCOM4(typeSpec.Name.Name, structType, file, fset, fileName, newPCARand(cfg.Seed), cfg.LCOMTransitive)
+			result.Line = fset.Position(typeSpec.Pos()).Line
+			result.EndLine = fset.Position(typeSpec.End()).Line
+			result.MethodFiles = methodFiles[typeSpec.Name.Name]
+			result.FieldCount = len(extractFields(structType))
+			result.MethodCount = methodCounts[typeSpec.Name.Name]
+			doc := typeSpec.Doc
+			if doc == nil {
+				doc = declDoc
+			}
+			result.Suppressed = parseIgnoreDirectives(doc)
+			if cfg.Experimental {
+				result.MapRaces = findMapRaces(typeSpec.Name.Name, structType, pkg, launched[typeSpec.Name.Name])
+				result.UnsynchronizedAccesses = findUnsynchronizedAccesses(typeSpec.Name.Name, structType, pkg)
+			}
 			results = append(results, result)
 
 			return true
@@ -34,39 +73,59 @@ func CalculateLCOM4(pkg *ast.Package, fset *token.FileSet) []StructResult {
 	return results
 }
 
-// calculateStructLCOM4 calculates LCOM4 for a single struct
-func calculateStructLCOM4(structName string, structType *ast.StructType, file *ast.File, fset *token.FileSet, fileName string) StructResult {
-	// Extract field names
+// calculateStructLCOM4 calculates LCOM4 for a single struct.
+// In transitive mode, a method is also connected to every field used by the same-struct methods
+// it calls, directly or through further calls.
+func calculateStructLCOM4(structName string, structType *ast.StructType, file *ast.File, fset *token.FileSet, fileName string, rng *rand.Rand, transitive bool) StructResult {
+	// Extract field names (embedded fields are named after their type)
 	fields := extractFields(structType)
+	embedded := extractEmbeddedFields(structType)
+
+	// Promoted members can only be attributed when there is a single embedded field
+	promotedFrom := ""
+	if len(embedded) == 1 {
+		promotedFrom = embedded[0]
+	}
 
 	// Extract methods and their field usage
-	methods := extractMethods(structName, file, fields)
+	methods := extractMethods(structName, file, fields, promotedFrom)
 
 	// Perform advanced analyses (always, even if no methods)
 	// 1. Method clustering analysis (private method call graph)
 	methodClusters := AnalyzeMethodClustering(structName, structType, file, fset)
 
 	// 2. Field matrix analysis (method×field usage with PCA)
-	fieldMatrix := AnalyzeFieldMatrix(structName, structType, file, fset, fields)
+	fieldMatrix := AnalyzeFieldMatrix(structName, structType, file, fset, fields, rng)
+
+	// 3. Value receiver mutations (writes lost on a copied receiver)
+	mutations := AnalyzeReceiverMutations(structName, file, fields)
 
 	// If no methods, LCOM4 is 0
 	if len(methods) == 0 {
 		return StructResult{
-			StructName:       structName,
-			FilePath:         fileName,
-			LCOM4Score:       0,
-			ComponentDetails: [][]string{},
-			MethodClusters:   methodClusters,
-			FieldMatrix:      fieldMatrix,
+			StructName:             structName,
+			FilePath:               fileName,
+			LCOM4Score:             0,
+			ComponentDetails:       [][]string{},
+			MethodClusters:         methodClusters,
+			FieldMatrix:            fieldMatrix,
+			ValueReceiverMutations: mutations,
+			EmbeddedFields:         embedded,
+			IsTest:                 isTestFile(fileName),
 		}
 	}
 
-	// Build Union-Find graph: both methods and fields are nodes
+	// Build Union-Find graph: both methods and fields are nodes.
+	// Method nodes are qualified as "Struct.Method" so they can never collide with a field
+	// (field names contain no dot) or with a method of another struct.
 	uf := newUnionFind()
+	methodNode := func(name string) string {
+		return structName + "." + name
+	}
 
 	// Add all methods as nodes
 	for _, method := range methods {
-		uf.add(method.name)
+		uf.add(methodNode(method.name))
 	}
 
 	// Add all fields as nodes
@@ -77,21 +136,190 @@ func calculateStructLCOM4(structName string, structType *ast.StructType, file *a
 	// Connect methods to fields they use
 	for _, method := range methods {
 		for field := range method.usedFields {
-			uf.union(method.name, field)
+			uf.union(methodNode(method.name), field)
 		}
 	}
 
-	// Count connected components
+	// Connect methods to fields they reach through calls to other methods of the struct
+	if transitive {
+		for method, fields := range reachableFields(methods) {
+			for field := range fields {
+				uf.union(methodNode(method), field)
+			}
+		}
+	}
+
+	// Count connected components, reporting methods by their bare names
 	components := uf.getComponents()
+	for _, component := range components {
+		for i, member := range component {
+			component[i] = strings.TrimPrefix(member, structName+".")
+		}
+	}
+
+	// Chidamber-Kemerer variants from the same method-field usage
+	lcom1, lcom2 := calculateLCOM1AndLCOM2(methods)
+
+	// Normalized cohesion ratios
+	tcc, lcc := calculateClassCohesion(methods)
+
+	result := StructResult{
+		StructName:             structName,
+		FilePath:               fileName,
+		LCOM4Score:             len(components),
+		LCOM1:                  lcom1,
+		LCOM2:                  lcom2,
+		TCC:                    tcc,
+		LCC:                    lcc,
+		ComponentDetails:       components,
+		MethodClusters:         methodClusters,
+		FieldMatrix:            fieldMatrix,
+		ValueReceiverMutations: mutations,
+		EmbeddedFields:         embedded,
+		IsTest:                 isTestFile(fileName),
+	}
 
-	return StructResult{
-		StructName:       structName,
-		FilePath:         fileName,
-		LCOM4Score:       len(components),
-		ComponentDetails: components,
-		MethodClusters:   methodClusters,
-		FieldMatrix:      fieldMatrix,
+	// Simulate extracting the largest component into its own struct.
+	// The extracted struct is cohesive (LCOM4=1) and the original keeps the remaining components.
+	if len(components) > 1 {
+		result.SplitCandidate = largestComponent(components)
+		result.ProjectedLCOM4AfterSplit = len(components) - 1
 	}
+
+	return result
+}
+
+// reachableFields returns, for each method, the fields used by the same-struct methods it calls
+// directly or transitively (a depth-first walk of the receiver call graph)
+func reachableFields(methods []methodInfo) map[string]map[string]bool {
+	byName := make(map[string]methodInfo)
+	for _, method := range methods {
+		byName[method.name] = method
+	}
+
+	reachable := make(map[string]map[string]bool)
+	for _, method := range methods {
+		fields := make(map[string]bool)
+		visited := map[string]bool{method.name: true}
+
+		var walk func(m methodInfo)
+		walk = func(m methodInfo) {
+			for call := range m.calls {
+				_, calledName, _ := strings.Cut(call, ".")
+				callee, exists := byName[calledName]
+				if !exists || visited[calledName] {
+					continue
+				}
+				visited[calledName] = true
+				for field := range callee.usedFields {
+					fields[field] = true
+				}
+				walk(callee)
+			}
+		}
+		walk(method)
+
+		reachable[method.name] = fields
+	}
+
+	return reachable
+}
+
+// calculateLCOM1AndLCOM2 compares every pair of methods: LCOM1 is the number of pairs (P) that
+// use no field in common, and LCOM2 is P minus the pairs (Q) that share a field, or 0 if Q >= P
+func calculateLCOM1AndLCOM2(methods []methodInfo) (lcom1 int, lcom2 int) {
+	disjoint, sharing := 0, 0
+	for i := 0; i < len(methods); i++ {
+		for j := i + 1; j < len(methods); j++ {
+			if sharesField(methods[i].usedFields, methods[j].usedFields) {
+				sharing++
+			} else {
+				disjoint++
+			}
+		}
+	}
+
+	return disjoint, max(0, disjoint-sharing)
+}
+
+// calculateClassCohesion returns Tight and Loose Class Cohesion over the struct's method pairs.
+// TCC is the share of pairs that use a common field; LCC also counts pairs connected through a
+// chain of such methods. Getters, setters, and other utility methods are left out, and both
+// ratios are 0 when fewer than two methods remain.
+func calculateClassCohesion(methods []methodInfo) (tcc float64, lcc float64) {
+	var considered []methodInfo
+	for _, method := range methods {
+		if !isUtilityMethod(method.name) {
+			considered = append(considered, method)
+		}
+	}
+
+	n := len(considered)
+	if n < 2 {
+		return 0, 0
+	}
+
+	// Methods sharing a field are directly connected; union them for the indirect connections
+	uf := newUnionFind()
+	for _, method := range considered {
+		uf.add(method.name)
+	}
+	direct := 0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if sharesField(considered[i].usedFields, considered[j].usedFields) {
+				direct++
+				uf.union(considered[i].name, considered[j].name)
+			}
+		}
+	}
+
+	indirect := 0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if uf.find(considered[i].name) == uf.find(considered[j].name) {
+				indirect++
+			}
+		}
+	}
+
+	pairs := float64(n * (n - 1) / 2)
+	return float64(direct) / pairs, float64(indirect) / pairs
+}
+
+// sharesField reports whether two methods use at least one field in common
+func sharesField(a map[string]bool, b map[string]bool) bool {
+	for field := range a {
+		if b[field] {
+			return true
+		}
+	}
+	return false
+}
+
+// largestComponent returns a sorted copy of the component with the most members
+// (ties are broken by the alphabetically first member for stable output)
+func largestComponent(components [][]string) []string {
+	var largest []string
+	for _, component := range components {
+		sorted := make([]string, len(component))
+		copy(sorted, component)
+		sort.Strings(sorted)
+
+		if len(sorted) > len(largest) || (len(sorted) == len(largest) && sorted[0] < largest[0]) {
+			largest = sorted
+		}
+	}
+	return largest
+}
+
+// newPCARand returns the random source for one struct's PCA, or nil for the fixed start vector (seed 0).
+// Each struct gets its own source so results do not depend on the order structs are visited.
+func newPCARand(seed int64) *rand.Rand {
+	if seed == 0 {
+		return nil
+	}
+	return rand.New(rand.NewSource(seed))
 }
 
 // extractFields extracts all field names from a struct
@@ -102,6 +330,13 @@ func extractFields(structType *ast.StructType) []string {
 	}
 
 	for _, field := range structType.Fields.List {
+		// Embedded fields are named after their type
+		if len(field.Names) == 0 {
+			if name := embeddedFieldName(field.Type); name != "" {
+				fields = append(fields, name)
+			}
+			continue
+		}
 		for _, name := range field.Names {
 			fields = append(fields, name.Name)
 		}
@@ -109,14 +344,53 @@ func extractFields(structType *ast.StructType) []string {
 	return fields
 }
 
+// extractEmbeddedFields returns the names of a struct's embedded fields
+func extractEmbeddedFields(structType *ast.StructType) []string {
+	var embedded []string
+	if structType.Fields == nil {
+		return embedded
+	}
+
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			if name := embeddedFieldName(field.Type); name != "" {
+				embedded = append(embedded, name)
+			}
+		}
+	}
+	return embedded
+}
+
+// embeddedFieldName returns the implicit field name of an embedded type:
+// T, *T, pkg.T, and T[K] all yield "T"
+func embeddedFieldName(expr ast.Expr) string {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.SelectorExpr:
+			return t.Sel.Name
+		default:
+			return receiverTypeName(expr)
+		}
+	}
+}
+
 // methodInfo holds information about a method
 type methodInfo struct {
 	name       string
 	usedFields map[string]bool
+	calls      map[string]int // Same-struct method calls through the receiver, keyed "Struct.method"
 }
 
-// extractMethods finds all methods of a struct and tracks which fields they use
-func extractMethods(structName string, file *ast.File, structFields []string) []methodInfo {
+// extractMethods finds all methods of a struct and tracks which fields they use.
+// If promotedFrom names an embedded field, selectors through the receiver that are neither
+// a field nor a method of the struct are promoted members and count as usage of that field.
+func extractMethods(structName string, file *ast.File, structFields []string, promotedFrom string) []methodInfo {
 	var methods []methodInfo
 
 	// Create field map for quick lookup
@@ -125,6 +399,20 @@ func extractMethods(structName string, file *ast.File, structFields []string) []
 		fieldMap[field] = true
 	}
 
+	// Members declared on the struct itself; any other selector is promoted
+	members := make(map[string]bool)
+	if promotedFrom != "" {
+		for _, field := range structFields {
+			members[field] = true
+		}
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 &&
+				receiverTypeName(funcDecl.Recv.List[0].Type) == structName {
+				members[funcDecl.Name.Name] = true
+			}
+		}
+	}
+
 	ast.Inspect(file, func(n ast.Node) bool {
 		funcDecl, ok := n.(*ast.FuncDecl)
 		if !ok {
@@ -137,19 +425,9 @@ func extractMethods(structName string, file *ast.File, structFields []string) []
 		}
 
 		for _, recv := range funcDecl.Recv.List {
-			var recvTypeName string
+			recvTypeName := receiverTypeName(recv.Type)
 			var recvName string
 
-			// Get receiver type name
-			switch t := recv.Type.(type) {
-			case *ast.Ident:
-				recvTypeName = t.Name
-			case *ast.StarExpr:
-				if ident, ok := t.X.(*ast.Ident); ok {
-					recvTypeName = ident.Name
-				}
-			}
-
 			// Get receiver variable name
 			if len(recv.Names) > 0 {
 				recvName = recv.Names[0].Name
@@ -158,9 +436,13 @@ func extractMethods(structName string, file *ast.File, structFields []string) []
 			if recvTypeName == structName {
 				// This is a method of our struct
 				usedFields := findUsedFields(funcDecl.Body, recvName, fieldMap)
+				if promotedFrom != "" && usesPromotedMember(funcDecl.Body, recvName, members) {
+					usedFields[promotedFrom] = true
+				}
 				methods = append(methods, methodInfo{
 					name:       funcDecl.Name.Name,
 					usedFields: usedFields,
+					calls:      extractMethodCallsWithFrequency(funcDecl.Body, recvName, structName),
 				})
 			}
 		}
@@ -171,6 +453,31 @@ func extractMethods(structName string, file *ast.File, structFields []string) []
 	return methods
 }
 
+// usesPromotedMember reports whether a function body selects, through the receiver,
+// a name that is not one of the struct's own members (a promoted field or method)
+func usesPromotedMember(body *ast.BlockStmt, recvName string, members map[string]bool) bool {
+	if body == nil || recvName == "" || recvName == "_" {
+		return false
+	}
+
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		selector, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == recvName && !members[selector.Sel.Name] {
+			found = true
+		}
+		return true
+	})
+
+	return found
+}
+
 // findUsedFields finds all fields accessed in a function body
 func findUsedFields(body *ast.BlockStmt, recvName string, fieldMap map[string]bool) map[string]bool {
 	usedFields := make(map[string]bool)
@@ -252,7 +559,7 @@ func (uf *unionFind) union(node1, node2 string) {
 	}
 }
 
-// getComponents returns all connected components
+// getComponents returns all connected components, each sorted, ordered by their first member
 func (uf *unionFind) getComponents() [][]string {
 	componentMap := make(map[string][]string)
 
@@ -263,8 +570,12 @@ func (uf *unionFind) getComponents() [][]string {
 
 	components := make([][]string, 0, len(componentMap))
 	for _, component := range componentMap {
+		sort.Strings(component)
 		components = append(components, component)
 	}
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
 
 	return components
 }
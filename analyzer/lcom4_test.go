@@ -0,0 +1,252 @@
+package analyzer
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGenericStructs(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"ds/ds.go": `package ds
+
+type Stack[T any] struct {
+	items []T
+}
+
+func (s *Stack[T]) Push(v T) { s.items = append(s.items, v) }
+
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	v := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return v, true
+}
+
+type Pair[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func (p Pair[K, V]) Key() K { return p.key }
+
+func (p Pair[K, V]) Value() V { return p.value }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "ds")
+
+	stack := findStruct(t, pkg, "Stack")
+	if stack.MethodCount != 2 || stack.LCOM4Score != 1 {
+		t.Errorf("Stack: %d methods, LCOM4 %d, want 2 methods sharing items (LCOM4 1)", stack.MethodCount, stack.LCOM4Score)
+	}
+	if got := findFunction(t, pkg, "Stack.Pop").Complexity; got != 2 {
+		t.Errorf("Stack.Pop complexity = %d, want 2", got)
+	}
+
+	// Two type parameters make the receiver an IndexListExpr; the methods use disjoint fields
+	pair := findStruct(t, pkg, "Pair")
+	if pair.MethodCount != 2 || pair.LCOM4Score != 2 {
+		t.Errorf("Pair: %d methods, LCOM4 %d, want 2 methods on disjoint fields (LCOM4 2)", pair.MethodCount, pair.LCOM4Score)
+	}
+	findFunction(t, pkg, "Pair.Key")
+}
+
+func TestEmbeddedFieldsInLCOM4(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"svc/svc.go": `package svc
+
+import "sync"
+
+type Base struct{ ID int }
+
+func (b *Base) Describe() string { return "base" }
+
+// Explicit connects methods that touch Base directly and through its qualified name
+type Explicit struct {
+	Base
+	name string
+}
+
+func (e *Explicit) Rename(name string) { e.name = name; e.Base.ID++ }
+
+func (e *Explicit) ResetID() { e.Base = Base{} }
+
+// Promoted connects methods through a promoted field and a promoted method
+type Promoted struct {
+	*Base
+	count int
+}
+
+func (p *Promoted) Next() int { p.count++; return p.ID }
+
+func (p *Promoted) String() string { return p.Describe() }
+
+// Locked embeds two types, so promoted members cannot be attributed and Name, Mutex, and
+// Base stay apart
+type Locked struct {
+	sync.Mutex
+	Base
+	hits int
+}
+
+func (l *Locked) Hit() { l.hits++ }
+
+func (l *Locked) Name() string { return l.Describe() }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "svc")
+	for name, want := range map[string]int{"Explicit": 1, "Promoted": 1, "Locked": 4} {
+		s := findStruct(t, pkg, name)
+		if s.LCOM4Score != want {
+			t.Errorf("%s LCOM4 = %d, want %d (components %v)", name, s.LCOM4Score, want, s.ComponentDetails)
+		}
+	}
+
+	locked := findStruct(t, pkg, "Locked")
+	if len(locked.EmbeddedFields) != 2 || locked.EmbeddedFields[0] != "Mutex" || locked.EmbeddedFields[1] != "Base" {
+		t.Errorf("Locked embedded fields = %v, want [Mutex Base]", locked.EmbeddedFields)
+	}
+	if locked.FieldCount != 3 {
+		t.Errorf("Locked field count = %d, want 3 (embedded fields count once)", locked.FieldCount)
+	}
+}
+
+func TestLCOM1AndLCOM2(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"acct/acct.go": `package acct
+
+// Account has two method groups: A and B share balance, C and D share owner
+type Account struct {
+	balance int
+	owner   string
+}
+
+func (a *Account) Deposit(n int)  { a.balance += n }
+func (a *Account) Withdraw(n int) { a.balance -= n }
+func (a *Account) Rename(s string) { a.owner = s }
+func (a *Account) Owner() string   { return a.owner }
+
+// Counter's methods all share one field
+type Counter struct{ n int }
+
+func (c *Counter) Inc()      { c.n++ }
+func (c *Counter) Dec()      { c.n-- }
+func (c *Counter) Value() int { return c.n }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "acct")
+	tests := []struct {
+		name                string
+		lcom1, lcom2, lcom4 int
+	}{
+		// 6 pairs: 2 share a field, 4 do not
+		{"Account", 4, 2, 2},
+		// 3 pairs, all sharing n
+		{"Counter", 0, 0, 1},
+	}
+	for _, tt := range tests {
+		s := findStruct(t, pkg, tt.name)
+		if s.LCOM1 != tt.lcom1 || s.LCOM2 != tt.lcom2 || s.LCOM4Score != tt.lcom4 {
+			t.Errorf("%s LCOM1/2/4 = %d/%d/%d, want %d/%d/%d", tt.name, s.LCOM1, s.LCOM2, s.LCOM4Score, tt.lcom1, tt.lcom2, tt.lcom4)
+		}
+	}
+}
+
+func TestClassCohesion(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"buf/buf.go": `package buf
+
+type Buffer struct {
+	data  []byte
+	flush int
+	total int
+}
+
+func (b *Buffer) Write(p []byte) { b.data = append(b.data, p...) }
+
+func (b *Buffer) Flush() { b.data = b.data[:0]; b.flush++ }
+
+func (b *Buffer) Stats() int { return b.flush }
+
+// Getters are left out of TCC and LCC
+func (b *Buffer) GetTotal() int { return b.total }
+`,
+	}, nil)
+
+	s := findStruct(t, findPackage(t, report, "buf"), "Buffer")
+	// Write-Flush share data and Flush-Stats share flush; Write-Stats connect only through Flush
+	if math.Abs(s.TCC-2.0/3.0) > 1e-9 || s.LCC != 1 {
+		t.Errorf("TCC = %.3f, LCC = %.3f, want 0.667 and 1", s.TCC, s.LCC)
+	}
+	if s.TCC > s.LCC {
+		t.Errorf("TCC %.3f exceeds LCC %.3f", s.TCC, s.LCC)
+	}
+}
+
+func TestLCOM4WithSharedFieldNames(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"model/model.go": `package model
+
+type User struct {
+	id   int
+	name string
+}
+
+func (u *User) ID() int       { return u.id }
+func (u *User) Label() string { return u.name }
+
+type Group struct {
+	id   int
+	name string
+}
+
+func (g *Group) Describe() string { return g.name + string(rune(g.id)) }
+func (g *Group) Label() string    { return g.name }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "model")
+
+	// User's methods use disjoint fields; Group's share name. The same field and method names
+	// on the two structs must not connect their components.
+	user := findStruct(t, pkg, "User")
+	if user.LCOM4Score != 2 || !reflect.DeepEqual(user.ComponentDetails, [][]string{{"ID", "id"}, {"Label", "name"}}) {
+		t.Errorf("User LCOM4 = %d with components %v, want 2: [ID id] [Label name]", user.LCOM4Score, user.ComponentDetails)
+	}
+	group := findStruct(t, pkg, "Group")
+	if group.LCOM4Score != 1 {
+		t.Errorf("Group LCOM4 = %d, want 1 (components %v)", group.LCOM4Score, group.ComponentDetails)
+	}
+}
+
+func TestLCOM4Transitive(t *testing.T) {
+	files := map[string]string{
+		"job/job.go": `package job
+
+type Job struct{ x int }
+
+// A touches no field itself; it reaches x only by calling B
+func (j *Job) A() int { return j.B() + 1 }
+
+func (j *Job) B() int { return j.x }
+`,
+	}
+
+	direct := findStruct(t, findPackage(t, analyzeFixture(t, files, nil), "job"), "Job")
+	if direct.LCOM4Score != 2 {
+		t.Errorf("default LCOM4 = %d, want 2 (A apart from B and x): %v", direct.LCOM4Score, direct.ComponentDetails)
+	}
+
+	cfg := DefaultConfig()
+	cfg.LCOMTransitive = true
+	transitive := findStruct(t, findPackage(t, analyzeFixture(t, files, cfg), "job"), "Job")
+	if transitive.LCOM4Score != 1 || !reflect.DeepEqual(transitive.ComponentDetails, [][]string{{"A", "B", "x"}}) {
+		t.Errorf("transitive LCOM4 = %d with components %v, want 1: [A B x]", transitive.LCOM4Score, transitive.ComponentDetails)
+	}
+}
@@ -3,21 +3,31 @@ package analyzer
 import (
 	"go/ast"
 	"go/token"
+	"sort"
 )
 
 // CalculateLoCForPackage calculates lines of code metrics for an entire package
 func CalculateLoCForPackage(pkg *ast.Package, fset *token.FileSet) PackageLoC {
 	result := PackageLoC{
-		TotalLoC:  0,
-		FileCount: 0,
-		FileLocs:  make(map[string]int),
+		PhysicalLoC:    0,
+		SourceLoC:      0,
+		FileCount:      0,
+		FileLocs:       make(map[string]int),
+		FileSourceLocs: make(map[string]int),
 	}
 
 	for fileName, file := range pkg.Files {
 		fileLoC := calculateFileLoC(file, fset)
-		result.TotalLoC += fileLoC
+		result.PhysicalLoC += fileLoC
+		sourceLoC := calculateSourceLoC(file, fset)
+		result.SourceLoC += sourceLoC
+		result.Statements += countStatements(file)
+		commentLines, docLines := calculateCommentLines(file, fset)
+		result.CommentLines += commentLines
+		result.DocCommentLines += docLines
 		result.FileCount++
 		result.FileLocs[fileName] = fileLoC
+		result.FileSourceLocs[fileName] = sourceLoC
 	}
 
 	return result
@@ -25,9 +35,81 @@ func CalculateLoCForPackage(pkg *ast.Package, fset *token.FileSet) PackageLoC {
 
 // PackageLoC holds LoC metrics for a package
 type PackageLoC struct {
-	TotalLoC  int
-	FileCount int
-	FileLocs  map[string]int
+	PhysicalLoC     int            // Lines including comments and blank lines
+	SourceLoC       int            // Lines containing code
+	Statements      int            // Statements in function bodies
+	CommentLines    int            // Lines containing a comment
+	DocCommentLines int            // Comment lines belonging to doc comments
+	FileCount       int            // Number of files
+	FileLocs        map[string]int // Physical lines per file
+	FileSourceLocs  map[string]int // Lines containing code per file
+}
+
+// count returns the package's lines of code in the given mode (see Config.LoCMode)
+func (l PackageLoC) count(mode string) int {
+	switch mode {
+	case LoCModeSource:
+		return l.SourceLoC
+	case LoCModeStatements:
+		return l.Statements
+	}
+	return l.PhysicalLoC
+}
+
+// functionLoC returns a function's lines of code in the given mode (see Config.LoCMode)
+func functionLoC(f FunctionResult, mode string) int {
+	switch mode {
+	case LoCModeSource:
+		return f.SourceLoC
+	case LoCModeStatements:
+		return f.Statements
+	}
+	return f.LoC
+}
+
+// locMode returns the LoC mode in effect, treating an empty mode as physical
+func locMode(mode string) string {
+	if mode == "" {
+		return LoCModePhysical
+	}
+	return mode
+}
+
+// buildFileResults summarizes each file of a package: its LoC and the functions and structs declared in it
+func buildFileResults(pkgLoC PackageLoC, functions []FunctionResult, structs []StructResult) []FileResult {
+	files := make(map[string]*FileResult, len(pkgLoC.FileLocs))
+	for fileName, loc := range pkgLoC.FileLocs {
+		files[fileName] = &FileResult{
+			Path:      fileName,
+			LoC:       loc,
+			SourceLoC: pkgLoC.FileSourceLocs[fileName],
+		}
+	}
+
+	for _, f := range functions {
+		file, ok := files[f.FilePath]
+		if !ok {
+			continue
+		}
+		file.FuncCount++
+		if f.Complexity > file.MaxComplexity {
+			file.MaxComplexity = f.Complexity
+		}
+	}
+	for _, s := range structs {
+		if file, ok := files[s.FilePath]; ok {
+			file.StructCount++
+		}
+	}
+
+	results := make([]FileResult, 0, len(files))
+	for _, file := range files {
+		results = append(results, *file)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Path < results[j].Path
+	})
+	return results
 }
 
 // calculateFileLoC calculates the number of lines of code in a file
@@ -41,11 +123,105 @@ func calculateFileLoC(file *ast.File, fset *token.FileSet) int {
 	endPos := fset.Position(file.End())
 
 	// Calculate the number of lines
-	// Note: This gives us the total number of lines in the file (including comments and blank lines)
-	// For a more accurate "source lines of code" we could filter out comments and blank lines
+	// Note: This gives us the total number of lines in the file (including comments and blank lines);
+	// see calculateSourceLoC for lines of code only
 	return endPos.Line - startPos.Line + 1
 }
 
+// calculateSourceLoC counts the lines of a file that contain at least one non-comment token
+func calculateSourceLoC(file *ast.File, fset *token.FileSet) int {
+	if file == nil {
+		return 0
+	}
+
+	return len(codeLines(file, fset))
+}
+
+// calculateCommentLines counts the lines of a file holding comments, and how many of those belong to
+// doc comments: the comment groups immediately preceding the package clause, a top-level declaration,
+// a type or value spec, or a struct field or interface method
+func calculateCommentLines(file *ast.File, fset *token.FileSet) (commentLines int, docLines int) {
+	if file == nil {
+		return 0, 0
+	}
+
+	docGroups := make(map[*ast.CommentGroup]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		var doc *ast.CommentGroup
+		switch node := n.(type) {
+		case *ast.File:
+			doc = node.Doc
+		case *ast.FuncDecl:
+			doc = node.Doc
+		case *ast.GenDecl:
+			doc = node.Doc
+		case *ast.TypeSpec:
+			doc = node.Doc
+		case *ast.ValueSpec:
+			doc = node.Doc
+		case *ast.Field:
+			doc = node.Doc
+		}
+		if doc != nil {
+			docGroups[doc] = true
+		}
+		return true
+	})
+
+	comments := make(map[int]bool)
+	docs := make(map[int]bool)
+	for _, group := range file.Comments {
+		for line := fset.Position(group.Pos()).Line; line <= fset.Position(group.End()).Line; line++ {
+			comments[line] = true
+			if docGroups[group] {
+				docs[line] = true
+			}
+		}
+	}
+
+	return len(comments), len(docs)
+}
+
+// lineRatio divides a line count by the source lines, returning 0 for packages without code
+func lineRatio(lines int, sourceLoC int) float64 {
+	if sourceLoC == 0 {
+		return 0
+	}
+	return float64(lines) / float64(sourceLoC)
+}
+
+// codeLines returns the set of lines holding code within a node.
+// Every token begins or ends some AST node, so the lines of node boundaries cover all code lines;
+// comments are skipped, and multi-line string literals count every line they span.
+func codeLines(root ast.Node, fset *token.FileSet) map[int]bool {
+	lines := make(map[int]bool)
+
+	ast.Inspect(root, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case nil:
+			return false
+		case *ast.CommentGroup, *ast.Comment:
+			return false
+		case *ast.BasicLit:
+			for line := fset.Position(node.Pos()).Line; line <= fset.Position(node.End()).Line; line++ {
+				lines[line] = true
+			}
+			return false
+		}
+
+		if n.Pos().IsValid() {
+			lines[fset.Position(n.Pos()).Line] = true
+		}
+		if n.End().IsValid() && n.End() > n.Pos() {
+			// End is just past the last token
+			lines[fset.Position(n.End()-1).Line] = true
+		}
+		return true
+	})
+
+	return lines
+}
+
 // CalculateFunctionLoC calculates lines of code for a function
 func CalculateFunctionLoC(funcDecl *ast.FuncDecl, fset *token.FileSet) int {
 	if funcDecl == nil || funcDecl.Body == nil {
@@ -65,6 +241,49 @@ func CalculateFunctionLoC(funcDecl *ast.FuncDecl, fset *token.FileSet) int {
 	return lines
 }
 
+// CalculateFunctionSourceLoC counts the lines of a function body that contain code,
+// on the same basis as CalculateFunctionLoC (the opening brace line is not counted)
+func CalculateFunctionSourceLoC(funcDecl *ast.FuncDecl, fset *token.FileSet) int {
+	if funcDecl == nil || funcDecl.Body == nil {
+		return 0
+	}
+
+	openLine := fset.Position(funcDecl.Body.Lbrace).Line
+	count := 0
+	for line := range codeLines(funcDecl.Body, fset) {
+		if line > openLine {
+			count++
+		}
+	}
+	return count
+}
+
+// CalculateFunctionStatements counts the statements in a function body, including those of
+// function literals within it
+func CalculateFunctionStatements(funcDecl *ast.FuncDecl) int {
+	if funcDecl == nil || funcDecl.Body == nil {
+		return 0
+	}
+
+	return countStatements(funcDecl.Body)
+}
+
+// countStatements counts the statements within a node. Blocks only group statements, empty
+// statements hold no code, and a labeled statement is counted once as the statement it labels,
+// so none of those count on their own. Each case and select clause counts.
+func countStatements(root ast.Node) int {
+	count := 0
+	ast.Inspect(root, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.BlockStmt, *ast.EmptyStmt, *ast.LabeledStmt:
+		case ast.Stmt:
+			count++
+		}
+		return true
+	})
+	return count
+}
+
 // CalculateLoCForFunctions calculates LoC for all functions in a package
 // and returns them as a map keyed by function name
 func CalculateLoCForFunctions(pkg *ast.Package, fset *token.FileSet) map[string]int {
@@ -77,24 +296,7 @@ func CalculateLoCForFunctions(pkg *ast.Package, fset *token.FileSet) map[string]
 				return true
 			}
 
-			funcName := funcDecl.Name.Name
-
-			// Add receiver type for methods
-			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-				recv := funcDecl.Recv.List[0]
-				var recvTypeName string
-				switch t := recv.Type.(type) {
-				case *ast.Ident:
-					recvTypeName = t.Name
-				case *ast.StarExpr:
-					if ident, ok := t.X.(*ast.Ident); ok {
-						recvTypeName = ident.Name
-					}
-				}
-				if recvTypeName != "" {
-					funcName = recvTypeName + "." + funcName
-				}
-			}
+			funcName := qualifiedFuncName(funcDecl)
 
 			loc := CalculateFunctionLoC(funcDecl, fset)
 			funcLoCs[funcName] = loc
@@ -105,3 +307,26 @@ func CalculateLoCForFunctions(pkg *ast.Package, fset *token.FileSet) map[string]
 
 	return funcLoCs
 }
+
+// largestLiteralLoC returns the number of lines spanned by the largest composite literal in a
+// function body, such as a lookup table or a list of test cases
+func largestLiteralLoC(funcDecl *ast.FuncDecl, fset *token.FileSet) int {
+	if funcDecl == nil || funcDecl.Body == nil {
+		return 0
+	}
+
+	largest := 0
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+		lines := fset.Position(lit.Rbrace).Line - fset.Position(lit.Lbrace).Line + 1
+		if lines > largest {
+			largest = lines
+		}
+		// Nested literals are part of this one
+		return false
+	})
+	return largest
+}
@@ -0,0 +1,246 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"testing"
+)
+
+// commentHeavySource is mostly comments and blank lines around four lines of code
+const commentHeavySource = `// Package p has a long header
+// explaining what it is for.
+//
+// And more.
+
+package p
+
+/*
+A block comment
+spanning several lines.
+*/
+
+// Add adds.
+func Add(a, b int) int {
+	// explain
+
+	// and explain again
+	return a + b // trailing comments do not hide code
+}
+`
+
+func TestCalculateSourceLoCSkipsCommentsAndBlanks(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", commentHeavySource, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	physical := calculateFileLoC(file, fset)
+	source := calculateSourceLoC(file, fset)
+	if source != 4 {
+		t.Errorf("source LoC = %d, want 4 (package, func, return, closing brace)", source)
+	}
+	if physical < 3*source {
+		t.Errorf("physical LoC = %d, want far more than the %d source lines", physical, source)
+	}
+
+	funcDecl := file.Decls[0].(*ast.FuncDecl)
+	if got := CalculateFunctionLoC(funcDecl, fset); got != 5 {
+		t.Errorf("function LoC = %d, want 5", got)
+	}
+	if got := CalculateFunctionSourceLoC(funcDecl, fset); got != 2 {
+		t.Errorf("function source LoC = %d, want 2", got)
+	}
+}
+
+func TestCalculateSourceLoCCountsMultilineStrings(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", "package p\n\nconst usage = `line one\n\n// not a comment\n`\n", parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := calculateSourceLoC(file, fset); got != 5 {
+		t.Errorf("source LoC = %d, want 5 (the raw string spans lines 3-6)", got)
+	}
+}
+
+func TestPackageSourceLoC(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{"p/p.go": commentHeavySource}, nil)
+
+	pkg := findPackage(t, report, "p")
+	if pkg.SourceLoC != 4 {
+		t.Errorf("package SourceLoC = %d, want 4", pkg.SourceLoC)
+	}
+	if pkg.TotalLoC <= pkg.SourceLoC {
+		t.Errorf("package TotalLoC = %d, want more than SourceLoC %d in the default physical mode", pkg.TotalLoC, pkg.SourceLoC)
+	}
+	if f := findFunction(t, pkg, "Add"); f.SourceLoC != 2 {
+		t.Errorf("Add SourceLoC = %d, want 2", f.SourceLoC)
+	}
+}
+
+func TestFileResults(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"store/store.go": `package store
+
+type Store struct{ items map[string]int }
+
+func (s *Store) Get(k string) int { return s.items[k] }
+
+func (s *Store) Put(k string, v int) {
+	if v < 0 {
+		return
+	}
+	s.items[k] = v
+}
+`,
+		"store/util.go": `package store
+
+// Keys are normalized before use
+func normalize(k string) string { return k }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "store")
+	if len(pkg.Files) != 2 {
+		t.Fatalf("got %d files, want 2", len(pkg.Files))
+	}
+
+	want := []struct {
+		base                  string
+		funcs, structs, maxCC int
+		loc, sourceLoC        int
+	}{
+		{"store.go", 2, 1, 2, 12, 9},
+		{"util.go", 1, 0, 1, 4, 2},
+	}
+	for i, w := range want {
+		f := pkg.Files[i]
+		if filepath.Base(f.Path) != w.base {
+			t.Errorf("file %d = %s, want %s", i, f.Path, w.base)
+			continue
+		}
+		if f.FuncCount != w.funcs || f.StructCount != w.structs || f.MaxComplexity != w.maxCC || f.LoC != w.loc || f.SourceLoC != w.sourceLoC {
+			t.Errorf("%s = %+v, want %d functions, %d structs, max complexity %d, LoC %d, source LoC %d",
+				w.base, f, w.funcs, w.structs, w.maxCC, w.loc, w.sourceLoC)
+		}
+	}
+}
+
+func TestDetectLongFunctionsBoundary(t *testing.T) {
+	cfg := DefaultConfig()
+	packages := []PackageResult{{
+		Name: "app",
+		Path: "app",
+		Functions: []FunctionResult{
+			{FuncName: "Below", LoC: cfg.LongFunctionThreshold - 1},
+			{FuncName: "AtThreshold", LoC: cfg.LongFunctionThreshold},
+			{FuncName: "Table", LoC: 80, LiteralLoC: 40},
+			{FuncName: "MostlyLogic", LoC: 80, LiteralLoC: 39},
+		},
+	}}
+
+	results := detectLongFunctions(packages, cfg)
+	if len(results) != 2 {
+		t.Fatalf("got %d diagnostics, want 2 (AtThreshold and MostlyLogic): %+v", len(results), results)
+	}
+	if results[0].TargetName != "app.AtThreshold" || results[1].TargetName != "app.MostlyLogic" {
+		t.Errorf("targets = %q, %q, want app.AtThreshold and app.MostlyLogic", results[0].TargetName, results[1].TargetName)
+	}
+	evidence, ok := results[0].Evidence.(LongFunctionEvidence)
+	if !ok {
+		t.Fatalf("evidence is %T, want LongFunctionEvidence", results[0].Evidence)
+	}
+	if evidence.LoC != cfg.LongFunctionThreshold || evidence.Threshold != cfg.LongFunctionThreshold {
+		t.Errorf("evidence = %+v, want LoC and threshold %d", evidence, cfg.LongFunctionThreshold)
+	}
+
+	cfg.LongFunctionThreshold = 0
+	if results := detectLongFunctions(packages, cfg); len(results) != 0 {
+		t.Errorf("threshold 0 reported %d functions, want the check disabled", len(results))
+	}
+}
+
+func TestLargestLiteralLoC(t *testing.T) {
+	const src = `package p
+
+func f() map[string][]int {
+	small := []int{1, 2}
+	table := map[string][]int{
+		"a": {1,
+			2},
+		"b": {3},
+	}
+	_ = small
+	return table
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The map literal spans 5 lines; its nested literals are not counted on their own
+	if got := largestLiteralLoC(file.Decls[0].(*ast.FuncDecl), fset); got != 5 {
+		t.Errorf("largestLiteralLoC = %d, want 5", got)
+	}
+}
+
+// locModesSource has comments, a blank line, and several statements per line:
+// 13 physical lines from the package clause, 9 of them with code, and 8 statements
+const locModesSource = `// Package calc has a header comment.
+package calc
+
+// Sum adds the numbers.
+func Sum(xs []int) int {
+	total := 0; count := 0 // two statements
+	for _, x := range xs {
+		// accumulate
+		total += x; count++
+
+	}
+	_ = count; _ = xs
+	return total
+}
+`
+
+func TestLoCModes(t *testing.T) {
+	tests := []struct {
+		mode      string
+		pkgLoC    int
+		funcLoC   float64
+		reportLoC int
+	}{
+		// Body lines after the opening brace: 9
+		{LoCModePhysical, 13, 9, 13},
+		// Code lines of the body after the opening brace: 7
+		{LoCModeSource, 9, 7, 9},
+		// total, count, for, +=, ++, two blank assignments, return
+		{LoCModeStatements, 8, 8, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.LoCMode = tt.mode
+			report := analyzeFixture(t, map[string]string{"calc/calc.go": locModesSource}, cfg)
+
+			if report.LoCMode != tt.mode {
+				t.Errorf("report LoCMode = %q, want %q", report.LoCMode, tt.mode)
+			}
+			pkg := findPackage(t, report, "calc")
+			if pkg.TotalLoC != tt.pkgLoC || pkg.AvgFuncLoC != tt.funcLoC || report.TotalLoC != tt.reportLoC {
+				t.Errorf("package LoC = %d, avg function LoC = %.1f, report LoC = %d, want %d, %.1f, %d",
+					pkg.TotalLoC, pkg.AvgFuncLoC, report.TotalLoC, tt.pkgLoC, tt.funcLoC, tt.reportLoC)
+			}
+
+			// The per-mode counts are all kept, whichever mode the totals use
+			sum := findFunction(t, pkg, "Sum")
+			if sum.LoC != 9 || sum.SourceLoC != 7 || sum.Statements != 8 {
+				t.Errorf("Sum LoC = %d, source = %d, statements = %d, want 9, 7, 8", sum.LoC, sum.SourceLoC, sum.Statements)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// Allocation kinds reported in LoopAllocation.Allocations
+const (
+	allocMake         = "make"
+	allocNew          = "new"
+	allocCompositeLit = "composite literal"
+	allocAppend       = "append to unpreallocated slice"
+)
+
+// findLoopAllocations finds loops whose bodies contain allocations: make, new, slice/map/pointer
+// composite literals, and appends to slices declared without preallocation. This is a syntactic
+// heuristic (no escape analysis); each allocation is attributed to its innermost loop.
+func findLoopAllocations(funcDecl *ast.FuncDecl, fset *token.FileSet) []LoopAllocation {
+	if funcDecl.Body == nil {
+		return nil
+	}
+
+	unpreallocated := findUnpreallocatedSlices(funcDecl.Body)
+
+	var results []LoopAllocation
+	var walk func(n ast.Node, loop *LoopAllocation)
+	walk = func(n ast.Node, loop *LoopAllocation) {
+		ast.Inspect(n, func(node ast.Node) bool {
+			if node == n {
+				return true
+			}
+
+			switch x := node.(type) {
+			case *ast.FuncLit:
+				// Closures are analyzed as their own scope
+				return false
+
+			case *ast.ForStmt, *ast.RangeStmt:
+				inner := &LoopAllocation{Line: fset.Position(x.Pos()).Line}
+				walk(loopBody(x), inner)
+				if len(inner.Allocations) > 0 {
+					results = append(results, *inner)
+				}
+				return false
+
+			case *ast.CallExpr:
+				if loop == nil {
+					return true
+				}
+				if ident, ok := x.Fun.(*ast.Ident); ok {
+					switch ident.Name {
+					case "make":
+						loop.add(allocMake)
+					case "new":
+						loop.add(allocNew)
+					case "append":
+						if len(x.Args) > 0 {
+							if target, ok := x.Args[0].(*ast.Ident); ok && unpreallocated[target.Name] {
+								loop.add(allocAppend)
+							}
+						}
+					}
+				}
+
+			case *ast.UnaryExpr:
+				if loop != nil && x.Op == token.AND {
+					if _, ok := x.X.(*ast.CompositeLit); ok {
+						loop.add(allocCompositeLit)
+					}
+				}
+
+			case *ast.CompositeLit:
+				if loop != nil {
+					switch t := x.Type.(type) {
+					case *ast.MapType:
+						loop.add(allocCompositeLit)
+					case *ast.ArrayType:
+						if t.Len == nil {
+							loop.add(allocCompositeLit)
+						}
+					}
+				}
+			}
+			return true
+		})
+	}
+	walk(funcDecl.Body, nil)
+
+	// Inner loops are recorded before their outer loops; report in source order
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Line < results[j].Line
+	})
+
+	return results
+}
+
+// add records an allocation kind once per loop
+func (l *LoopAllocation) add(kind string) {
+	for _, existing := range l.Allocations {
+		if existing == kind {
+			return
+		}
+	}
+	l.Allocations = append(l.Allocations, kind)
+}
+
+// loopBody returns the body of a for or range statement
+func loopBody(loop ast.Node) *ast.BlockStmt {
+	switch l := loop.(type) {
+	case *ast.ForStmt:
+		return l.Body
+	case *ast.RangeStmt:
+		return l.Body
+	}
+	return nil
+}
+
+// findUnpreallocatedSlices finds slice variables declared empty (`var s []T`, `s := []T{}`),
+// which grow by reallocation when appended to in a loop
+func findUnpreallocatedSlices(body *ast.BlockStmt) map[string]bool {
+	names := make(map[string]bool)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.ValueSpec:
+			if arrayType, ok := x.Type.(*ast.ArrayType); ok && arrayType.Len == nil && len(x.Values) == 0 {
+				for _, name := range x.Names {
+					names[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if x.Tok != token.DEFINE || len(x.Lhs) != len(x.Rhs) {
+				return true
+			}
+			for i, rhs := range x.Rhs {
+				lit, ok := rhs.(*ast.CompositeLit)
+				if !ok || len(lit.Elts) > 0 {
+					continue
+				}
+				if arrayType, ok := lit.Type.(*ast.ArrayType); ok && arrayType.Len == nil {
+					if ident, ok := x.Lhs[i].(*ast.Ident); ok {
+						names[ident.Name] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	return names
+}
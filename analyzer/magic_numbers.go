@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+)
+
+// countMagicNumbers counts numeric literals used in a function's logic.
+// Excluded: the common values 0 and 1 (and therefore -1), literals inside const
+// declarations, and array lengths such as [16]byte.
+func countMagicNumbers(funcDecl *ast.FuncDecl) int {
+	if funcDecl.Body == nil {
+		return 0
+	}
+
+	count := 0
+	skip := make(map[ast.Node]bool)
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.GenDecl:
+			// Literals in local const declarations are already named
+			if node.Tok == token.CONST {
+				return false
+			}
+
+		case *ast.ArrayType:
+			// Array lengths are part of the type, not logic
+			if node.Len != nil {
+				ast.Inspect(node.Len, func(n2 ast.Node) bool {
+					if n2 != nil {
+						skip[n2] = true
+					}
+					return true
+				})
+			}
+
+		case *ast.BasicLit:
+			if skip[node] || (node.Kind != token.INT && node.Kind != token.FLOAT) {
+				return true
+			}
+			if !isCommonNumber(node) {
+				count++
+			}
+		}
+
+		return true
+	})
+
+	return count
+}
+
+// isCommonNumber reports whether a numeric literal is 0 or 1 (in any notation, e.g. 0x0 or 1.0)
+func isCommonNumber(lit *ast.BasicLit) bool {
+	value := constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+	if value.Kind() == constant.Unknown {
+		return false
+	}
+	return constant.Compare(value, token.EQL, constant.MakeInt64(0)) ||
+		constant.Compare(value, token.EQL, constant.MakeInt64(1))
+}
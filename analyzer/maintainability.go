@@ -0,0 +1,33 @@
+package analyzer
+
+import "math"
+
+// maintainabilityIndex calculates the Maintainability Index in the normalized 0-100 variant used by
+// Visual Studio:
+//
+//	MI = max(0, (171 - 5.2 * ln(V) - 0.23 * CC - 16.2 * ln(LoC)) * 100 / 171)
+//
+// with V the Halstead volume, CC the cyclomatic complexity, and LoC the source lines of the function.
+// Volume and LoC below 1 are treated as 1 so that empty functions score 100 minus their complexity.
+// 20 and above is maintainable, 10-19 moderately maintainable, below 10 hard to maintain.
+func maintainabilityIndex(volume float64, complexity int, loc int) float64 {
+	volume = math.Max(volume, 1)
+	lines := math.Max(float64(loc), 1)
+
+	mi := (171 - 5.2*math.Log(volume) - 0.23*float64(complexity) - 16.2*math.Log(lines)) * 100 / 171
+	return math.Min(math.Max(mi, 0), 100)
+}
+
+// averageMaintainabilityIndex returns the mean Maintainability Index of a package's functions,
+// or 0 if it has none
+func averageMaintainabilityIndex(functions []FunctionResult) float64 {
+	if len(functions) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, f := range functions {
+		total += f.MaintainabilityIndex
+	}
+	return total / float64(len(functions))
+}
@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMaintainabilityIndex(t *testing.T) {
+	tests := []struct {
+		name       string
+		volume     float64
+		complexity int
+		loc        int
+		want       float64
+	}{
+		{"trivial", 1, 1, 1, 99.865497},
+		{"empty body", 0, 1, 0, 99.865497},
+		{"complex", 3000, 30, 200, 21.423478},
+		{"clamped at 0", 1e6, 100, 5000, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maintainabilityIndex(tt.volume, tt.complexity, tt.loc); math.Abs(got-tt.want) > 1e-5 {
+				t.Errorf("maintainabilityIndex = %.6f, want %.6f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackageMaintainabilityIndex(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"calc/calc.go": `package calc
+
+func Identity(n int) int { return n }
+
+func Classify(n int) string {
+	switch {
+	case n < 0 && n > -10:
+		return "small negative"
+	case n < 0:
+		return "negative"
+	case n == 0:
+		return "zero"
+	case n < 10 || n%2 == 0:
+		for i := 0; i < n; i++ {
+			if i*i == n {
+				return "square"
+			}
+		}
+		return "small"
+	}
+	return "large"
+}
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "calc")
+	identity, classify := findFunction(t, pkg, "Identity"), findFunction(t, pkg, "Classify")
+	if identity.MaintainabilityIndex <= classify.MaintainabilityIndex {
+		t.Errorf("Identity MI %.1f should exceed Classify MI %.1f", identity.MaintainabilityIndex, classify.MaintainabilityIndex)
+	}
+	if want := (identity.MaintainabilityIndex + classify.MaintainabilityIndex) / 2; math.Abs(pkg.MaintainabilityIndex-want) > 1e-9 {
+		t.Errorf("package MI = %.3f, want the mean %.3f", pkg.MaintainabilityIndex, want)
+	}
+}
@@ -0,0 +1,338 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// collectGoroutineMethods returns the methods started as goroutines anywhere in the package, by
+// receiver type name: "go x.Method(...)" and method calls made inside "go func() { ... }()".
+// Without type checking, a call counts only when x's type can be read from the syntax: the
+// enclosing method's receiver, a parameter or variable declared with a named type, or a variable
+// assigned T{}, &T{}, or new(T). Calls on other expressions are not recorded.
+func collectGoroutineMethods(pkg *ast.Package) map[string]map[string]bool {
+	launched := make(map[string]map[string]bool)
+	record := func(call *ast.CallExpr) {
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		ident, ok := selector.X.(*ast.Ident)
+		if !ok {
+			return
+		}
+		typeName := declaredTypeName(ident)
+		if typeName == "" {
+			return
+		}
+		if launched[typeName] == nil {
+			launched[typeName] = make(map[string]bool)
+		}
+		launched[typeName][selector.Sel.Name] = true
+	}
+
+	for _, file := range pkg.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			goStmt, ok := n.(*ast.GoStmt)
+			if !ok {
+				return true
+			}
+
+			switch fun := goStmt.Call.Fun.(type) {
+			case *ast.SelectorExpr:
+				record(goStmt.Call)
+			case *ast.FuncLit:
+				ast.Inspect(fun.Body, func(n2 ast.Node) bool {
+					if call, ok := n2.(*ast.CallExpr); ok {
+						record(call)
+					}
+					return true
+				})
+			}
+			return true
+		})
+	}
+
+	return launched
+}
+
+// declaredTypeName returns the name of the named type an identifier is declared with, or "" when
+// the declaration does not spell it out: receivers and parameters (x T, x *T), var x T, and
+// x := T{}, &T{}, or new(T)
+func declaredTypeName(ident *ast.Ident) string {
+	if ident.Obj == nil || ident.Obj.Kind != ast.Var {
+		return ""
+	}
+
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.Field:
+		return receiverTypeName(decl.Type)
+	case *ast.ValueSpec:
+		if decl.Type != nil {
+			return receiverTypeName(decl.Type)
+		}
+		for i, name := range decl.Names {
+			if name.Name == ident.Name && i < len(decl.Values) && len(decl.Names) == len(decl.Values) {
+				return constructedTypeName(decl.Values[i])
+			}
+		}
+	case *ast.AssignStmt:
+		if len(decl.Lhs) != len(decl.Rhs) {
+			return ""
+		}
+		for i, lhs := range decl.Lhs {
+			if name, ok := lhs.(*ast.Ident); ok && name.Name == ident.Name {
+				return constructedTypeName(decl.Rhs[i])
+			}
+		}
+	}
+	return ""
+}
+
+// constructedTypeName returns the type an expression constructs: T{}, &T{}, or new(T)
+func constructedTypeName(expr ast.Expr) string {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	switch e := expr.(type) {
+	case *ast.CompositeLit:
+		if e.Type != nil {
+			return receiverTypeName(e.Type)
+		}
+	case *ast.CallExpr:
+		if ident, ok := e.Fun.(*ast.Ident); ok && ident.Name == "new" && len(e.Args) == 1 {
+			return receiverTypeName(e.Args[0])
+		}
+	}
+	return ""
+}
+
+// findMapRaces finds map fields of a struct that may be accessed concurrently without a lock.
+// A field is reported when at least two methods access it outside a lock on the receiver (see
+// receiverLockedRanges), at least one of them writes it (index assignment, delete, or
+// reassignment), and at least one of them is started as a goroutine on a value of the struct's
+// type (launched, from collectGoroutineMethods). This is a best-effort syntactic heuristic.
+func findMapRaces(structName string, structType *ast.StructType, pkg *ast.Package, launched map[string]bool) []MapRace {
+	mapFields := make(map[string]bool)
+	if structType.Fields != nil {
+		for _, field := range structType.Fields.List {
+			if _, ok := field.Type.(*ast.MapType); !ok {
+				continue
+			}
+			for _, name := range field.Names {
+				mapFields[name.Name] = true
+			}
+		}
+	}
+	if len(mapFields) == 0 {
+		return nil
+	}
+
+	accessors := make(map[string][]string)
+	writers := make(map[string][]string)
+
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+
+			recv := funcDecl.Recv.List[0]
+			recvTypeName := receiverTypeName(recv.Type)
+			if recvTypeName != structName || len(recv.Names) == 0 || recv.Names[0].Name == "_" {
+				continue
+			}
+
+			// Accesses between a Lock/RLock on the receiver and the matching unlock are guarded
+			recvName := recv.Names[0].Name
+			read, written := findMapFieldAccess(funcDecl.Body, recvName, mapFields, receiverLockedRanges(funcDecl.Body, recvName))
+			for field := range read {
+				accessors[field] = append(accessors[field], funcDecl.Name.Name)
+			}
+			for field := range written {
+				writers[field] = append(writers[field], funcDecl.Name.Name)
+			}
+		}
+	}
+
+	var races []MapRace
+	for field, methods := range accessors {
+		if len(methods) < 2 || len(writers[field]) == 0 {
+			continue
+		}
+
+		var started []string
+		for _, method := range methods {
+			if launched[method] {
+				started = append(started, method)
+			}
+		}
+		if len(started) == 0 {
+			continue
+		}
+
+		sort.Strings(methods)
+		sort.Strings(writers[field])
+		sort.Strings(started)
+		races = append(races, MapRace{
+			Field:      field,
+			Methods:    methods,
+			Writers:    writers[field],
+			Goroutines: started,
+		})
+	}
+
+	sort.Slice(races, func(i, j int) bool {
+		return races[i].Field < races[j].Field
+	})
+
+	return races
+}
+
+// callsLock checks whether a function body calls a Lock or RLock method
+func callsLock(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		if call, ok := n.(*ast.CallExpr); ok {
+			if selector, ok := call.Fun.(*ast.SelectorExpr); ok {
+				if selector.Sel.Name == "Lock" || selector.Sel.Name == "RLock" {
+					found = true
+				}
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// lockedRange is a span of a function body during which a lock is held
+type lockedRange struct {
+	from token.Pos
+	to   token.Pos
+}
+
+// receiverLockedRanges returns the spans of a method body in which a lock on the receiver is held:
+// from each recv.Lock()/recv.RLock() or recv.field.Lock()/RLock() call to the next unlock of the
+// receiver that is not deferred, or to the end of the body. Locks on other values (a package-level
+// mutex, another struct's field) do not guard the receiver, and accesses before the lock is taken
+// are unguarded. Control flow is not followed: spans are in source order.
+func receiverLockedRanges(body *ast.BlockStmt, recvName string) []lockedRange {
+	var locks, unlocks []token.Pos
+	deferred := make(map[*ast.CallExpr]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			// Closures run at another time, possibly without the lock
+			return false
+		case *ast.DeferStmt:
+			deferred[x.Call] = true
+		case *ast.CallExpr:
+			selector, ok := x.Fun.(*ast.SelectorExpr)
+			if !ok || rootIdentName(selector.X) != recvName {
+				return true
+			}
+			switch selector.Sel.Name {
+			case "Lock", "RLock":
+				locks = append(locks, x.Pos())
+			case "Unlock", "RUnlock":
+				if !deferred[x] {
+					unlocks = append(unlocks, x.Pos())
+				}
+			}
+		}
+		return true
+	})
+
+	var ranges []lockedRange
+	for _, lock := range locks {
+		end := body.End()
+		for _, unlock := range unlocks {
+			if unlock > lock && unlock < end {
+				end = unlock
+			}
+		}
+		ranges = append(ranges, lockedRange{from: lock, to: end})
+	}
+	return ranges
+}
+
+// rootIdentName returns the name of the identifier a selector chain starts from (s in s.mu.x), or ""
+func rootIdentName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.Ident:
+			return e.Name
+		case *ast.SelectorExpr:
+			expr = e.X
+		default:
+			return ""
+		}
+	}
+}
+
+// findMapFieldAccess returns the map fields a method accesses through its receiver outside the
+// locked ranges, and the subset it writes
+func findMapFieldAccess(body *ast.BlockStmt, recvName string, mapFields map[string]bool, locked []lockedRange) (map[string]bool, map[string]bool) {
+	accessed := make(map[string]bool)
+	written := make(map[string]bool)
+
+	// receiverField returns the unguarded map field an expression refers to ("recv.field"), or ""
+	receiverField := func(expr ast.Expr) string {
+		selector, ok := expr.(*ast.SelectorExpr)
+		if !ok {
+			return ""
+		}
+		for _, r := range locked {
+			if selector.Pos() > r.from && selector.Pos() < r.to {
+				return ""
+			}
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == recvName && mapFields[selector.Sel.Name] {
+			return selector.Sel.Name
+		}
+		return ""
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.SelectorExpr:
+			if field := receiverField(x); field != "" {
+				accessed[field] = true
+			}
+
+		case *ast.AssignStmt:
+			for _, lhs := range x.Lhs {
+				// recv.field[key] = value
+				if index, ok := lhs.(*ast.IndexExpr); ok {
+					lhs = index.X
+				}
+				if field := receiverField(lhs); field != "" {
+					written[field] = true
+				}
+			}
+
+		case *ast.IncDecStmt:
+			if index, ok := x.X.(*ast.IndexExpr); ok {
+				if field := receiverField(index.X); field != "" {
+					written[field] = true
+				}
+			}
+
+		case *ast.CallExpr:
+			// delete(recv.field, key)
+			if ident, ok := x.Fun.(*ast.Ident); ok && ident.Name == "delete" && len(x.Args) > 0 {
+				if field := receiverField(x.Args[0]); field != "" {
+					written[field] = true
+				}
+			}
+		}
+		return true
+	})
+
+	return accessed, written
+}
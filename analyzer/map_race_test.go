@@ -0,0 +1,135 @@
+package analyzer
+
+import "testing"
+
+func TestPossibleMapRace(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Experimental = true
+
+	report := analyzeFixture(t, map[string]string{
+		"cache/cache.go": `package cache
+
+import "sync"
+
+var global sync.Mutex
+
+// Racy is started as a goroutine through its receiver and writes its map without a lock
+type Racy struct {
+	items map[string]int
+}
+
+func (r *Racy) Start() { go r.Run() }
+
+func (r *Racy) Run() { r.items["a"] = 1 }
+
+func (r *Racy) Get(k string) int { return r.items[k] }
+
+// Guarded locks its own mutex around every access
+type Guarded struct {
+	mu    sync.Mutex
+	items map[string]int
+}
+
+func (g *Guarded) Start() { go g.Run() }
+
+func (g *Guarded) Run() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.items["a"] = 1
+}
+
+func (g *Guarded) Get(k string) int {
+	g.mu.Lock()
+	v := g.items[k]
+	g.mu.Unlock()
+	return v
+}
+
+// WrongLock locks a package-level mutex, and LateLock takes its lock after the write
+type WrongLock struct {
+	mu    sync.Mutex
+	items map[string]int
+}
+
+func (w *WrongLock) Start() { go w.Run() }
+
+func (w *WrongLock) Run() {
+	global.Lock()
+	defer global.Unlock()
+	w.items["a"] = 1
+}
+
+func (w *WrongLock) LateLock(k string) int {
+	v := w.items[k]
+	w.mu.Lock()
+	w.mu.Unlock()
+	return v
+}
+
+// Idle has a Run method too, but only another type's Run is started as a goroutine
+type Idle struct {
+	items map[string]int
+}
+
+func (i *Idle) Run() { i.items["a"] = 1 }
+
+func (i *Idle) Get(k string) int { return i.items[k] }
+
+type Worker struct{}
+
+func (w *Worker) Run() {}
+
+func StartWorker() {
+	w := &Worker{}
+	go w.Run()
+}
+`,
+	}, cfg)
+
+	pkg := findPackage(t, report, "cache")
+	tests := []struct {
+		structName string
+		wantRace   bool
+	}{
+		{"Racy", true},
+		{"Guarded", false},
+		{"WrongLock", true},
+		{"Idle", false},
+	}
+	for _, tt := range tests {
+		races := findStruct(t, pkg, tt.structName).MapRaces
+		if got := len(races) > 0; got != tt.wantRace {
+			t.Errorf("%s: map races %+v, want race = %v", tt.structName, races, tt.wantRace)
+		}
+	}
+
+	wrongLock := findStruct(t, pkg, "WrongLock").MapRaces
+	if len(wrongLock) == 1 && len(wrongLock[0].Methods) != 2 {
+		t.Errorf("WrongLock: methods %v, want Run and LateLock", wrongLock[0].Methods)
+	}
+
+	if got := len(diagnosticsOfType(report, DiagnosticPossibleMapRace)); got != 2 {
+		t.Errorf("got %d Possible Map Race diagnostics, want 2", got)
+	}
+}
+
+func TestPossibleMapRaceRequiresExperimental(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"cache/cache.go": `package cache
+
+type Racy struct {
+	items map[string]int
+}
+
+func (r *Racy) Start() { go r.Run() }
+
+func (r *Racy) Run() { r.items["a"] = 1 }
+
+func (r *Racy) Get(k string) int { return r.items[k] }
+`,
+	}, nil)
+
+	if got := len(diagnosticsOfType(report, DiagnosticPossibleMapRace)); got != 0 {
+		t.Errorf("got %d Possible Map Race diagnostics without -experimental, want 0", got)
+	}
+}
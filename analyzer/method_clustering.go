@@ -90,19 +90,9 @@ func extractAllMethods(structName string, file *ast.File) map[string]*methodCall
 		}
 
 		for _, recv := range funcDecl.Recv.List {
-			var recvTypeName string
+			recvTypeName := receiverTypeName(recv.Type)
 			var recvVarName string
 
-			// Get receiver type name
-			switch t := recv.Type.(type) {
-			case *ast.Ident:
-				recvTypeName = t.Name
-			case *ast.StarExpr:
-				if ident, ok := t.X.(*ast.Ident); ok {
-					recvTypeName = ident.Name
-				}
-			}
-
 			// Get receiver variable name
 			if len(recv.Names) > 0 {
 				recvVarName = recv.Names[0].Name
@@ -367,11 +357,11 @@ func suggestResponsibility(methods []string) string {
 		}
 	}
 
-	// Find most common keyword
+	// Find most common keyword (the alphabetically first on ties, for stable output)
 	maxCount := 0
 	commonWord := ""
 	for word, count := range keywords {
-		if count > maxCount {
+		if count > maxCount || (count == maxCount && word < commonWord) {
 			maxCount = count
 			commonWord = word
 		}
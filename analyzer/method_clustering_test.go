@@ -0,0 +1,59 @@
+package analyzer
+
+import "testing"
+
+func TestMethodIslandsDiagnostic(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"svc/svc.go": `package svc
+
+type Service struct {
+	path  string
+	queue []string
+}
+
+func (s *Service) Load() string { return s.readConfig() }
+
+func (s *Service) readConfig() string { return s.parseConfig(s.path) }
+
+func (s *Service) parseConfig(p string) string { return p }
+
+func (s *Service) Send(msg string) { s.queueMessage(msg) }
+
+func (s *Service) queueMessage(msg string) { s.queue = append(s.queue, s.encodeMessage(msg)) }
+
+func (s *Service) encodeMessage(msg string) string { return "[" + msg + "]" }
+
+type Parser struct {
+	input string
+}
+
+func (p *Parser) Parse() string { return p.scan() }
+
+func (p *Parser) scan() string { return p.token() }
+
+func (p *Parser) token() string { return p.input }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "svc")
+	service := findStruct(t, pkg, "Service")
+	if service.MethodClusters == nil || service.MethodClusters.ClusterCount != 2 {
+		t.Fatalf("Service method clusters = %+v, want 2", service.MethodClusters)
+	}
+
+	islands := diagnosticsOfType(report, DiagnosticMethodIslands)
+	if len(islands) != 1 {
+		t.Fatalf("got %d Method Islands diagnostics, want 1 (Service only): %+v", len(islands), islands)
+	}
+	if islands[0].TargetName != "svc.Service" {
+		t.Errorf("TargetName = %q, want svc.Service", islands[0].TargetName)
+	}
+
+	evidence, ok := islands[0].Evidence.(MethodIslandsEvidence)
+	if !ok {
+		t.Fatalf("evidence is %T, want MethodIslandsEvidence", islands[0].Evidence)
+	}
+	if evidence.ClusterCount != 2 || evidence.TotalPrivateMethods != 4 {
+		t.Errorf("evidence clusters = %d, private methods = %d, want 2 and 4", evidence.ClusterCount, evidence.TotalPrivateMethods)
+	}
+}
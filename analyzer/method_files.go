@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"go/ast"
+	"sort"
+)
+
+// collectMethodFiles maps each receiver type name to the sorted, distinct files its methods are declared in
+func collectMethodFiles(pkg *ast.Package) map[string][]string {
+	fileSets := make(map[string]map[string]bool)
+
+	for fileName, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+
+			recvTypeName := receiverTypeName(funcDecl.Recv.List[0].Type)
+			if recvTypeName == "" {
+				continue
+			}
+
+			if fileSets[recvTypeName] == nil {
+				fileSets[recvTypeName] = make(map[string]bool)
+			}
+			fileSets[recvTypeName][fileName] = true
+		}
+	}
+
+	methodFiles := make(map[string][]string, len(fileSets))
+	for typeName, files := range fileSets {
+		sorted := make([]string, 0, len(files))
+		for fileName := range files {
+			sorted = append(sorted, fileName)
+		}
+		sort.Strings(sorted)
+		methodFiles[typeName] = sorted
+	}
+	return methodFiles
+}
+
+// countMethods counts the methods declared on each receiver type across all files of a package
+func countMethods(pkg *ast.Package) map[string]int {
+	counts := make(map[string]int)
+
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+
+			if recvTypeName := receiverTypeName(funcDecl.Recv.List[0].Type); recvTypeName != "" {
+				counts[recvTypeName]++
+			}
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,225 @@
+package analyzer
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WorkFileName is the Go workspace file discovered at the root of the analyzed project
+const WorkFileName = "go.work"
+
+// moduleRoot is a Go module of the analyzed project
+type moduleRoot struct {
+	Dir  string // Module directory relative to the analysis root, slash-separated ("" for the root)
+	Path string // Module path declared in the directory's go.mod
+}
+
+// projectModules maps package paths relative to the analysis root to import paths and back.
+// A single-module project has one root; a go.work workspace has one per "use" directive.
+type projectModules []moduleRoot
+
+// determineModuleRoots returns the project's modules. With a go.work file at the root, each
+// module it uses is included; otherwise the root go.mod is the only module. The root directory
+// always belongs to a module, falling back to the directory name when it has no go.mod.
+func determineModuleRoots(rootPath string) projectModules {
+	var modules projectModules
+	hasRoot := false
+
+	for _, dir := range parseWorkUses(rootPath) {
+		modulePath, ok := readModulePath(filepath.Join(rootPath, filepath.FromSlash(dir)))
+		if !ok {
+			continue
+		}
+		if dir == "." {
+			dir = ""
+			hasRoot = true
+		}
+		modules = append(modules, moduleRoot{Dir: dir, Path: modulePath})
+	}
+
+	if !hasRoot {
+		modulePath, ok := readModulePath(rootPath)
+		if !ok {
+			modulePath = filepath.Base(rootPath)
+		}
+		modules = append(modules, moduleRoot{Dir: "", Path: modulePath})
+	}
+
+	return modules
+}
+
+// readModulePath returns the module path declared in a directory's go.mod
+func readModulePath(dir string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", false
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module")), `"`), true
+		}
+	}
+	return "", false
+}
+
+// parseWorkUses returns the module directories listed by "use" directives in the root go.work,
+// in single-line and block form, cleaned and slash-separated relative to the root
+func parseWorkUses(rootPath string) []string {
+	data, err := os.ReadFile(filepath.Join(rootPath, WorkFileName))
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "use (" || line == "use(":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "use "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "use"))
+		default:
+			continue
+		}
+
+		if line == "" || filepath.IsAbs(line) {
+			continue
+		}
+		dirs = append(dirs, path.Clean(filepath.ToSlash(strings.Trim(line, `"`))))
+	}
+
+	return dirs
+}
+
+// internalPrefixes returns the module paths whose imports count as internal: every project
+// module plus the modules each of them replaces with a local directory
+func (m projectModules) internalPrefixes(rootPath string) []string {
+	var prefixes []string
+	for _, module := range m {
+		prefixes = append(prefixes, module.Path)
+	}
+	for _, module := range m {
+		prefixes = append(prefixes, localReplacedModules(filepath.Join(rootPath, filepath.FromSlash(module.Dir)))...)
+	}
+	return prefixes
+}
+
+// localReplacedModules returns the module paths that a module's go.mod replaces with a local directory
+// ("replace example.com/lib => ../lib"), in single-line and block form.
+// Replacements with a module version on the right-hand side are remote and not included.
+func localReplacedModules(moduleDir string) []string {
+	data, err := os.ReadFile(filepath.Join(moduleDir, "go.mod"))
+	if err != nil {
+		return nil
+	}
+
+	var modules []string
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "replace (" || line == "replace(":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "replace "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "replace"))
+		default:
+			continue
+		}
+
+		from, to, found := strings.Cut(line, "=>")
+		if !found {
+			continue
+		}
+		fromFields := strings.Fields(from)
+		toFields := strings.Fields(to)
+		if len(fromFields) == 0 || len(toFields) != 1 {
+			continue
+		}
+
+		target := strings.Trim(toFields[0], `"`)
+		if strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") || filepath.IsAbs(target) {
+			modules = append(modules, strings.Trim(fromFields[0], `"`))
+		}
+	}
+
+	return modules
+}
+
+// importPath returns the full import path of a project package, resolved through the module
+// with the longest directory containing it
+func (m projectModules) importPath(pkgPath string) string {
+	var best *moduleRoot
+	for i := range m {
+		module := &m[i]
+		if module.Dir != "" && pkgPath != module.Dir && !strings.HasPrefix(pkgPath, module.Dir+"/") {
+			continue
+		}
+		if best == nil || len(module.Dir) > len(best.Dir) {
+			best = module
+		}
+	}
+	if best == nil {
+		return pkgPath
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(pkgPath, best.Dir), "/")
+	if rest == "" {
+		return best.Path
+	}
+	return best.Path + "/" + rest
+}
+
+// packagePath returns the project-relative path of an import path, and false if the import is
+// outside the analyzed modules. The module with the longest matching path wins.
+func (m projectModules) packagePath(importPath string) (string, bool) {
+	var best *moduleRoot
+	for i := range m {
+		module := &m[i]
+		if strings.HasPrefix(module.Dir, "../") || module.Dir == ".." {
+			// Used by the workspace but outside the analyzed tree
+			continue
+		}
+		if importPath != module.Path && !strings.HasPrefix(importPath, module.Path+"/") {
+			continue
+		}
+		if best == nil || len(module.Path) > len(best.Path) {
+			best = module
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+
+	rest := strings.TrimPrefix(strings.TrimPrefix(importPath, best.Path), "/")
+	switch {
+	case best.Dir == "":
+		return rest, true
+	case rest == "":
+		return best.Dir, true
+	default:
+		return best.Dir + "/" + rest, true
+	}
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestLocalReplacedModules(t *testing.T) {
+	dir := writeFixture(t, map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.24
+
+require (
+	example.com/lib v0.0.0
+	example.com/tools v0.0.0
+	example.com/remote v1.0.0
+)
+
+replace example.com/lib => ../lib
+
+replace (
+	example.com/tools v0.0.0 => ./tools
+	example.com/remote => example.com/fork v1.2.0
+)
+`,
+	})
+
+	if got, want := localReplacedModules(dir), []string{"example.com/lib", "example.com/tools"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("local replacements = %v, want %v", got, want)
+	}
+}
+
+func TestReplacedModulesAreInternal(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.24\n\nrequire example.com/lib v0.0.0\n\nreplace example.com/lib => ../lib\n",
+		"app.go": `package app
+
+import (
+	"strings"
+
+	"example.com/lib/text"
+)
+
+func Shout(s string) string { return text.Upper(strings.TrimSpace(s)) }
+`,
+	}, nil)
+
+	shout := findFunction(t, findPackage(t, report, ""), "Shout")
+	if !slices.Contains(shout.InternalDeps, "example.com/lib/text") {
+		t.Errorf("internal deps = %v, want the replaced module's package", shout.InternalDeps)
+	}
+	if slices.Contains(shout.ExternalDeps, "example.com/lib/text") {
+		t.Errorf("external deps = %v, replaced module counted as external", shout.ExternalDeps)
+	}
+}
+
+func TestWorkspaceModules(t *testing.T) {
+	files := map[string]string{
+		"go.work":    "go 1.24\n\nuse (\n\t./api // service\n\t./lib\n)\n",
+		"api/go.mod": "module example.com/api\n\ngo 1.24\n",
+		"api/handler/handler.go": `package handler
+
+import "example.com/lib/store"
+
+func Get(id int) string { return store.Load(id) }
+`,
+		"lib/go.mod": "module example.com/lib\n\ngo 1.24\n",
+		"lib/store/store.go": `package store
+
+func Load(id int) string { return "" }
+`,
+	}
+	dir := writeFixture(t, mergeFiles(files, map[string]string{"go.mod": "module example.com/root\n\ngo 1.24\n"}))
+
+	modules := determineModuleRoots(dir)
+	want := projectModules{{Dir: "api", Path: "example.com/api"}, {Dir: "lib", Path: "example.com/lib"}, {Dir: "", Path: "example.com/root"}}
+	if !reflect.DeepEqual(modules, want) {
+		t.Errorf("modules = %+v, want %+v", modules, want)
+	}
+	if got := modules.importPath("lib/store"); got != "example.com/lib/store" {
+		t.Errorf("importPath(lib/store) = %q, want example.com/lib/store", got)
+	}
+	if got, ok := modules.packagePath("example.com/api/handler"); !ok || got != "api/handler" {
+		t.Errorf("packagePath(example.com/api/handler) = %q, %v, want api/handler", got, ok)
+	}
+
+	report, err := AnalyzeWithOptions(dir, AnalyzeOptions{})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	// The cross-module import is internal coupling in both directions
+	handler := findPackage(t, report, "api/handler")
+	store := findPackage(t, report, "lib/store")
+	if handler.Efferent != 1 || store.Afferent != 1 {
+		t.Errorf("handler Ce = %d, store Ca = %d, want 1 and 1", handler.Efferent, store.Afferent)
+	}
+	if got := findFunction(t, store, "Load").Afferent; got != 1 {
+		t.Errorf("store.Load afferent = %d, want 1", got)
+	}
+	if get := findFunction(t, handler, "Get"); !slices.Contains(get.InternalDeps, "example.com/lib/store") {
+		t.Errorf("handler.Get internal deps = %v, want example.com/lib/store", get.InternalDeps)
+	}
+}
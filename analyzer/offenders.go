@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Offender kinds
+const (
+	OffenderFunction   = "function"
+	OffenderStruct     = "struct"
+	OffenderDiagnostic = "diagnostic"
+)
+
+// Offender score weights: a critical diagnostic outranks a bare metric at the same threshold ratio
+const (
+	metricOffenderWeight   = 1.0
+	criticalOffenderWeight = 2.0
+)
+
+// rankOffenders merges the most complex functions, the least cohesive structs, and the critical
+// diagnostics into one list ranked by score, capped at n entries.
+//
+// Scores put different metrics on one scale: weight * value / threshold, so a metric at its
+// diagnostic threshold scores its weight (complexity against complex_function_threshold, LCOM4
+// against god_object_lcom4) and twice the threshold scores twice as much. Scores are not capped,
+// so the worst code keeps its lead. A critical diagnostic is scored from the metric behind it
+// (see diagnosticRatio) with the critical weight. When a diagnostic and a metric point at the
+// same declaration, only the higher-ranked entry is kept.
+func rankOffenders(packages []PackageResult, diagnostics []DiagnosticResult, cfg *Config, n int) []Offender {
+	if n <= 0 {
+		return nil
+	}
+
+	var candidates []Offender
+	for _, d := range diagnostics {
+		if d.Severity != "Critical" {
+			continue
+		}
+		candidates = append(candidates, Offender{
+			Kind:        OffenderDiagnostic,
+			Name:        d.TargetName,
+			Reason:      d.Type,
+			Score:       criticalOffenderWeight * diagnosticRatio(d, cfg),
+			FilePath:    diagnosticFile(d),
+			RelatedPath: d.RelatedPath,
+		})
+	}
+
+	for _, pkg := range packages {
+		for _, f := range pkg.Functions {
+			if f.IsTest || f.Complexity <= 1 {
+				continue
+			}
+			candidates = append(candidates, Offender{
+				Kind:        OffenderFunction,
+				Name:        fmt.Sprintf("%s.%s", pkg.Name, f.FuncName),
+				Package:     pkg.Path,
+				Reason:      fmt.Sprintf("Complexity %d", f.Complexity),
+				Score:       metricOffenderWeight * thresholdRatio(float64(f.Complexity), float64(cfg.ComplexFunctionThreshold)),
+				FilePath:    f.FilePath,
+				Line:        f.Line,
+				RelatedPath: fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName),
+			})
+		}
+		for _, s := range pkg.Structs {
+			if s.IsTest || s.LCOM4Score <= 1 {
+				continue
+			}
+			candidates = append(candidates, Offender{
+				Kind:        OffenderStruct,
+				Name:        fmt.Sprintf("%s.%s", pkg.Name, s.StructName),
+				Package:     pkg.Path,
+				Reason:      fmt.Sprintf("LCOM4 %d", s.LCOM4Score),
+				Score:       metricOffenderWeight * thresholdRatio(float64(s.LCOM4Score), float64(cfg.GodObjectLCOM4)),
+				FilePath:    s.FilePath,
+				Line:        s.Line,
+				RelatedPath: fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName),
+			})
+		}
+	}
+
+	// Highest score first; diagnostics before metrics on a tie, then by name for a stable order
+	kindOrder := map[string]int{OffenderDiagnostic: 0, OffenderStruct: 1, OffenderFunction: 2}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Reason < b.Reason
+	})
+
+	var offenders []Offender
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if len(offenders) == n {
+			break
+		}
+		if candidate.RelatedPath != "" && seen[candidate.RelatedPath] {
+			continue
+		}
+		seen[candidate.RelatedPath] = true
+		offenders = append(offenders, candidate)
+	}
+
+	return offenders
+}
+
+// thresholdRatio normalizes a metric against its threshold: value / threshold, uncapped
+func thresholdRatio(value float64, threshold float64) float64 {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return value / threshold
+}
+
+// diagnosticRatio returns the metric behind a diagnostic relative to its threshold. Diagnostics
+// without a metric (import cycles, layering violations, duplicate declarations) rank as if at
+// their threshold.
+func diagnosticRatio(d DiagnosticResult, cfg *Config) float64 {
+	switch e := d.Evidence.(type) {
+	case GodObjectEvidence:
+		return thresholdRatio(float64(e.LCOM4Score), float64(cfg.GodObjectLCOM4))
+	case AmbiguousStructEvidence:
+		return thresholdRatio(float64(e.LCOM4Score), float64(cfg.AmbiguousStructLCOM4))
+	case ComplexFunctionEvidence:
+		return thresholdRatio(float64(e.Complexity), float64(cfg.ComplexFunctionThreshold))
+	case CognitiveComplexityEvidence:
+		return thresholdRatio(float64(e.CognitiveComplexity), float64(e.Threshold))
+	case GodFunctionEvidence:
+		return thresholdRatio(e.Score, e.Threshold)
+	case LongFunctionEvidence:
+		return thresholdRatio(float64(e.LoC), float64(e.Threshold))
+	case LongParameterListEvidence:
+		return thresholdRatio(float64(e.ParamCount), float64(e.Threshold))
+	case MagicNumberEvidence:
+		return thresholdRatio(float64(e.MagicNumbers), float64(e.Threshold))
+	case AwkwardAPIEvidence:
+		return thresholdRatio(float64(e.SurfaceComplexity), float64(e.Threshold))
+	case HighFanOutEvidence:
+		return thresholdRatio(float64(e.FanOut), float64(e.Threshold))
+	case HighStructComplexityEvidence:
+		return thresholdRatio(float64(e.WMC), float64(e.Threshold))
+	case LargeStructEvidence:
+		return math.Max(thresholdRatio(float64(e.FieldCount), float64(e.FieldThreshold)),
+			thresholdRatio(float64(e.MethodCount), float64(e.MethodThreshold)))
+	case ComplexityBudgetEvidence:
+		return thresholdRatio(e.Percent, e.MaxPercent)
+	case ShotgunSurgeryEvidence:
+		return thresholdRatio(float64(e.Afferent), float64(e.AfferentThreshold))
+	case UnstableFoundationEvidence:
+		return thresholdRatio(float64(e.Afferent), float64(cfg.UnstableFoundationAfferent))
+	}
+	return 1
+}
+
+// diagnosticFile returns the first source file of a diagnostic, or "" for package-level diagnostics
+func diagnosticFile(d DiagnosticResult) string {
+	if d.Evidence == nil {
+		return ""
+	}
+	if files := d.Evidence.SourceFiles(); len(files) > 0 {
+		return files[0]
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import "testing"
+
+func TestRankOffendersOrdersByScore(t *testing.T) {
+	cfg := DefaultConfig()
+	packages := []PackageResult{{
+		Name: "app",
+		Path: "app",
+		Functions: []FunctionResult{
+			{FuncName: "Small", Complexity: 5},
+			{FuncName: "Huge", Complexity: 70},
+			{FuncName: "Medium", Complexity: 20},
+			{FuncName: "Trivial", Complexity: 1},
+		},
+		Structs: []StructResult{
+			{StructName: "Cohesive", LCOM4Score: 1},
+			{StructName: "Split", LCOM4Score: 4},
+		},
+	}}
+	diagnostics := []DiagnosticResult{{
+		Type:        DiagnosticUntestedComplexFunction,
+		TargetName:  "app.Medium",
+		Severity:    "Critical",
+		Evidence:    ComplexFunctionEvidence{Complexity: 20, Function: "Medium"},
+		RelatedPath: "#function-app-Medium",
+	}}
+
+	offenders := rankOffenders(packages, diagnostics, cfg, 10)
+
+	// Huge: 70/15; Medium's diagnostic: 2 * 20/15 (outranks its metric, which is dropped);
+	// Split: 4/3; Small: 5/15; Trivial and Cohesive are not candidates
+	want := []string{"app.Huge", "app.Medium", "app.Split", "app.Small"}
+	if len(offenders) != len(want) {
+		t.Fatalf("got %d offenders, want %d: %+v", len(offenders), len(want), offenders)
+	}
+	for i, name := range want {
+		if offenders[i].Name != name {
+			t.Errorf("offender %d = %s, want %s", i, offenders[i].Name, name)
+		}
+		if i > 0 && offenders[i].Score > offenders[i-1].Score {
+			t.Errorf("offender %d scores %.2f, above offender %d (%.2f)", i, offenders[i].Score, i-1, offenders[i-1].Score)
+		}
+	}
+	if offenders[1].Kind != OffenderDiagnostic {
+		t.Errorf("app.Medium kind = %s, want %s", offenders[1].Kind, OffenderDiagnostic)
+	}
+}
+
+func TestRankOffendersUncappedScores(t *testing.T) {
+	cfg := DefaultConfig()
+	packages := []PackageResult{{
+		Name: "app",
+		Path: "app",
+		Functions: []FunctionResult{
+			{FuncName: "VeryComplex", Complexity: 71},
+			{FuncName: "Complex", Complexity: 31},
+		},
+	}}
+	diagnostics := []DiagnosticResult{
+		{
+			Type:        DiagnosticUntestedComplexFunction,
+			TargetName:  "app.Complex",
+			Severity:    "Critical",
+			Evidence:    ComplexFunctionEvidence{Complexity: 31, Function: "Complex"},
+			RelatedPath: "#function-app-Complex",
+		},
+		{
+			Type:        DiagnosticUntestedComplexFunction,
+			TargetName:  "app.VeryComplex",
+			Severity:    "Critical",
+			Evidence:    ComplexFunctionEvidence{Complexity: 71, Function: "VeryComplex"},
+			RelatedPath: "#function-app-VeryComplex",
+		},
+	}
+
+	offenders := rankOffenders(packages, diagnostics, cfg, 10)
+	if len(offenders) != 2 {
+		t.Fatalf("got %d offenders, want 2: %+v", len(offenders), offenders)
+	}
+	if offenders[0].Name != "app.VeryComplex" {
+		t.Errorf("first offender = %s, want app.VeryComplex", offenders[0].Name)
+	}
+	if offenders[0].Score <= offenders[1].Score {
+		t.Errorf("critical diagnostics tie at %.2f and %.2f; want the more complex function to score higher", offenders[0].Score, offenders[1].Score)
+	}
+}
+
+func TestRankOffendersCapsAtN(t *testing.T) {
+	cfg := DefaultConfig()
+	pkg := PackageResult{Name: "app", Path: "app"}
+	for i := 2; i < 30; i++ {
+		pkg.Functions = append(pkg.Functions, FunctionResult{FuncName: "F" + string(rune('A'+i)), Complexity: i})
+	}
+
+	for _, n := range []int{0, 1, 5, 10} {
+		offenders := rankOffenders([]PackageResult{pkg}, nil, cfg, n)
+		if len(offenders) != n {
+			t.Errorf("n=%d: got %d offenders", n, len(offenders))
+			continue
+		}
+		if n > 0 && offenders[0].Reason != "Complexity 29" {
+			t.Errorf("n=%d: first offender is %s (%s), want the complexity 29 function", n, offenders[0].Name, offenders[0].Reason)
+		}
+	}
+
+	if got := len(rankOffenders([]PackageResult{pkg}, nil, cfg, 100)); got != len(pkg.Functions) {
+		t.Errorf("n=100: got %d offenders, want all %d candidates", got, len(pkg.Functions))
+	}
+}
@@ -0,0 +1,36 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// progressLogger writes analysis progress, one line per event. A logger without a writer
+// discards everything, so analysis code can log unconditionally.
+type progressLogger struct {
+	mu sync.Mutex // Serializes lines written by concurrent parse workers
+	w  io.Writer
+}
+
+// newProgressLogger returns a logger writing to w (nil discards progress)
+func newProgressLogger(w io.Writer) *progressLogger {
+	return &progressLogger{w: w}
+}
+
+// logf writes one progress line
+func (l *progressLogger) logf(format string, args ...interface{}) {
+	if l == nil || l.w == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.w, format+"\n", args...)
+}
+
+// phase reports how long an analysis phase took
+func (l *progressLogger) phase(name string, elapsed time.Duration) {
+	l.logf("%s took %s", name, elapsed.Round(time.Millisecond))
+}
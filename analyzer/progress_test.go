@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeProgressLogging(t *testing.T) {
+	dir := writeFixture(t, map[string]string{
+		"app/app.go":   "package app\n\nimport \"example.com/app/util\"\n\nfunc Run() string { return util.Name() }\n",
+		"util/util.go": "package util\n\nfunc Name() string { return \"app\" }\n",
+	})
+
+	var log bytes.Buffer
+	if _, err := AnalyzeWithOptions(dir, AnalyzeOptions{Logger: &log}); err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	out := log.String()
+
+	for _, want := range []string{
+		"Parsing app\n",
+		"Parsing util\n",
+		"Found 2 packages\n",
+		"Parsing took ",
+		"LCOM4 took ",
+		"Complexity took ",
+		"Coupling took ",
+		"Diagnostics took ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("progress output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestProgressLoggerWithoutWriter(t *testing.T) {
+	// A nil writer keeps the analysis silent instead of failing
+	newProgressLogger(nil).logf("Parsing %s", "app")
+	var nilLogger *progressLogger
+	nilLogger.phase("Parsing", 0)
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"go/ast"
+	"sort"
+)
+
+// AnalyzeReceiverMutations finds value-receiver methods that write to fields of the struct.
+// Such writes modify a copy of the receiver and are lost when the method returns.
+func AnalyzeReceiverMutations(structName string, file *ast.File, fields []string) []ReceiverMutation {
+	var mutations []ReceiverMutation
+
+	// Create field map for quick lookup
+	fieldMap := make(map[string]bool)
+	for _, field := range fields {
+		fieldMap[field] = true
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		funcDecl, ok := n.(*ast.FuncDecl)
+		if !ok {
+			return true
+		}
+
+		// Check if this is a method of our struct
+		if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+			return true
+		}
+
+		recv := funcDecl.Recv.List[0]
+
+		// Only value receivers lose their writes; pointer receivers are fine
+		if _, isPointer := recv.Type.(*ast.StarExpr); isPointer || receiverTypeName(recv.Type) != structName {
+			return true
+		}
+
+		// Unnamed receivers cannot be written to
+		if len(recv.Names) == 0 || recv.Names[0].Name == "_" {
+			return true
+		}
+
+		// Methods returning the struct type modify and return a copy on purpose (e.g. WithX builders)
+		if returnsType(funcDecl, structName) {
+			return true
+		}
+
+		fieldUsage := findFieldUsageWeighted(funcDecl.Body, recv.Names[0].Name, fieldMap)
+
+		var written []string
+		for field, weight := range fieldUsage {
+			// Weight 2 = write, 3 = read+write
+			if weight >= 2 {
+				written = append(written, field)
+			}
+		}
+
+		if len(written) > 0 {
+			sort.Strings(written)
+			mutations = append(mutations, ReceiverMutation{
+				Method: funcDecl.Name.Name,
+				Fields: written,
+			})
+		}
+
+		return true
+	})
+
+	// Sort for consistent output
+	sort.Slice(mutations, func(i, j int) bool {
+		return mutations[i].Method < mutations[j].Method
+	})
+
+	return mutations
+}
+
+// returnsType checks if a function returns a value (or pointer) of the named type
+func returnsType(funcDecl *ast.FuncDecl, typeName string) bool {
+	if funcDecl.Type.Results == nil {
+		return false
+	}
+
+	for _, result := range funcDecl.Type.Results.List {
+		if receiverTypeName(result.Type) == typeName {
+			return true
+		}
+	}
+
+	return false
+}
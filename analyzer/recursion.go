@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"go/ast"
+)
+
+// markRecursion sets IsRecursive on functions that call themselves and IsMutuallyRecursive on
+// functions in a call cycle with other functions of the package. calls maps each function to
+// the package functions it calls. Mutual recursion cannot span packages, since Go forbids
+// import cycles.
+func markRecursion(functions []FunctionResult, calls map[string][]string) {
+	mutual := make(map[string]bool)
+	for _, component := range stronglyConnectedComponents(calls) {
+		for _, name := range component {
+			mutual[name] = true
+		}
+	}
+
+	for i := range functions {
+		f := &functions[i]
+		f.IsMutuallyRecursive = mutual[f.FuncName]
+		for _, called := range calls[f.FuncName] {
+			if called == f.FuncName {
+				f.IsRecursive = true
+				break
+			}
+		}
+	}
+}
+
+// receiverName returns the name of a method's receiver, or "" for functions and unnamed receivers
+func receiverName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 || len(funcDecl.Recv.List[0].Names) == 0 {
+		return ""
+	}
+	return funcDecl.Recv.List[0].Names[0].Name
+}
+
+// hasRecursiveClosure reports whether a function body assigns a function literal to a variable
+// that the literal itself calls, as in
+//
+//	var walk func(n *Node)
+//	walk = func(n *Node) { ... walk(child) ... }
+func hasRecursiveClosure(body *ast.BlockStmt) bool {
+	if body == nil {
+		return false
+	}
+
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+		for i, rhs := range assign.Rhs {
+			lit, ok := rhs.(*ast.FuncLit)
+			if !ok {
+				continue
+			}
+			variable, ok := assign.Lhs[i].(*ast.Ident)
+			if !ok || variable.Name == "_" {
+				continue
+			}
+			if callsIdent(lit.Body, variable) {
+				found = true
+				return false
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// callsIdent reports whether a block calls the variable declared by ident. Identifiers are
+// matched by their resolved object when the parser bound one, by name otherwise.
+func callsIdent(body *ast.BlockStmt, ident *ast.Ident) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		called, ok := call.Fun.(*ast.Ident)
+		if !ok || called.Name != ident.Name {
+			return true
+		}
+		if ident.Obj == nil || called.Obj == ident.Obj {
+			found = true
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRecursionDetection(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"rec/rec.go": `package rec
+
+func Factorial(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * Factorial(n-1)
+}
+
+func IsEven(n int) bool {
+	if n == 0 {
+		return true
+	}
+	return IsOdd(n - 1)
+}
+
+func IsOdd(n int) bool {
+	if n == 0 {
+		return false
+	}
+	return IsEven(n - 1)
+}
+
+type Tree struct {
+	Children []*Tree
+}
+
+type Parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *Parser) Expr() int {
+	if p.pos < len(p.tokens) && p.tokens[p.pos] == "(" {
+		return p.group()
+	}
+	return 1
+}
+
+func (p *Parser) group() int {
+	p.pos++
+	return p.Expr()
+}
+
+func (t *Tree) Depth() int {
+	var depth func(n *Tree) int
+	depth = func(n *Tree) int {
+		max := 0
+		for _, c := range n.Children {
+			if d := depth(c); d > max {
+				max = d
+			}
+		}
+		return max + 1
+	}
+	return depth(t)
+}
+
+func Twice(n int) int { return Factorial(n) * 2 }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "rec")
+	tests := []struct {
+		name      string
+		recursive bool
+		mutual    bool
+	}{
+		{"Factorial", true, false},
+		{"IsEven", false, true},
+		{"IsOdd", false, true},
+		{"Parser.Expr", false, true},
+		{"Parser.group", false, true},
+		{"Tree.Depth", true, false},
+		{"Twice", false, false},
+	}
+	for _, tt := range tests {
+		f := findFunction(t, pkg, tt.name)
+		if f.IsRecursive != tt.recursive || f.IsMutuallyRecursive != tt.mutual {
+			t.Errorf("%s: recursive = %v, mutual = %v, want %v and %v", tt.name, f.IsRecursive, f.IsMutuallyRecursive, tt.recursive, tt.mutual)
+		}
+	}
+}
+
+func TestRecursionJSONKeys(t *testing.T) {
+	data, err := json.Marshal(FunctionResult{IsRecursive: true, IsMutuallyRecursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"is_recursive":true`, `"is_mutually_recursive":true`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("JSON %s lacks %s", data, key)
+		}
+	}
+
+	// Both flags are left out when unset
+	data, err = json.Marshal(FunctionResult{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "recursive") {
+		t.Errorf("JSON %s has recursion flags, want them omitted when false", data)
+	}
+}
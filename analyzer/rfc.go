@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// applyResponseForClass sets each struct's RFC (Response For a Class): the number of its methods
+// plus the distinct functions and methods those methods call, including standard library and
+// other external calls. Builtins and conversions to predeclared or package-level types are not calls.
+func applyResponseForClass(structs []StructResult, pkg *ast.Package) {
+	response := make(map[string]map[string]bool)
+
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+
+			structName := receiverTypeName(funcDecl.Recv.List[0].Type)
+			if structName == "" {
+				continue
+			}
+			recvName := ""
+			if len(funcDecl.Recv.List[0].Names) > 0 {
+				recvName = funcDecl.Recv.List[0].Names[0].Name
+			}
+
+			if response[structName] == nil {
+				response[structName] = make(map[string]bool)
+			}
+			response[structName][structName+"."+funcDecl.Name.Name] = true
+			for target := range extractCallTargets(funcDecl.Body, recvName, structName) {
+				response[structName][target] = true
+			}
+		}
+	}
+
+	for i := range structs {
+		structs[i].RFC = len(response[structs[i].StructName])
+	}
+}
+
+// extractCallTargets returns every function or method called in a body with its frequency.
+// Like extractMethodCallsWithFrequency, calls on the receiver are keyed "Struct.method";
+// other calls are keyed by their source text ("helper", "fmt.Println", "s.buf.WriteString").
+func extractCallTargets(body *ast.BlockStmt, recvName string, structName string) map[string]int {
+	calls := make(map[string]int)
+
+	if body == nil {
+		return nil
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		switch fun := ast.Unparen(callExpr.Fun).(type) {
+		case *ast.Ident:
+			if fun.Obj == nil && types.Universe.Lookup(fun.Name) != nil {
+				// Builtin function or predeclared type conversion
+				return true
+			}
+			if fun.Obj != nil && fun.Obj.Kind == ast.Typ {
+				return true
+			}
+			calls[fun.Name]++
+		case *ast.SelectorExpr:
+			target := types.ExprString(fun)
+			if recvName != "" && recvName != "_" && strings.HasPrefix(target, recvName+".") {
+				target = structName + strings.TrimPrefix(target, recvName)
+			}
+			calls[target]++
+		}
+
+		return true
+	})
+
+	return calls
+}
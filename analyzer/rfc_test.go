@@ -0,0 +1,37 @@
+package analyzer
+
+import "testing"
+
+func TestResponseForClass(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"cache/cache.go": `package cache
+
+import "strings"
+
+type Cache struct{ items map[string]string }
+
+func (c *Cache) Get(key string) string {
+	return c.items[normalize(key)]
+}
+
+func (c *Cache) Put(key, value string) {
+	c.items[normalize(key)] = strings.TrimSpace(value)
+	c.evict()
+}
+
+func (c *Cache) evict() {
+	if len(c.items) > 100 {
+		c.items = make(map[string]string, len(c.items))
+	}
+}
+
+func normalize(key string) string { return key }
+`,
+	}, nil)
+
+	// Three methods, plus normalize and strings.TrimSpace; evict is already a method, and the
+	// make and len builtins are not calls
+	if got := findStruct(t, findPackage(t, report, "cache"), "Cache").RFC; got != 5 {
+		t.Errorf("RFC = %d, want 5", got)
+	}
+}
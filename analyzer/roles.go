@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Package roles (Config.PackageRoles values)
+const (
+	RoleStable   = "stable"   // Depended upon and expected to change rarely (e.g. domain): instability should be low
+	RoleUnstable = "unstable" // Depends on others and is free to change (e.g. api, cmd): instability should be high
+)
+
+// packageRole returns the role configured for a package and the pattern that assigned it.
+// When several patterns match, the most specific (longest) pattern wins.
+func (c *Config) packageRole(pkgPath string) (string, string) {
+	patterns := make([]string, 0, len(c.PackageRoles))
+	for pattern := range c.PackageRoles {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	for _, pattern := range patterns {
+		if matchPathPattern(strings.Trim(pattern, "/"), pkgPath) {
+			return c.PackageRoles[pattern], pattern
+		}
+	}
+	return "", ""
+}
+
+// validateRoles checks the package role patterns and values
+func (c *Config) validateRoles() error {
+	for pattern, role := range c.PackageRoles {
+		if role != RoleStable && role != RoleUnstable {
+			return fmt.Errorf("package_roles: role of %q must be %q or %q, got %q", pattern, RoleStable, RoleUnstable, role)
+		}
+		if _, err := path.Match(strings.Trim(pattern, "/"), ""); err != nil {
+			return fmt.Errorf("package_roles: invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package analyzer
+
+import "math"
+
+// Health score thresholds, matching the "high" counts in the report summary
+const (
+	scoreHighComplexity  = 15  // Functions with complexity above this count as complex
+	scoreHighLCOM4       = 2   // Structs with LCOM4 above this count as incohesive
+	scoreHighInstability = 0.7 // Packages with instability above this count as unstable
+)
+
+// Health score weights: the most each factor can subtract from 100.
+// Metric ratios are scaled by their weight (e.g. 20% complex functions costs 0.2 * 25 = 5 points);
+// diagnostics cost a fixed amount per severity, capped at scoreWeightDiagnostics.
+const (
+	scoreWeightComplexity  = 25.0
+	scoreWeightLCOM4       = 15.0
+	scoreWeightInstability = 10.0
+	scoreWeightDiagnostics = 50.0
+
+	scorePenaltyCritical = 5.0
+	scorePenaltyWarning  = 1.0
+	scorePenaltyInfo     = 0.2
+)
+
+// CalculateHealthScore returns a project health score from 0 (worst) to 100 (best):
+//
+//	100 - 25 * (functions with complexity > 15) / functions
+//	    - 15 * (structs with LCOM4 > 2) / structs
+//	    - 10 * (packages with instability > 0.7) / packages
+//	    - min(50, 5 * critical + 1 * warning + 0.2 * info diagnostics)
+//
+// clamped to [0, 100]. Adding a diagnostic or a worse metric never raises the score.
+func CalculateHealthScore(packages []PackageResult, diagnostics []DiagnosticResult) float64 {
+	var functions, complexFunctions int
+	var structs, incohesiveStructs int
+	var unstablePackages int
+
+	for _, pkg := range packages {
+		if pkg.Instability > scoreHighInstability {
+			unstablePackages++
+		}
+		for _, s := range pkg.Structs {
+			structs++
+			if s.LCOM4Score > scoreHighLCOM4 {
+				incohesiveStructs++
+			}
+		}
+		for _, f := range pkg.Functions {
+			functions++
+			if f.Complexity > scoreHighComplexity {
+				complexFunctions++
+			}
+		}
+	}
+
+	diagnosticPenalty := 0.0
+	for _, d := range diagnostics {
+		switch d.Severity {
+		case "Critical":
+			diagnosticPenalty += scorePenaltyCritical
+		case "Warning":
+			diagnosticPenalty += scorePenaltyWarning
+		case "Info":
+			diagnosticPenalty += scorePenaltyInfo
+		}
+	}
+
+	score := 100.0
+	score -= scoreWeightComplexity * ratio(complexFunctions, functions)
+	score -= scoreWeightLCOM4 * ratio(incohesiveStructs, structs)
+	score -= scoreWeightInstability * ratio(unstablePackages, len(packages))
+	score -= math.Min(scoreWeightDiagnostics, diagnosticPenalty)
+
+	return math.Max(0, math.Min(100, score))
+}
+
+// ratio returns part / total, or 0 when total is 0
+func ratio(part int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
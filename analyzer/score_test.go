@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestHealthScoreBounds(t *testing.T) {
+	if got := CalculateHealthScore(nil, nil); got != 100 {
+		t.Errorf("empty project score = %v, want 100", got)
+	}
+
+	// Every metric at its worst plus far more diagnostics than the cap still scores 0
+	worst := []PackageResult{{
+		Instability: 1,
+		Structs:     []StructResult{{LCOM4Score: 10}},
+		Functions:   []FunctionResult{{Complexity: 50}},
+	}}
+	var diagnostics []DiagnosticResult
+	for i := 0; i < 100; i++ {
+		diagnostics = append(diagnostics, DiagnosticResult{Severity: "Critical"})
+	}
+	if got := CalculateHealthScore(worst, diagnostics); got != 0 {
+		t.Errorf("worst project score = %v, want 0", got)
+	}
+}
+
+func TestHealthScoreIsMonotonic(t *testing.T) {
+	packages := []PackageResult{{
+		Instability: 0.2,
+		Structs:     []StructResult{{LCOM4Score: 1}, {LCOM4Score: 3}},
+		Functions:   []FunctionResult{{Complexity: 3}, {Complexity: 20}},
+	}}
+	diagnostics := []DiagnosticResult{{Severity: "Warning"}}
+
+	before := CalculateHealthScore(packages, diagnostics)
+	after := CalculateHealthScore(packages, append(diagnostics, DiagnosticResult{Severity: "Critical"}))
+	if after != before-scorePenaltyCritical {
+		t.Errorf("score with an added critical diagnostic = %v, want %v - %v", after, before, scorePenaltyCritical)
+	}
+}
+
+func TestHealthScoreOfFixtures(t *testing.T) {
+	clean := analyzeFixture(t, map[string]string{
+		"calc/calc.go": `package calc
+
+// Add returns the sum of a and b
+func Add(a, b int) int { return a + b }
+
+// Sub returns the difference of a and b
+func Sub(a, b int) int { return a - b }
+`,
+	}, nil)
+	if clean.HealthScore < 95 {
+		t.Errorf("clean project score = %.1f, want near 100 (diagnostics %+v)", clean.HealthScore, clean.Diagnostics)
+	}
+
+	// Deeply branching functions, an import cycle, and a struct with unrelated methods
+	var branches strings.Builder
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&branches, "\tif n == %d {\n\t\tn += %d\n\t}\n", i+100, i+200)
+	}
+	messy := analyzeFixture(t, map[string]string{
+		"a/a.go": "package a\n\nimport \"example.com/app/b\"\n\nfunc Tangle(n int) int {\n" + branches.String() + "\treturn b.Tangle(n)\n}\n",
+		"b/b.go": "package b\n\nimport \"example.com/app/a\"\n\nfunc Tangle(n int) int {\n" + branches.String() + "\treturn a.Tangle(n)\n}\n",
+		"c/c.go": `package c
+
+type Mixed struct{ a, b, c int }
+
+func (m *Mixed) A() { m.a++ }
+
+func (m *Mixed) B() { m.b++ }
+
+func (m *Mixed) C() { m.c++ }
+`,
+	}, nil)
+	if messy.HealthScore > 60 {
+		t.Errorf("messy project score = %.1f, want a low score", messy.HealthScore)
+	}
+	if messy.HealthScore >= clean.HealthScore {
+		t.Errorf("messy score %.1f should be below clean score %.1f", messy.HealthScore, clean.HealthScore)
+	}
+}
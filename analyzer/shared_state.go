@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// findUnsynchronizedAccesses finds methods of a struct that may touch its state concurrently without
+// a lock. Two patterns are reported:
+//
+//   - the struct has a sync.Mutex or sync.RWMutex field (named or embedded), and a method reads or
+//     writes other fields without calling Lock/RLock
+//   - a method starts a goroutine (go func() { ... }() or go recv.method()) whose body touches
+//     fields without calling Lock/RLock
+//
+// Fields that synchronize themselves (types from sync and sync/atomic, channels) are ignored, and
+// methods named *Locked or locked* are assumed to be called with the lock held. Mutexes are
+// recognized by type name and locking by the Lock/RLock selector, so this is a best-effort
+// syntactic heuristic: fields only set before the struct is shared, or guarded by a lock the
+// caller takes, are reported too.
+func findUnsynchronizedAccesses(structName string, structType *ast.StructType, pkg *ast.Package) []UnsynchronizedAccess {
+	stateFields, mutexFields := classifySharedFields(structType)
+	if len(stateFields) == 0 {
+		return nil
+	}
+
+	type method struct {
+		decl     *ast.FuncDecl
+		recvName string
+	}
+	var methods []method
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+			recv := funcDecl.Recv.List[0]
+			if receiverTypeName(recv.Type) != structName || len(recv.Names) == 0 || recv.Names[0].Name == "_" {
+				continue
+			}
+			methods = append(methods, method{decl: funcDecl, recvName: recv.Names[0].Name})
+		}
+	}
+
+	// Fields each reported method touches, and whether it does so from a goroutine
+	accessed := make(map[string]map[string]bool)
+	fromGoroutine := make(map[string]bool)
+	record := func(methodName string, fields map[string]bool, goroutine bool) {
+		if len(fields) == 0 {
+			return
+		}
+		if accessed[methodName] == nil {
+			accessed[methodName] = make(map[string]bool)
+		}
+		for field := range fields {
+			accessed[methodName][field] = true
+		}
+		fromGoroutine[methodName] = fromGoroutine[methodName] || goroutine
+	}
+
+	// Unlocked field accesses of each method, and the methods started as goroutines on the receiver
+	unlocked := make(map[string]map[string]bool)
+	launched := make(map[string]bool)
+	for _, m := range methods {
+		name := m.decl.Name.Name
+		if !callsLock(m.decl.Body) && !assumesLockHeld(name) {
+			unlocked[name] = findReceiverFieldAccess(m.decl.Body, m.recvName, stateFields)
+			if len(mutexFields) > 0 {
+				record(name, unlocked[name], false)
+			}
+		}
+
+		ast.Inspect(m.decl.Body, func(n ast.Node) bool {
+			goStmt, ok := n.(*ast.GoStmt)
+			if !ok {
+				return true
+			}
+			switch fun := goStmt.Call.Fun.(type) {
+			case *ast.FuncLit:
+				if !callsLock(fun.Body) {
+					record(name, findReceiverFieldAccess(fun.Body, m.recvName, stateFields), true)
+				}
+			case *ast.SelectorExpr:
+				if ident, ok := fun.X.(*ast.Ident); ok && ident.Name == m.recvName {
+					launched[fun.Sel.Name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	// A method started with go recv.method() touches the fields from another goroutine
+	for name := range launched {
+		record(name, unlocked[name], true)
+	}
+
+	var results []UnsynchronizedAccess
+	for name, fields := range accessed {
+		access := UnsynchronizedAccess{Method: name, Goroutine: fromGoroutine[name]}
+		for field := range fields {
+			access.Fields = append(access.Fields, field)
+		}
+		sort.Strings(access.Fields)
+		results = append(results, access)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Method < results[j].Method
+	})
+
+	return results
+}
+
+// classifySharedFields returns the fields of a struct that hold shared state, and its mutex fields.
+// Fields whose types synchronize themselves (sync.*, atomic.*, channels) are neither.
+func classifySharedFields(structType *ast.StructType) (state map[string]bool, mutexes []string) {
+	state = make(map[string]bool)
+	if structType.Fields == nil {
+		return state, nil
+	}
+
+	for _, field := range structType.Fields.List {
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		if len(field.Names) == 0 {
+			if name := embeddedFieldName(field.Type); name != "" {
+				names = append(names, name)
+			}
+		}
+
+		typ := field.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		if _, ok := typ.(*ast.ChanType); ok {
+			continue
+		}
+		if selector, ok := typ.(*ast.SelectorExpr); ok {
+			if pkgIdent, ok := selector.X.(*ast.Ident); ok && (pkgIdent.Name == "sync" || pkgIdent.Name == "atomic") {
+				if pkgIdent.Name == "sync" && (selector.Sel.Name == "Mutex" || selector.Sel.Name == "RWMutex") {
+					mutexes = append(mutexes, names...)
+				}
+				continue
+			}
+		}
+
+		for _, name := range names {
+			if name != "_" {
+				state[name] = true
+			}
+		}
+	}
+
+	return state, mutexes
+}
+
+// assumesLockHeld reports whether a method name follows the convention for methods whose caller
+// holds the lock (fooLocked, lockedFoo)
+func assumesLockHeld(name string) bool {
+	return strings.HasSuffix(name, "Locked") || strings.HasPrefix(name, "locked")
+}
+
+// findReceiverFieldAccess returns the fields a body reads or writes through the receiver (recv.field)
+func findReceiverFieldAccess(body *ast.BlockStmt, recvName string, fields map[string]bool) map[string]bool {
+	accessed := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		selector, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == recvName && fields[selector.Sel.Name] {
+			accessed[selector.Sel.Name] = true
+		}
+		return true
+	})
+	return accessed
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnsynchronizedSharedState(t *testing.T) {
+	files := map[string]string{
+		"counter/counter.go": `package counter
+
+import "sync"
+
+// Counter locks in Inc but not in Peek or Reset
+type Counter struct {
+	mu    sync.Mutex
+	n     int
+	hits  int
+	ready chan struct{}
+}
+
+func (c *Counter) Inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+	c.hits++
+}
+
+func (c *Counter) Peek() int { return c.n }
+
+func (c *Counter) Reset() {
+	c.n = 0
+	c.hits = 0
+}
+
+// resetLocked is called with the lock held
+func (c *Counter) resetLocked() { c.n = 0 }
+
+// Wait only uses a channel, which synchronizes itself
+func (c *Counter) Wait() { <-c.ready }
+
+// Worker has no mutex but writes a field from a goroutine
+type Worker struct {
+	done bool
+}
+
+func (w *Worker) Start() {
+	go func() {
+		w.done = true
+	}()
+}
+
+// Plain has neither a mutex nor goroutines
+type Plain struct{ n int }
+
+func (p *Plain) Set(n int) { p.n = n }
+`,
+	}
+
+	// The diagnostic is experimental
+	if got := diagnosticsOfType(analyzeFixture(t, files, nil), DiagnosticUnsynchronizedState); len(got) != 0 {
+		t.Errorf("got %d diagnostics without experimental diagnostics enabled", len(got))
+	}
+
+	cfg := DefaultConfig()
+	cfg.Experimental = true
+	report := analyzeFixture(t, files, cfg)
+
+	pkg := findPackage(t, report, "counter")
+	accesses := make(map[string][]string)
+	for _, access := range findStruct(t, pkg, "Counter").UnsynchronizedAccesses {
+		accesses[access.Method] = access.Fields
+	}
+	want := map[string][]string{
+		"Peek":  {"n"},
+		"Reset": {"hits", "n"},
+	}
+	if !reflect.DeepEqual(accesses, want) {
+		t.Errorf("Counter accesses = %v, want %v", accesses, want)
+	}
+
+	worker := findStruct(t, pkg, "Worker").UnsynchronizedAccesses
+	if len(worker) != 1 || worker[0].Method != "Start" || !worker[0].Goroutine {
+		t.Errorf("Worker accesses = %+v, want Start in a goroutine", worker)
+	}
+
+	var targets []string
+	for _, d := range diagnosticsOfType(report, DiagnosticUnsynchronizedState) {
+		targets = append(targets, d.TargetName)
+	}
+	if len(targets) != 2 || findStruct(t, pkg, "Plain").UnsynchronizedAccesses != nil {
+		t.Errorf("diagnosed %v, want counter.Counter and counter.Worker only", targets)
+	}
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// extractResultTypes returns the type of each value a function returns.
+// Grouped named results such as (a, b int) yield one entry per name.
+func extractResultTypes(funcDecl *ast.FuncDecl) []string {
+	results := funcDecl.Type.Results
+	if results == nil {
+		return nil
+	}
+
+	var resultTypes []string
+	for _, field := range results.List {
+		typeName := types.ExprString(field.Type)
+		count := len(field.Names)
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			resultTypes = append(resultTypes, typeName)
+		}
+	}
+	return resultTypes
+}
+
+// significantResultCount counts returned values. Only the idiomatic two-value (T, error) and
+// (T, bool) forms are exempt and cost the same as returning T; longer lists count every result.
+func significantResultCount(resultTypes []string) int {
+	count := len(resultTypes)
+	if count == 2 {
+		if last := resultTypes[1]; last == "error" || last == "bool" {
+			count--
+		}
+	}
+	return count
+}
+
+// Penalties added to SurfaceComplexity for signature features that make a function harder to call
+const (
+	surfaceEmptyInterfacePenalty = 2 // per interface{}/any parameter, which the compiler cannot check
+	surfaceExtraErrorPenalty     = 2 // per error-like result after the first
+)
+
+// surfaceComplexity measures how hard a function is to call: its parameter count (variadics count
+// once, the receiver is not counted) plus its result count (grouped named results count per name),
+// plus a penalty for each interface{}/any parameter and for each error-like result after the first
+func surfaceComplexity(funcDecl *ast.FuncDecl, resultTypes []string) int {
+	complexity := countParameters(funcDecl) + len(resultTypes)
+
+	if funcDecl.Type.Params != nil {
+		for _, field := range funcDecl.Type.Params.List {
+			typ := field.Type
+			if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+				typ = ellipsis.Elt
+			}
+			if !isEmptyInterface(typ) {
+				continue
+			}
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			complexity += count * surfaceEmptyInterfacePenalty
+		}
+	}
+
+	errorResults := 0
+	for _, resultType := range resultTypes {
+		if isErrorLikeType(resultType) {
+			errorResults++
+		}
+	}
+	if errorResults > 1 {
+		complexity += (errorResults - 1) * surfaceExtraErrorPenalty
+	}
+
+	return complexity
+}
+
+// isEmptyInterface reports whether a type expression is interface{} or any
+func isEmptyInterface(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name == "any"
+	case *ast.InterfaceType:
+		return t.Methods == nil || len(t.Methods.List) == 0
+	}
+	return false
+}
+
+// isErrorLikeType reports whether a type name is error or names an error type (*MyError, pkg.Error)
+func isErrorLikeType(typeName string) bool {
+	return typeName == "error" || strings.HasSuffix(typeName, "Error")
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import "testing"
+
+func TestSignificantResultCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		resultTypes []string
+		want        int
+	}{
+		{"none", nil, 0},
+		{"single error", []string{"error"}, 1},
+		{"value and error", []string{"T", "error"}, 1},
+		{"value and ok", []string{"T", "bool"}, 1},
+		{"two values", []string{"int", "string"}, 2},
+		{"three values and error", []string{"A", "B", "C", "error"}, 4},
+		{"three values and ok", []string{"A", "B", "C", "bool"}, 4},
+		{"two values and error", []string{"A", "B", "error"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := significantResultCount(tt.resultTypes); got != tt.want {
+				t.Errorf("significantResultCount(%v) = %d, want %d", tt.resultTypes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectTooManyReturnValues(t *testing.T) {
+	packages := []PackageResult{{
+		Name: "app",
+		Path: "app",
+		Functions: []FunctionResult{
+			{FuncName: "Load", ResultTypes: []string{"A", "B", "C", "error"}, ResultCount: 4},
+			{FuncName: "Get", ResultTypes: []string{"T", "error"}, ResultCount: 2},
+			{FuncName: "Split", ResultTypes: []string{"A", "B", "error"}, ResultCount: 3},
+		},
+	}}
+
+	diagnostics := detectTooManyReturnValues(packages)
+	if len(diagnostics) != 1 || diagnostics[0].TargetName != "app.Load" {
+		t.Fatalf("got %+v, want one diagnostic for app.Load", diagnostics)
+	}
+}
+
+func TestCountParameters(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"no parameters", `func f() {}`, 0},
+		{"grouped", `func f(a, b int, c string) {}`, 3},
+		{"variadic counts once", `func f(format string, args ...any) {}`, 2},
+		{"unnamed", `func f(int, string, bool) {}`, 3},
+		{"receiver is not a parameter", `func (s *S) f(a int) {}`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countParameters(parseFunc(t, tt.src)); got != tt.want {
+				t.Errorf("countParameters = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLongParameterList(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"app/app.go": `package app
+
+type Server struct{}
+
+func Five(a, b, c, d, e int) {}
+
+func Four(a, b, c int, rest ...int) {}
+
+func (s *Server) Handle(a, b int, c, d string, e bool) {}
+`,
+	}, nil)
+
+	got := map[string]string{}
+	for _, d := range diagnosticsOfType(report, DiagnosticLongParameterList) {
+		got[d.TargetName] = d.Severity
+	}
+	want := map[string]string{"app.Five": "Warning", "app.Server.Handle": "Warning"}
+	if len(got) != len(want) || got["app.Five"] != want["app.Five"] || got["app.Server.Handle"] != want["app.Server.Handle"] {
+		t.Errorf("Long Parameter List diagnostics = %v, want %v", got, want)
+	}
+}
+
+func TestSurfaceComplexity(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"clean", `func Get(ctx context.Context, id string) (User, error) { return User{}, nil }`, 4},
+		{"six parameters, three results", `func Sync(a, b, c string, d, e int, f bool) (int, int, error) { return 0, 0, nil }`, 9},
+		{"grouped named results count per name", `func Split(s string) (head, tail string) { return }`, 3},
+		{"variadic any counts once with a penalty", `func Log(format string, args ...any) {}`, 2 + 2},
+		{"interface{} parameter", `func Store(key string, value interface{}) error { return nil }`, 3 + 2},
+		{"second error-like result", `func Check() (error, *ValidationError) { return nil, nil }`, 2 + 2},
+		{"receiver is not counted", `func (s *S) Name() string { return "" }`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			funcDecl := parseFunc(t, tt.src)
+			if got := surfaceComplexity(funcDecl, extractResultTypes(funcDecl)); got != tt.want {
+				t.Errorf("surfaceComplexity = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectAwkwardAPI(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"app/app.go": `package app
+
+import "context"
+
+type User struct{}
+
+func Get(ctx context.Context, id string) (User, error) { return User{}, nil }
+
+func Sync(a, b, c string, d, e int, f bool) (int, int, error) { return 0, 0, nil }
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "app")
+	if got := findFunction(t, pkg, "Get").SurfaceComplexity; got != 4 {
+		t.Errorf("Get surface complexity = %d, want 4", got)
+	}
+	if got := findFunction(t, pkg, "Sync").SurfaceComplexity; got != 9 {
+		t.Errorf("Sync surface complexity = %d, want 9", got)
+	}
+
+	awkward := diagnosticsOfType(report, DiagnosticAwkwardAPI)
+	if len(awkward) != 1 || awkward[0].TargetName != "app.Sync" {
+		t.Errorf("got %+v, want one Awkward API diagnostic for app.Sync", awkward)
+	}
+}
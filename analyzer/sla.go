@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLA statuses (DiagnosticResult.SLAStatus)
+const (
+	SLAWithin  = "within"  // The diagnostic is younger than its severity's SLA
+	SLAOverdue = "overdue" // The diagnostic is older than its severity's SLA
+	SLAUnknown = "unknown" // No file age is available (e.g. package-level diagnostics)
+)
+
+// annotateSLA sets AgeDays, DueDate, and SLAStatus on diagnostics whose severity has an SLA.
+// A diagnostic's age is the age of its oldest source file, taken from the file's last git
+// commit, or its modification time when git is unavailable or the file is untracked.
+func annotateSLA(diagnostics []DiagnosticResult, slaDays map[string]int, now time.Time) {
+	if len(slaDays) == 0 {
+		return
+	}
+
+	ages := newFileAgeCache()
+
+	for i := range diagnostics {
+		d := &diagnostics[i]
+		days, ok := slaDays[d.Severity]
+		if !ok {
+			continue
+		}
+
+		var oldest time.Time
+		if d.Evidence != nil {
+			for _, file := range d.Evidence.SourceFiles() {
+				if modified, ok := ages.lastModified(file); ok && (oldest.IsZero() || modified.Before(oldest)) {
+					oldest = modified
+				}
+			}
+		}
+
+		if oldest.IsZero() {
+			d.SLAStatus = SLAUnknown
+			continue
+		}
+
+		due := oldest.AddDate(0, 0, days)
+		d.AgeDays = int(now.Sub(oldest).Hours() / 24)
+		d.DueDate = due.Format("2006-01-02")
+		if now.After(due) {
+			d.SLAStatus = SLAOverdue
+		} else {
+			d.SLAStatus = SLAWithin
+		}
+	}
+}
+
+// fileAgeCache caches last-modified times per file
+type fileAgeCache struct {
+	times  map[string]time.Time
+	useGit bool
+}
+
+// newFileAgeCache creates a cache, using git only if it is installed
+func newFileAgeCache() *fileAgeCache {
+	_, err := exec.LookPath("git")
+	return &fileAgeCache{
+		times:  make(map[string]time.Time),
+		useGit: err == nil,
+	}
+}
+
+// lastModified returns when a file was last changed
+func (c *fileAgeCache) lastModified(file string) (time.Time, bool) {
+	if t, ok := c.times[file]; ok {
+		return t, !t.IsZero()
+	}
+
+	var t time.Time
+	if c.useGit {
+		t = gitLastCommitTime(file)
+	}
+	if t.IsZero() {
+		if info, err := os.Stat(file); err == nil {
+			t = info.ModTime()
+		}
+	}
+
+	c.times[file] = t
+	return t, !t.IsZero()
+}
+
+// gitLastCommitTime returns the time of the last commit touching a file, or zero if unknown
+func gitLastCommitTime(file string) time.Time {
+	cmd := exec.Command("git", "-C", filepath.Dir(file), "log", "-1", "--format=%ct", "--", filepath.Base(file))
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}
+	}
+
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
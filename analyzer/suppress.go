@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+	"unicode"
+)
+
+// Source directives suppressing diagnostics for one declaration. They go in the doc comment of a
+// struct type or function:
+//
+//	//codehealth:ignore God Object, large-struct
+//	//codehealth:ignore-all
+const (
+	ignoreDirective    = "//codehealth:ignore"
+	ignoreAllDirective = "//codehealth:ignore-all"
+)
+
+// ignoreFileDirective in a comment above the package clause suppresses every diagnostic of the file
+const ignoreFileDirective = "//codehealth:ignore-file"
+
+// suppressAll marks a declaration whose diagnostics are all suppressed
+const suppressAll = "*"
+
+// parseIgnoreDirectives returns the diagnostic types suppressed by the directives in a doc comment,
+// as written, or suppressAll. Types are comma-separated and compared ignoring case, spaces, hyphens,
+// and underscores, so "God Object", "god-object", and "GodObject" are the same type.
+// A directive without types suppresses everything, like ignore-all.
+func parseIgnoreDirectives(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+
+	var suppressed []string
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(comment.Text)
+		if text == ignoreAllDirective {
+			suppressed = append(suppressed, suppressAll)
+			continue
+		}
+
+		rest, found := strings.CutPrefix(text, ignoreDirective)
+		if !found || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		if strings.TrimSpace(rest) == "" {
+			suppressed = append(suppressed, suppressAll)
+			continue
+		}
+		for _, diagnosticType := range strings.Split(rest, ",") {
+			if diagnosticType = strings.TrimSpace(diagnosticType); diagnosticType != "" {
+				suppressed = append(suppressed, diagnosticType)
+			}
+		}
+	}
+	return suppressed
+}
+
+// normalizeDiagnosticType reduces a diagnostic type to lowercase letters and digits
+func normalizeDiagnosticType(diagnosticType string) string {
+	var b strings.Builder
+	for _, r := range diagnosticType {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// suppression is the set of diagnostic types ignored for one declaration
+type suppression struct {
+	targetName string
+	filePath   string
+	types      map[string]bool // Normalized types, or suppressAll
+}
+
+// matches reports whether a diagnostic of the given type is suppressed
+func (s suppression) matches(diagnosticType string) bool {
+	return s.types[suppressAll] || s.types[normalizeDiagnosticType(diagnosticType)]
+}
+
+// filterSuppressedDiagnostics drops diagnostics suppressed by //codehealth:ignore directives.
+// A diagnostic belongs to a declaration when it links to the declaration's report entry, or when
+// it names the declaration as its target in the same file (a method diagnosis linking to its struct).
+func filterSuppressedDiagnostics(diagnostics []DiagnosticResult, packages []PackageResult) []DiagnosticResult {
+	byPath := make(map[string]suppression)
+	for _, pkg := range packages {
+		for _, s := range pkg.Structs {
+			if len(s.Suppressed) > 0 {
+				byPath[fmt.Sprintf("#struct-%s-%s", pkg.Path, s.StructName)] = newSuppression(pkg.Name+"."+s.StructName, s.FilePath, s.Suppressed)
+			}
+		}
+		for _, f := range pkg.Functions {
+			if len(f.Suppressed) > 0 {
+				byPath[fmt.Sprintf("#function-%s-%s", pkg.Path, f.FuncName)] = newSuppression(pkg.Name+"."+f.FuncName, f.FilePath, f.Suppressed)
+			}
+		}
+	}
+	if len(byPath) == 0 {
+		return diagnostics
+	}
+
+	byTarget := make(map[string][]suppression)
+	for _, s := range byPath {
+		byTarget[s.targetName] = append(byTarget[s.targetName], s)
+	}
+
+	var kept []DiagnosticResult
+	for _, d := range diagnostics {
+		if !isSuppressedDiagnostic(d, byPath, byTarget) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// newSuppression builds the suppression of a declaration from its directive types
+func newSuppression(targetName string, filePath string, suppressed []string) suppression {
+	types := make(map[string]bool)
+	for _, diagnosticType := range suppressed {
+		if diagnosticType == suppressAll {
+			types[suppressAll] = true
+			continue
+		}
+		types[normalizeDiagnosticType(diagnosticType)] = true
+	}
+	return suppression{targetName: targetName, filePath: filePath, types: types}
+}
+
+// isSuppressedDiagnostic reports whether a directive on the diagnosed declaration suppresses the diagnostic
+func isSuppressedDiagnostic(d DiagnosticResult, byPath map[string]suppression, byTarget map[string][]suppression) bool {
+	if s, ok := byPath[d.RelatedPath]; ok && s.matches(d.Type) {
+		return true
+	}
+
+	for _, s := range byTarget[d.TargetName] {
+		if !s.matches(d.Type) || d.Evidence == nil {
+			continue
+		}
+		for _, filePath := range d.Evidence.SourceFiles() {
+			if filePath == s.filePath {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasIgnoreFileDirective reports whether a comment above the package clause is an ignore-file directive
+func hasIgnoreFileDirective(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if strings.TrimSpace(comment.Text) == ignoreFileDirective {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// markIgnoredFiles flags the files carrying an ignore-file directive
+func markIgnoredFiles(files []FileResult, pkg *ast.Package) {
+	for i := range files {
+		if file, ok := pkg.Files[files[i].Path]; ok {
+			files[i].Ignored = hasIgnoreFileDirective(file)
+		}
+	}
+}
+
+// filterIgnoredScopes drops diagnostics of files with an ignore-file directive and of packages
+// matching the ignore_packages patterns. Like the ignore file, a diagnostic is dropped only if
+// every file it refers to is ignored; a package-level diagnostic is dropped with its package.
+func filterIgnoredScopes(diagnostics []DiagnosticResult, packages []PackageResult, patterns []string) []DiagnosticResult {
+	ignoredFiles := make(map[string]bool)
+	ignoredPackages := make(map[string]bool)
+	for _, pkg := range packages {
+		ignorePackage := matchesAnyPackagePattern(patterns, pkg.Path)
+		if ignorePackage {
+			ignoredPackages[fmt.Sprintf("#package-%s", pkg.Path)] = true
+		}
+		for _, file := range pkg.Files {
+			if ignorePackage || file.Ignored {
+				ignoredFiles[file.Path] = true
+			}
+		}
+	}
+	if len(ignoredFiles) == 0 && len(ignoredPackages) == 0 {
+		return diagnostics
+	}
+
+	var kept []DiagnosticResult
+	for _, d := range diagnostics {
+		if !ignoredPackages[d.RelatedPath] && !allFilesIgnored(d, ignoredFiles) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// matchesAnyPackagePattern reports whether a package path matches one of the patterns
+func matchesAnyPackagePattern(patterns []string, pkgPath string) bool {
+	for _, pattern := range patterns {
+		if matchPathPattern(strings.Trim(pattern, "/"), pkgPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// allFilesIgnored reports whether a diagnostic refers to files and all of them are ignored
+func allFilesIgnored(d DiagnosticResult, ignoredFiles map[string]bool) bool {
+	if d.Evidence == nil {
+		return false
+	}
+
+	files := d.Evidence.SourceFiles()
+	if len(files) == 0 {
+		return false
+	}
+	for _, filePath := range files {
+		if !ignoredFiles[filePath] {
+			return false
+		}
+	}
+	return true
+}
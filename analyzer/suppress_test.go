@@ -0,0 +1,123 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// twoResponsibilities is a struct body with two unrelated halves (LCOM4 = 2), completed by a type name
+const twoResponsibilities = ` struct {
+	name  string
+	count int
+}
+`
+
+// godObjectConfig reports every struct with LCOM4 >= 2 in an imported package as a God Object
+func godObjectConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.GodObjectLCOM4 = 2
+	cfg.GodObjectAfferent = 1
+	return cfg
+}
+
+// godObjectImporter imports the packages holding the structs, giving them a Ca of 1
+var godObjectImporter = map[string]string{
+	"cmd/main.go": "package main\n\nimport (\n\t_ \"example.com/app/app\"\n\t_ \"example.com/app/legacy/old\"\n)\n\nfunc main() {}\n",
+}
+
+func TestIgnoreDirectiveSuppressesDiagnostic(t *testing.T) {
+	src := "package app\n\n"
+	for _, decl := range []struct{ doc, name string }{
+		{"", "Flagged"},
+		{"//codehealth:ignore God Object\n", "Ignored"},
+		{"// Quiet has a regular doc comment too.\n//codehealth:ignore-all\n", "Quiet"},
+		{"//codehealth:ignore long-function\n", "OtherType"},
+	} {
+		src += decl.doc + "type " + decl.name + twoResponsibilities + "\n" +
+			"func (x *" + decl.name + ") Name() string { return x.name }\n\n" +
+			"func (x *" + decl.name + ") Count() int { return x.count }\n\n"
+	}
+	report := analyzeFixture(t, mergeFiles(godObjectImporter, map[string]string{"app/app.go": src}), godObjectConfig())
+
+	var targets []string
+	for _, d := range diagnosticsOfType(report, DiagnosticGodObject) {
+		targets = append(targets, d.TargetName)
+	}
+	sort.Strings(targets)
+	if want := []string{"app.Flagged", "app.OtherType"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("God Object targets = %v, want %v", targets, want)
+	}
+
+	pkg := findPackage(t, report, "app")
+	if got := findStruct(t, pkg, "Ignored").Suppressed; !reflect.DeepEqual(got, []string{"God Object"}) {
+		t.Errorf("Ignored.Suppressed = %v, want [God Object]", got)
+	}
+	if got := findStruct(t, pkg, "Quiet").Suppressed; !reflect.DeepEqual(got, []string{suppressAll}) {
+		t.Errorf("Quiet.Suppressed = %v, want [%s]", got, suppressAll)
+	}
+}
+
+func TestParseIgnoreDirectives(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{"types", "//codehealth:ignore God Object, large-struct\nfunc f() {}", []string{"God Object", "large-struct"}},
+		{"all", "//codehealth:ignore-all\nfunc f() {}", []string{suppressAll}},
+		{"bare directive", "//codehealth:ignore\nfunc f() {}", []string{suppressAll}},
+		{"other directive", "//codehealth:ignored\nfunc f() {}", nil},
+		{"no doc", "func f() {}", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseIgnoreDirectives(parseFunc(t, tt.src).Doc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if normalizeDiagnosticType("God Object") != normalizeDiagnosticType("god_object") {
+		t.Errorf("type names differing in case and separators should match")
+	}
+}
+
+func TestIgnoreFileAndPackages(t *testing.T) {
+	godObject := func(name string) string {
+		return "type " + name + twoResponsibilities + "\n" +
+			"func (x *" + name + ") Name() string { return x.name }\n\n" +
+			"func (x *" + name + ") Count() int { return x.count }\n"
+	}
+	cfg := godObjectConfig()
+	cfg.IgnorePackages = []string{"legacy/**"}
+
+	report := analyzeFixture(t, mergeFiles(godObjectImporter, map[string]string{
+		"app/kept.go":         "package app\n\n" + godObject("Kept"),
+		"app/migrating.go":    "// Legacy code being migrated.\n//codehealth:ignore-file\n\npackage app\n\n" + godObject("Skipped"),
+		"legacy/old/store.go": "package old\n\n" + godObject("Store"),
+	}), cfg)
+
+	var targets []string
+	for _, d := range diagnosticsOfType(report, DiagnosticGodObject) {
+		targets = append(targets, d.TargetName)
+	}
+	if want := []string{"app.Kept"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("God Object targets = %v, want %v", targets, want)
+	}
+
+	// Ignored code is still measured
+	findStruct(t, findPackage(t, report, "app"), "Skipped")
+	findStruct(t, findPackage(t, report, "legacy/old"), "Store")
+
+	var ignored []string
+	for _, file := range findPackage(t, report, "app").Files {
+		if file.Ignored {
+			ignored = append(ignored, file.Path)
+		}
+	}
+	if len(ignored) != 1 || filepath.Base(ignored[0]) != "migrating.go" {
+		t.Errorf("ignored files = %v, want migrating.go", ignored)
+	}
+}
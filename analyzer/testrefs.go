@@ -0,0 +1,275 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// MarkTestedFunctions sets HasTestReference on every function that a test file references
+// directly, or that is reachable from such a function through the package's call graph.
+// Matching is name-based: a function F matches any use of the identifier F, and a method
+// T.M matches any use of M, since test files are not type-checked.
+func MarkTestedFunctions(functions []FunctionResult, pkg *ast.Package, testFiles map[string]*ast.File) {
+	if len(testFiles) == 0 {
+		return
+	}
+
+	// Index functions by their simple (unqualified) name
+	bySimpleName := make(map[string][]int)
+	for i, f := range functions {
+		bySimpleName[simpleFuncName(f.FuncName)] = append(bySimpleName[simpleFuncName(f.FuncName)], i)
+	}
+
+	// Collect identifiers referenced from each function body (the name-based call graph)
+	references := make(map[string]map[string]bool)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			references[qualifiedFuncName(funcDecl)] = collectIdentNames(funcDecl.Body)
+		}
+	}
+
+	// Seed with functions referenced from test files
+	var queue []int
+	visited := make(map[int]bool)
+	for _, file := range testFiles {
+		for name := range collectIdentNames(file) {
+			for _, idx := range bySimpleName[name] {
+				if !visited[idx] {
+					visited[idx] = true
+					queue = append(queue, idx)
+				}
+			}
+		}
+	}
+
+	// Propagate through calls made by tested functions
+	for len(queue) > 0 {
+		idx := queue[0]
+		queue = queue[1:]
+		functions[idx].HasTestReference = true
+
+		for name := range references[functions[idx].FuncName] {
+			for _, callee := range bySimpleName[name] {
+				if !visited[callee] {
+					visited[callee] = true
+					queue = append(queue, callee)
+				}
+			}
+		}
+	}
+}
+
+// importedFuncRef is a reference to a function of another project package: a top-level
+// function (pkg.F) or, by method name alone, any method of the package (x.M)
+type importedFuncRef struct {
+	pkgPath string
+	name    string
+	method  bool
+}
+
+// markCrossPackageTestReferences extends HasTestReference across packages. Test files anywhere in
+// the project (including external foo_test packages) refer to other packages through their imports,
+// and a tested function's calls into other packages reach those packages' functions too. Imported
+// references are resolved by import path; within a package they spread through the name-based
+// call graph as in MarkTestedFunctions. Functions MarkTestedFunctions already marked are the
+// starting points besides the test files.
+func markCrossPackageTestReferences(packageResults []PackageResult, packages map[string]*ParsedPackage, modules projectModules) {
+	type funcRefs struct {
+		local    map[string]bool
+		imported []importedFuncRef
+	}
+	type funcID struct {
+		pkg int
+		fn  int
+	}
+
+	resultIndex := make(map[string]int, len(packageResults))
+	for i, pkg := range packageResults {
+		resultIndex[pkg.Path] = i
+	}
+
+	// Index each package's functions by simple name, and collect the references of their bodies
+	bySimpleName := make([]map[string][]int, len(packageResults))
+	references := make([]map[string]funcRefs, len(packageResults))
+	var testFiles []*ast.File
+	testFileOwners := make(map[*ast.File]string)
+	for i, pkg := range packageResults {
+		bySimpleName[i] = make(map[string][]int)
+		for j, f := range pkg.Functions {
+			bySimpleName[i][simpleFuncName(f.FuncName)] = append(bySimpleName[i][simpleFuncName(f.FuncName)], j)
+		}
+
+		references[i] = make(map[string]funcRefs)
+		parsed := packages[pkg.Path]
+		if parsed == nil {
+			continue
+		}
+		for fileName, file := range parsed.Package.Files {
+			if isTestFile(fileName) {
+				testFiles = append(testFiles, file)
+				testFileOwners[file] = pkg.Path
+			}
+			projectImports := projectImportNames(file, pkg.Path, packages, modules)
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Body == nil {
+					continue
+				}
+				references[i][qualifiedFuncName(funcDecl)] = funcRefs{
+					local:    collectIdentNames(funcDecl.Body),
+					imported: importedFuncRefs(funcDecl.Body, projectImports),
+				}
+			}
+		}
+		for _, file := range parsed.TestFiles {
+			if _, ok := testFileOwners[file]; !ok {
+				testFiles = append(testFiles, file)
+				testFileOwners[file] = pkg.Path
+			}
+		}
+	}
+
+	var queue []funcID
+	visited := make(map[funcID]bool)
+	visit := func(id funcID) {
+		if !visited[id] {
+			visited[id] = true
+			queue = append(queue, id)
+		}
+	}
+	resolve := func(ref importedFuncRef) {
+		i, ok := resultIndex[ref.pkgPath]
+		if !ok {
+			return
+		}
+		for _, j := range bySimpleName[i][ref.name] {
+			if isMethod := strings.Contains(packageResults[i].Functions[j].FuncName, "."); isMethod == ref.method {
+				visit(funcID{i, j})
+			}
+		}
+	}
+
+	// Seed with functions already marked and with the imported references of every test file
+	for i, pkg := range packageResults {
+		for j, f := range pkg.Functions {
+			if f.HasTestReference {
+				visit(funcID{i, j})
+			}
+		}
+	}
+	for _, file := range testFiles {
+		for _, ref := range importedFuncRefs(file, projectImportNames(file, testFileOwners[file], packages, modules)) {
+			resolve(ref)
+		}
+	}
+
+	// Propagate through calls within each package and into imported packages
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		f := &packageResults[id.pkg].Functions[id.fn]
+		f.HasTestReference = true
+
+		refs := references[id.pkg][f.FuncName]
+		for name := range refs.local {
+			for _, callee := range bySimpleName[id.pkg][name] {
+				visit(funcID{id.pkg, callee})
+			}
+		}
+		for _, ref := range refs.imported {
+			resolve(ref)
+		}
+	}
+}
+
+// importedFuncRefs returns the references a node makes to functions of imported project packages:
+// pkg.F for each imported package, and x.M as a possible call of method M of any of them
+func importedFuncRefs(node ast.Node, projectImports map[string]string) []importedFuncRef {
+	if len(projectImports) == 0 {
+		return nil
+	}
+
+	seen := make(map[importedFuncRef]bool)
+	var refs []importedFuncRef
+	add := func(ref importedFuncRef) {
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		selector, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok && ident.Obj == nil {
+			if importedPath, ok := projectImports[ident.Name]; ok {
+				add(importedFuncRef{pkgPath: importedPath, name: selector.Sel.Name})
+				return true
+			}
+		}
+		for _, importedPath := range projectImports {
+			add(importedFuncRef{pkgPath: importedPath, name: selector.Sel.Name, method: true})
+		}
+		return true
+	})
+	return refs
+}
+
+// collectIdentNames collects the names of all identifiers under a node
+func collectIdentNames(node ast.Node) map[string]bool {
+	names := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			names[ident.Name] = true
+		}
+		return true
+	})
+	return names
+}
+
+// qualifiedFuncName returns the function name as used in FunctionResult ("Type.Method" for methods)
+func qualifiedFuncName(funcDecl *ast.FuncDecl) string {
+	funcName := funcDecl.Name.Name
+	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+		recvTypeName := receiverTypeName(funcDecl.Recv.List[0].Type)
+		if recvTypeName != "" {
+			funcName = recvTypeName + "." + funcName
+		}
+	}
+	return funcName
+}
+
+// receiverTypeName returns the base type name of a receiver (or any type) expression,
+// unwrapping pointers and generic instantiations: T, *T, T[K], and *T[K, V] all yield "T".
+// It returns "" for other expressions.
+func receiverTypeName(expr ast.Expr) string {
+	for {
+		switch t := expr.(type) {
+		case *ast.Ident:
+			return t.Name
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		default:
+			return ""
+		}
+	}
+}
+
+// simpleFuncName strips the receiver type from a qualified function name
+func simpleFuncName(funcName string) string {
+	if idx := strings.LastIndex(funcName, "."); idx >= 0 {
+		return funcName[idx+1:]
+	}
+	return funcName
+}
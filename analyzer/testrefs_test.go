@@ -0,0 +1,89 @@
+package analyzer
+
+import "testing"
+
+func TestTestReferencesAcrossPackages(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"util/util.go": `package util
+
+func Parse(s string) int { return helper(s) }
+
+func helper(s string) int { return len(s) }
+
+func Untested() int { return 1 }
+
+type Client struct{}
+
+func (c *Client) Send() error { return nil }
+
+func (c *Client) Close() error { return nil }
+
+func New() *Client { return &Client{} }
+`,
+		"svc/svc.go": `package svc
+
+func Run() {}
+`,
+		"svc/svc_test.go": `package svc
+
+import (
+	"testing"
+
+	"example.com/app/util"
+)
+
+func TestParse(t *testing.T) {
+	c := util.New()
+	c.Send()
+	if util.Parse("x") != 1 {
+		t.Fail()
+	}
+}
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "util")
+	for name, want := range map[string]bool{
+		"Parse":        true, // called from another package's test
+		"helper":       true, // called by a tested function
+		"New":          true,
+		"Client.Send":  true, // method called on a value of the imported package
+		"Untested":     false,
+		"Client.Close": false,
+	} {
+		if got := findFunction(t, pkg, name).HasTestReference; got != want {
+			t.Errorf("%s: HasTestReference = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestTestReferencesFromExternalTestPackage(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"util/util.go": `package util
+
+func Parse(s string) int { return len(s) }
+
+func Untested() int { return 1 }
+`,
+		"util/util_test.go": `package util_test
+
+import (
+	"testing"
+
+	"example.com/app/util"
+)
+
+func TestParse(t *testing.T) {
+	util.Parse("x")
+}
+`,
+	}, nil)
+
+	pkg := findPackage(t, report, "util")
+	if !findFunction(t, pkg, "Parse").HasTestReference {
+		t.Error("Parse is tested from util_test but not marked")
+	}
+	if findFunction(t, pkg, "Untested").HasTestReference {
+		t.Error("Untested is marked as tested")
+	}
+}
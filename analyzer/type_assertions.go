@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// findTypeAssertionCascade finds the expression a function type-asserts to the most distinct
+// concrete types, e.g. `if a, ok := v.(A); ok {} else if b, ok := v.(B); ok {}`.
+// Type switches (`v.(type)`) are not counted since they are the idiomatic form.
+// It returns the asserted expression and its sorted target types, or ("", nil) if no
+// expression is asserted to two or more types.
+func findTypeAssertionCascade(funcDecl *ast.FuncDecl) (string, []string) {
+	if funcDecl.Body == nil {
+		return "", nil
+	}
+
+	// Asserted expression -> set of target types
+	assertions := make(map[string]map[string]bool)
+
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		assert, ok := n.(*ast.TypeAssertExpr)
+		if !ok || assert.Type == nil {
+			return true
+		}
+
+		subject := types.ExprString(assert.X)
+		if assertions[subject] == nil {
+			assertions[subject] = make(map[string]bool)
+		}
+		assertions[subject][types.ExprString(assert.Type)] = true
+		return true
+	})
+
+	var bestSubject string
+	var bestTypes []string
+	for subject, targets := range assertions {
+		if len(targets) < 2 {
+			continue
+		}
+		if len(targets) < len(bestTypes) || (len(targets) == len(bestTypes) && subject > bestSubject) {
+			continue
+		}
+
+		bestSubject = subject
+		bestTypes = make([]string, 0, len(targets))
+		for t := range targets {
+			bestTypes = append(bestTypes, t)
+		}
+		sort.Strings(bestTypes)
+	}
+
+	return bestSubject, bestTypes
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// typeCheckPackages type-checks each package, returning type information keyed by package path.
+// Project packages are checked from the already parsed files; other imports are type-checked
+// from source and resolved from the working directory, so run the analyzer inside the module
+// when the project has third-party dependencies. Packages that fail to type-check (including
+// packages importing one that failed) are left out and keep the AST-only results.
+func typeCheckPackages(packages map[string]*ParsedPackage, modules projectModules) map[string]*types.Info {
+	imp := &projectImporter{
+		packages: packages,
+		modules:  modules,
+		fallback: importer.ForCompiler(token.NewFileSet(), "source", nil),
+		checked:  make(map[string]*types.Package),
+		infos:    make(map[string]*types.Info),
+	}
+
+	pkgPaths := make([]string, 0, len(packages))
+	for pkgPath := range packages {
+		pkgPaths = append(pkgPaths, pkgPath)
+	}
+	sort.Strings(pkgPaths)
+
+	for _, pkgPath := range pkgPaths {
+		imp.check(pkgPath)
+	}
+
+	return imp.infos
+}
+
+// projectImporter type-checks project packages on demand, so a package importing another
+// project package sees the same types the importee was checked with
+type projectImporter struct {
+	packages map[string]*ParsedPackage
+	modules  projectModules
+	fallback types.Importer
+	checked  map[string]*types.Package // nil entry: failed or being checked
+	infos    map[string]*types.Info
+}
+
+func (p *projectImporter) Import(path string) (*types.Package, error) {
+	if pkgPath, ok := p.modules.packagePath(path); ok {
+		if _, exists := p.packages[pkgPath]; exists {
+			if pkg := p.check(pkgPath); pkg != nil {
+				return pkg, nil
+			}
+			return nil, fmt.Errorf("package %s could not be type-checked", path)
+		}
+	}
+	return p.fallback.Import(path)
+}
+
+// check type-checks a project package once, returning nil if it (or one of its imports) fails
+func (p *projectImporter) check(pkgPath string) *types.Package {
+	if pkg, done := p.checked[pkgPath]; done {
+		return pkg
+	}
+	// Mark as in progress so an import cycle fails instead of recursing forever
+	p.checked[pkgPath] = nil
+
+	// Generated files are not measured but are part of the package
+	parsed := p.packages[pkgPath]
+	allFiles := make(map[string]*ast.File, len(parsed.Package.Files)+len(parsed.GeneratedFiles))
+	for fileName, file := range parsed.Package.Files {
+		allFiles[fileName] = file
+	}
+	for fileName, file := range parsed.GeneratedFiles {
+		allFiles[fileName] = file
+	}
+
+	fileNames := make([]string, 0, len(allFiles))
+	for fileName := range allFiles {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	files := make([]*ast.File, len(fileNames))
+	for i, fileName := range fileNames {
+		files[i] = allFiles[fileName]
+	}
+
+	info := &types.Info{
+		Uses: make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{Importer: p}
+	pkg, err := conf.Check(p.modules.importPath(pkgPath), parsed.FileSet, files, info)
+	if err != nil {
+		return nil
+	}
+
+	p.checked[pkgPath] = pkg
+	p.infos[pkgPath] = info
+	return pkg
+}
+
+// applyTypedAfferentCoupling recomputes function afferent coupling (Ca) for type-checked packages.
+// With type information, calls are resolved to their real targets: method calls through
+// variables and calls from other project packages are counted, which the AST-only matching misses.
+// Calls from packages that failed to type-check keep their AST-derived counts.
+func applyTypedAfferentCoupling(packageResults []PackageResult, packages map[string]*ParsedPackage, infos map[string]*types.Info, modules projectModules) {
+	// Index functions of type-checked packages by package path and name
+	funcIndex := make(map[string]*FunctionResult)
+	for i := range packageResults {
+		pkg := &packageResults[i]
+		if infos[pkg.Path] == nil {
+			continue
+		}
+		pkg.TypeChecked = true
+		for j := range pkg.Functions {
+			pkg.Functions[j].Afferent = 0
+			funcIndex[pkg.Path+"|"+pkg.Functions[j].FuncName] = &pkg.Functions[j]
+		}
+	}
+
+	// Callers without type information fall back to the AST matching
+	for pkgPath := range packages {
+		if infos[pkgPath] != nil {
+			continue
+		}
+		forEachCrossPackageCall(pkgPath, packages, modules, func(key string) {
+			if calledFunc, exists := funcIndex[key]; exists {
+				calledFunc.Afferent++
+			}
+		})
+	}
+
+	for pkgPath, info := range infos {
+		for _, file := range packages[pkgPath].Package.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				key := calleeKey(call, info, modules)
+				if calledFunc, exists := funcIndex[key]; exists {
+					calledFunc.Afferent++
+				}
+				return true
+			})
+		}
+	}
+
+	for i := range packageResults {
+		if !packageResults[i].TypeChecked {
+			continue
+		}
+		for j := range packageResults[i].Functions {
+			f := &packageResults[i].Functions[j]
+			f.Instability = 0
+			if total := f.Afferent + f.Efferent; total > 0 {
+				f.Instability = float64(f.Efferent) / float64(total)
+			}
+		}
+	}
+}
+
+// calleeKey returns "pkgPath|FuncName" (FuncName is "Type.Method" for methods) for a call
+// to a function declared in the project, or "" otherwise
+func calleeKey(call *ast.CallExpr, info *types.Info, modules projectModules) string {
+	fun := ast.Unparen(call.Fun)
+
+	// Explicit instantiation of a generic function: F[int](...)
+	switch index := fun.(type) {
+	case *ast.IndexExpr:
+		fun = index.X
+	case *ast.IndexListExpr:
+		fun = index.X
+	}
+
+	var ident *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return ""
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return ""
+	}
+
+	pkgPath, ok := modules.packagePath(fn.Pkg().Path())
+	if !ok {
+		return ""
+	}
+
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		recvType := recv.Type()
+		if ptr, ok := recvType.(*types.Pointer); ok {
+			recvType = ptr.Elem()
+		}
+		named, ok := recvType.(*types.Named)
+		if !ok {
+			// Interface methods have no declaration to attribute the call to
+			return ""
+		}
+		name = named.Obj().Name() + "." + name
+	}
+
+	return pkgPath + "|" + name
+}
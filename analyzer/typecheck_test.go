@@ -0,0 +1,90 @@
+package analyzer
+
+import "testing"
+
+// typeCheckFixture calls a method of another package through a variable, which only type
+// information can resolve
+var typeCheckFixture = map[string]string{
+	"util/util.go": `package util
+
+type Client struct{ sent int }
+
+func New() *Client { return &Client{} }
+
+func (c *Client) Send() { c.sent++ }
+`,
+	"svc/svc.go": `package svc
+
+import "example.com/app/util"
+
+func Run() {
+	c := util.New()
+	c.Send()
+}
+`,
+}
+
+func TestTypeCheckResolvesCrossPackageMethodCalls(t *testing.T) {
+	astOnly := analyzeFixture(t, typeCheckFixture, nil)
+	astUtil := findPackage(t, astOnly, "util")
+	if astUtil.TypeChecked {
+		t.Errorf("package type-checked without TypeCheck")
+	}
+	if got := findFunction(t, astUtil, "Client.Send").Afferent; got != 0 {
+		t.Errorf("AST-only Client.Send afferent = %d, want 0 (the call through a variable is not resolved)", got)
+	}
+	if got := findFunction(t, astUtil, "New").Afferent; got != 1 {
+		t.Errorf("AST-only New afferent = %d, want 1", got)
+	}
+
+	cfg := DefaultConfig()
+	cfg.TypeCheck = true
+	typed := analyzeFixture(t, typeCheckFixture, cfg)
+	typedUtil := findPackage(t, typed, "util")
+	if !typedUtil.TypeChecked {
+		t.Fatalf("package util was not type-checked")
+	}
+	if got := findFunction(t, typedUtil, "Client.Send").Afferent; got != 1 {
+		t.Errorf("type-checked Client.Send afferent = %d, want 1", got)
+	}
+	if got := findFunction(t, typedUtil, "New").Afferent; got != 1 {
+		t.Errorf("type-checked New afferent = %d, want 1", got)
+	}
+}
+
+func TestTypeCheckFallsBackToAST(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TypeCheck = true
+	report := analyzeFixture(t, map[string]string{
+		"util/util.go": "package util\n\nfunc New() int { return undefinedName }\n",
+		"svc/svc.go":   "package svc\n\nimport \"example.com/app/util\"\n\nfunc Run() int { return util.New() }\n",
+	}, cfg)
+
+	util := findPackage(t, report, "util")
+	if util.TypeChecked {
+		t.Errorf("package with a type error reported as type-checked")
+	}
+	if got := findFunction(t, util, "New").Afferent; got != 1 {
+		t.Errorf("fallback New afferent = %d, want 1 from the AST path", got)
+	}
+}
+
+func TestTypeCheckKeepsCallsFromUncheckedPackages(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TypeCheck = true
+	report := analyzeFixture(t, map[string]string{
+		"util/util.go": "package util\n\nfunc New() int { return 1 }\n",
+		"svc/svc.go":   "package svc\n\nimport \"example.com/app/util\"\n\nfunc Run() int { return util.New() + undefinedName }\n",
+	}, cfg)
+
+	if findPackage(t, report, "svc").TypeChecked {
+		t.Errorf("package with a type error reported as type-checked")
+	}
+	util := findPackage(t, report, "util")
+	if !util.TypeChecked {
+		t.Fatalf("package util was not type-checked")
+	}
+	if got := findFunction(t, util, "New").Afferent; got != 1 {
+		t.Errorf("New afferent = %d, want 1 from the unchecked caller", got)
+	}
+}
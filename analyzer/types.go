@@ -1,87 +1,284 @@
 package analyzer
 
+import "time"
+
 // Report represents the complete analysis report
 type Report struct {
-	Diagnostics []DiagnosticResult `json:"diagnostics"` // Integrated analysis results
-	Packages    []PackageResult    `json:"packages"`
-	TotalLoC    int                `json:"total_loc"` // Total lines of code in the project
+	SchemaVersion   string             `json:"schema_version"`   // JSON format version (see SchemaVersion); empty in reports predating versioning
+	GeneratedAt     time.Time          `json:"generated_at"`     // When the analysis ran
+	AnalyzerVersion string             `json:"analyzer_version"` // Version of the analyzer that wrote the report
+	TargetPath      string             `json:"target_path"`      // Absolute path of the analyzed directory
+	Diagnostics     []DiagnosticResult `json:"diagnostics"`      // Integrated analysis results
+	Packages        []PackageResult    `json:"packages"`
+	TotalLoC        int                `json:"total_loc"`               // Total lines of code in the project, counted per LoCMode
+	LoCMode         string             `json:"loc_mode,omitempty"`      // How total_loc and avg_func_loc are counted (see Config.LoCMode); empty in older reports
+	HealthScore     float64            `json:"health_score"`            // Project health score from 0 to 100 (see score.go)
+	TopOffenders    []Offender         `json:"top_offenders,omitempty"` // Worst functions, structs, and critical diagnostics, ranked (see offenders.go)
+	Hotspots        []Hotspot          `json:"hotspots,omitempty"`      // Functions ranked by complexity × churn (only with churn enabled, see churn.go)
+}
+
+// Hotspot is a function that is both complex and frequently changed
+type Hotspot struct {
+	Name        string `json:"name"`         // "package.Function"
+	Package     string `json:"package"`      // Package path
+	Complexity  int    `json:"complexity"`   // Cyclomatic complexity
+	Churn       int    `json:"churn"`        // Commits touching the function's file within the churn window
+	Score       int    `json:"score"`        // Complexity × Churn
+	FilePath    string `json:"file_path"`    // Source file
+	Line        int    `json:"line"`         // Line of the declaration
+	RelatedPath string `json:"related_path"` // Link to detailed data, as in DiagnosticResult
+}
+
+// Offender is one entry of the ranked list of the project's worst spots
+type Offender struct {
+	Kind        string  `json:"kind"`                // "function", "struct", or "diagnostic"
+	Name        string  `json:"name"`                // "package.Function", "package.Struct", or the diagnostic's target
+	Package     string  `json:"package,omitempty"`   // Package path (empty for diagnostics)
+	Reason      string  `json:"reason"`              // Metric value ("Complexity 23", "LCOM4 6") or diagnostic type
+	Score       float64 `json:"score"`               // Weight × metric / threshold (see rankOffenders); 1 is a metric at its threshold
+	FilePath    string  `json:"file_path,omitempty"` // Source file (empty for package-level diagnostics)
+	Line        int     `json:"line,omitempty"`      // Line of the declaration
+	RelatedPath string  `json:"related_path"`        // Link to detailed data, as in DiagnosticResult
 }
 
 // DiagnosticResult represents an anti-pattern or code smell detected by integrated analysis
 type DiagnosticResult struct {
-	Type        string                 `json:"type"`         // "God Object", "Unstable Foundation", etc.
-	TargetName  string                 `json:"target_name"`  // Name of the problematic package or struct
-	Message     string                 `json:"message"`      // Human-readable description
-	Severity    string                 `json:"severity"`     // "Critical", "Warning"
-	Evidence    map[string]interface{} `json:"evidence"`     // Metric values that support this diagnosis
-	RelatedPath string                 `json:"related_path"` // Link to detailed data (e.g., "#lcom-UserManager")
+	Type        string   `json:"type"`                 // "God Object", "Unstable Foundation", etc.
+	TargetName  string   `json:"target_name"`          // Name of the problematic package or struct
+	Message     string   `json:"message"`              // Human-readable description
+	Severity    string   `json:"severity"`             // "Critical", "Warning", "Info"
+	Evidence    Evidence `json:"evidence"`             // Metric values that support this diagnosis (see evidence.go)
+	RelatedPath string   `json:"related_path"`         // Link to detailed data (e.g., "#lcom-UserManager")
+	AgeDays     int      `json:"age_days,omitempty"`   // Days since the source file last changed (with severity SLAs configured)
+	DueDate     string   `json:"due_date,omitempty"`   // Date the SLA expires (YYYY-MM-DD)
+	SLAStatus   string   `json:"sla_status,omitempty"` // "within", "overdue", or "unknown"
 }
 
 // PackageResult represents the analysis results for a single package
 type PackageResult struct {
-	Name            string           `json:"name"`             // Package name
-	Path            string           `json:"path"`             // Package import path
-	Afferent        int              `json:"afferent"`         // Ca: Number of packages that depend on this package
-	Efferent        int              `json:"efferent"`         // Ce: Number of packages this package depends on
-	Instability     float64          `json:"instability"`      // I: Ce / (Ca + Ce)
-	Structs         []StructResult   `json:"structs"`          // Struct analysis results
-	Functions       []FunctionResult `json:"functions"`        // Function analysis results
-	TotalLoC        int              `json:"total_loc"`        // Total lines of code in this package
-	AvgFuncLoC      float64          `json:"avg_func_loc"`     // Average lines of code per function
-	FuncCount       int              `json:"func_count"`       // Number of functions/methods in this package
-	FileCount       int              `json:"file_count"`       // Number of files in this package
-	DependencyDepth int              `json:"dependency_depth"` // Maximum depth of internal dependency chain
+	Name                  string                 `json:"name"`                             // Package name
+	Path                  string                 `json:"path"`                             // Package import path
+	Afferent              int                    `json:"afferent"`                         // Ca: Number of packages that depend on this package
+	Efferent              int                    `json:"efferent"`                         // Ce: Number of packages this package depends on
+	Instability           float64                `json:"instability"`                      // I: Ce / (Ca + Ce)
+	WeightedInstability   float64                `json:"weighted_instability"`             // Instability over import edges weighted by the distinct selectors used (see CalculateWeightedInstability)
+	Structs               []StructResult         `json:"structs"`                          // Struct analysis results
+	Functions             []FunctionResult       `json:"functions"`                        // Function analysis results
+	TotalLoC              int                    `json:"total_loc"`                        // Total lines of code in this package, counted per Report.LoCMode
+	SourceLoC             int                    `json:"source_loc"`                       // Lines containing code (excluding comment-only and blank lines)
+	CommentDensity        float64                `json:"comment_density"`                  // Comment lines / source lines (doc and inline comments)
+	DocCommentDensity     float64                `json:"doc_comment_density"`              // Doc comment lines / source lines
+	MaintainabilityIndex  float64                `json:"maintainability_index"`            // Mean Maintainability Index of the package's functions (0 without functions)
+	AvgFuncLoC            float64                `json:"avg_func_loc"`                     // Average lines of code per function, counted per Report.LoCMode
+	AvgComplexity         float64                `json:"avg_complexity"`                   // Mean cyclomatic complexity of the package's functions
+	MaxComplexity         int                    `json:"max_complexity"`                   // Highest cyclomatic complexity of the package's functions
+	TotalComplexity       int                    `json:"total_complexity"`                 // Sum of the cyclomatic complexity of the package's functions
+	FuncCount             int                    `json:"func_count"`                       // Number of functions/methods in this package
+	FileCount             int                    `json:"file_count"`                       // Number of files in this package
+	DependencyDepth       int                    `json:"dependency_depth"`                 // Maximum depth of internal dependency chain
+	DuplicateDeclarations []DuplicateDeclaration `json:"duplicate_declarations,omitempty"` // Top-level names declared more than once
+	Files                 []FileResult           `json:"files"`                            // Per-file metrics, sorted by path
+	Interfaces            []InterfaceResult      `json:"interfaces,omitempty"`             // Interface type declarations
+	InternalImports       []string               `json:"internal_imports,omitempty"`       // Project packages this package imports (paths relative to the root)
+	ErrorStyles           map[string]int         `json:"error_styles,omitempty"`           // Error constructions in function bodies by style (see error_styles.go)
+	ExportedDecls         int                    `json:"exported_decls"`                   // Exported top-level functions, types, variables and constants
+	TotalDecls            int                    `json:"total_decls"`                      // All top-level functions, types, variables and constants (methods excluded)
+	ExportedRatio         float64                `json:"exported_ratio"`                   // ExportedDecls / TotalDecls
+	AbstractTypes         int                    `json:"abstract_types"`                   // Interface type declarations
+	TotalTypes            int                    `json:"total_types"`                      // All type declarations
+	Abstractness          float64                `json:"abstractness"`                     // AbstractTypes / TotalTypes (A)
+	Distance              float64                `json:"distance"`                         // Distance from the main sequence, |A + I - 1|
+	ImportCycle           *ImportCycle           `json:"import_cycle,omitempty"`           // Import cycle the package belongs to, if any
+	TypeChecked           bool                   `json:"type_checked,omitempty"`           // True if function coupling was resolved with type information
+	UndocumentedExports   []UndocumentedExport   `json:"undocumented_exports,omitempty"`   // Exported declarations without a doc comment
+}
+
+// UndocumentedExport represents an exported declaration without a doc comment
+type UndocumentedExport struct {
+	Name     string `json:"name"`      // Declared name ("Type.Method" for methods)
+	Kind     string `json:"kind"`      // "func", "method", "type", "var", or "const"
+	FilePath string `json:"file_path"` // Source file path
+	Line     int    `json:"line"`      // Line of the declared name
+}
+
+// FileResult represents the metrics of a single source file
+type FileResult struct {
+	Path          string `json:"path"`              // Source file path
+	LoC           int    `json:"loc"`               // Lines including comments and blank lines
+	SourceLoC     int    `json:"source_loc"`        // Lines containing code
+	FuncCount     int    `json:"func_count"`        // Functions and methods declared in the file
+	StructCount   int    `json:"struct_count"`      // Structs declared in the file
+	MaxComplexity int    `json:"max_complexity"`    // Highest cyclomatic complexity among the file's functions
+	Ignored       bool   `json:"ignored,omitempty"` // True if a //codehealth:ignore-file comment suppresses the file's diagnostics
+	Churn         int    `json:"churn,omitempty"`   // Commits touching the file within the churn window (only with churn enabled)
+}
+
+// InterfaceResult represents an interface type declared in a package
+type InterfaceResult struct {
+	Name          string   `json:"name"`                     // Interface name
+	FilePath      string   `json:"file_path"`                // Source file path
+	Methods       []string `json:"methods"`                  // Explicitly declared method names
+	Embeds        []string `json:"embeds,omitempty"`         // Embedded interfaces
+	UnusedMethods []string `json:"unused_methods,omitempty"` // Methods never called anywhere in the project
+	Implementers  []string `json:"implementers,omitempty"`   // Project types implementing the interface ("*T" when only the pointer does)
+}
+
+// DuplicateDeclaration represents a top-level name declared in more than one place within a package
+type DuplicateDeclaration struct {
+	Name        string   `json:"name"`        // Declared name ("Type.Method" for methods)
+	Kind        string   `json:"kind"`        // "type", "func", "method", "var", or "const"
+	Locations   []string `json:"locations"`   // file:line of each declaration
+	Constrained bool     `json:"constrained"` // True if every declaring file has a build constraint
 }
 
 // StructResult represents the LCOM4 analysis results for a single struct
 type StructResult struct {
-	StructName       string                `json:"struct_name"`        // Name of the struct
-	FilePath         string                `json:"file_path"`          // Source file path
-	LCOM4Score       int                   `json:"lcom4_score"`        // LCOM4 score (number of connected components)
-	ComponentDetails [][]string            `json:"component_details"`  // Details of each connected component
-	MethodClusters   *MethodClusterAnalysis `json:"method_clusters,omitempty"`   // Private method clustering analysis
-	FieldMatrix      *FieldMatrixAnalysis   `json:"field_matrix,omitempty"`      // Method×Field usage matrix analysis
+	StructName               string                 `json:"struct_name"`                           // Name of the struct
+	FilePath                 string                 `json:"file_path"`                             // Source file path
+	Line                     int                    `json:"line"`                                  // Line of the struct's type declaration
+	EndLine                  int                    `json:"end_line"`                              // Last line of the struct's type declaration
+	FieldCount               int                    `json:"field_count"`                           // Number of fields (embedded fields count once)
+	MethodCount              int                    `json:"method_count"`                          // Number of methods declared on the struct in any file of the package
+	LCOM4Score               int                    `json:"lcom4_score"`                           // LCOM4 score (number of connected components)
+	ComponentDetails         [][]string             `json:"component_details"`                     // Details of each connected component
+	MethodClusters           *MethodClusterAnalysis `json:"method_clusters,omitempty"`             // Private method clustering analysis
+	FieldMatrix              *FieldMatrixAnalysis   `json:"field_matrix,omitempty"`                // Method×Field usage matrix analysis
+	ValueReceiverMutations   []ReceiverMutation     `json:"value_receiver_mutations,omitempty"`    // Value-receiver methods that write to fields
+	ProjectedLCOM4AfterSplit int                    `json:"projected_lcom4_after_split,omitempty"` // LCOM4 if the largest component were extracted (only when LCOM4 > 1)
+	SplitCandidate           []string               `json:"split_candidate,omitempty"`             // Methods and fields of the largest component (the extraction candidate)
+	MethodFiles              []string               `json:"method_files,omitempty"`                // Distinct files the struct's methods are declared in
+	MapRaces                 []MapRace              `json:"map_races,omitempty"`                   // Map fields possibly accessed concurrently without a lock (experimental)
+	UnsynchronizedAccesses   []UnsynchronizedAccess `json:"unsynchronized_accesses,omitempty"`     // Methods possibly touching fields concurrently without a lock (experimental, see shared_state.go)
+	EmbeddedFields           []string               `json:"embedded_fields,omitempty"`             // Embedded fields, counted in LCOM4 as fields named after their type
+	IsTest                   bool                   `json:"is_test,omitempty"`                     // True if the struct is declared in a _test.go file
+	WMC                      int                    `json:"wmc"`                                   // Weighted Methods per Class: sum of the cyclomatic complexity of the struct's methods
+	RFC                      int                    `json:"rfc"`                                   // Response For a Class: methods plus the distinct functions and methods they call
+	LCOM1                    int                    `json:"lcom1"`                                 // LCOM1 (Chidamber-Kemerer): method pairs sharing no fields
+	LCOM2                    int                    `json:"lcom2"`                                 // LCOM2: pairs sharing no fields minus pairs sharing some, floored at 0
+	TCC                      float64                `json:"tcc"`                                   // Tight Class Cohesion: share of method pairs using a common field (0 with fewer than two methods)
+	LCC                      float64                `json:"lcc"`                                   // Loose Class Cohesion: share of method pairs connected directly or through other methods' fields
+	FeatureEnvy              []FeatureEnvy          `json:"feature_envy,omitempty"`                // Methods reading more fields of another package struct than of their own
+	Suppressed               []string               `json:"suppressed,omitempty"`                  // Diagnostic types ignored by //codehealth:ignore directives ("*" for all, see suppress.go)
+	Unreferenced             bool                   `json:"unreferenced,omitempty"`                // True if nothing in the project refers to the struct type (see unused_types.go)
+}
+
+// ImportCycle represents packages that import each other, directly or transitively
+type ImportCycle struct {
+	Packages []string `json:"packages"` // Packages in the cycle (strongly connected component), sorted
+	Ring     []string `json:"ring"`     // Shortest import path from the first package back to itself
+}
+
+// MapRace represents a map field that methods may access concurrently without a lock (experimental heuristic)
+type MapRace struct {
+	Field      string   `json:"field"`      // Map-typed field name
+	Methods    []string `json:"methods"`    // Methods accessing the field without calling Lock/RLock
+	Writers    []string `json:"writers"`    // Subset of Methods that write the field
+	Goroutines []string `json:"goroutines"` // Subset of Methods started with a go statement
+}
+
+// UnsynchronizedAccess represents a method that may touch a struct's fields concurrently without a lock (experimental heuristic)
+type UnsynchronizedAccess struct {
+	Method    string   `json:"method"`    // Method name
+	Fields    []string `json:"fields"`    // Fields accessed without calling Lock/RLock
+	Goroutine bool     `json:"goroutine"` // True if the access happens in a goroutine the method starts, or the method is started as one
+}
+
+// ReceiverMutation represents a value-receiver method whose field writes are lost on return
+type ReceiverMutation struct {
+	Method string   `json:"method"` // Method name
+	Fields []string `json:"fields"` // Fields written through the value receiver
 }
 
 // MethodClusterAnalysis represents the result of private method call graph clustering
 type MethodClusterAnalysis struct {
-	TotalPrivateMethods int                `json:"total_private_methods"` // Total number of private methods
-	ClusterCount        int                `json:"cluster_count"`         // Number of detected method clusters (islands)
-	Clusters            []MethodCluster    `json:"clusters"`              // Details of each cluster
-	HasMultipleIslands  bool               `json:"has_multiple_islands"`  // True if >= 2 clusters exist
+	TotalPrivateMethods int             `json:"total_private_methods"` // Total number of private methods
+	ClusterCount        int             `json:"cluster_count"`         // Number of detected method clusters (islands)
+	Clusters            []MethodCluster `json:"clusters"`              // Details of each cluster
+	HasMultipleIslands  bool            `json:"has_multiple_islands"`  // True if >= 2 clusters exist
 }
 
 // MethodCluster represents a single cluster of related private methods
 type MethodCluster struct {
-	ID              int      `json:"id"`               // Cluster ID
-	Methods         []string `json:"methods"`          // Method names in this cluster
-	Size            int      `json:"size"`             // Number of methods in cluster
-	CalledBy        []string `json:"called_by"`        // Public methods that call into this cluster
-	ResponsibilityHint string `json:"responsibility_hint"` // Suggested responsibility name based on method names
+	ID                 int      `json:"id"`                  // Cluster ID
+	Methods            []string `json:"methods"`             // Method names in this cluster
+	Size               int      `json:"size"`                // Number of methods in cluster
+	CalledBy           []string `json:"called_by"`           // Public methods that call into this cluster
+	ResponsibilityHint string   `json:"responsibility_hint"` // Suggested responsibility name based on method names
 }
 
 // FieldMatrixAnalysis represents the result of Method×Field usage matrix analysis with PCA
 type FieldMatrixAnalysis struct {
-	Matrix              [][]int  `json:"matrix"`                // Method×Field usage matrix (1=used, 0=not used)
-	MethodNames         []string `json:"method_names"`          // Method names (rows)
-	FieldNames          []string `json:"field_names"`           // Field names (columns)
-	EstimatedClusters   int      `json:"estimated_clusters"`    // Estimated number of responsibility clusters via PCA
-	ExplainedVariance   []float64 `json:"explained_variance"`   // Variance explained by each principal component
-	HasMultipleResponsibilities bool `json:"has_multiple_responsibilities"` // True if estimated clusters >= 2
-	Recommendations     string   `json:"recommendations"`       // Human-readable recommendations
+	Matrix                      [][]int   `json:"matrix"`                        // Method×Field usage matrix (1=used, 0=not used)
+	MethodNames                 []string  `json:"method_names"`                  // Method names (rows)
+	FieldNames                  []string  `json:"field_names"`                   // Field names (columns)
+	EstimatedClusters           int       `json:"estimated_clusters"`            // Estimated number of responsibility clusters via PCA
+	ExplainedVariance           []float64 `json:"explained_variance"`            // Variance explained by each principal component
+	HasMultipleResponsibilities bool      `json:"has_multiple_responsibilities"` // True if estimated clusters >= 2
+	Recommendations             string    `json:"recommendations"`               // Human-readable recommendations
 }
 
 // FunctionResult represents the cyclomatic complexity analysis results for a single function
 type FunctionResult struct {
-	FuncName         string   `json:"function_name"`      // Function/method name
-	FilePath         string   `json:"file_path"`          // Source file path
-	Complexity       int      `json:"complexity"`         // Cyclomatic complexity score
-	LoC              int      `json:"loc"`                // Lines of code in this function
-	Dependencies     []string `json:"dependencies"`       // List of external packages this function depends on
-	InternalDeps     []string `json:"internal_deps"`      // List of internal (project) packages this function depends on
-	ExternalDeps     []string `json:"external_deps"`      // List of external (3rd party) packages this function depends on
-	DependencyCount  int      `json:"dependency_count"`   // Total number of package dependencies
-	Afferent         int      `json:"afferent"`           // Ca: Number of functions that call this function (within project)
-	Efferent         int      `json:"efferent"`           // Ce: Number of external functions/packages this function calls
-	Instability      float64  `json:"instability"`        // I: Ce / (Ca + Ce)
+	FuncName             string           `json:"function_name"`                   // Function/method name
+	FilePath             string           `json:"file_path"`                       // Source file path
+	Complexity           int              `json:"complexity"`                      // Cyclomatic complexity score
+	CognitiveComplexity  int              `json:"cognitive_complexity"`            // Cognitive complexity (nesting-aware, see cognitive.go)
+	MaxNestingDepth      int              `json:"max_nesting_depth"`               // Deepest nesting of if/for/range/switch/select blocks
+	LoC                  int              `json:"loc"`                             // Lines of code in this function
+	SourceLoC            int              `json:"source_loc"`                      // Lines of the body containing code (excluding comment-only and blank lines)
+	Statements           int              `json:"statements"`                      // Statements in the body, not counting blocks themselves
+	Dependencies         []string         `json:"dependencies"`                    // List of external packages this function depends on
+	InternalDeps         []string         `json:"internal_deps"`                   // List of internal (project) packages this function depends on
+	ExternalDeps         []string         `json:"external_deps"`                   // List of external (3rd party) packages this function depends on
+	DependencyCount      int              `json:"dependency_count"`                // Total number of package dependencies
+	Afferent             int              `json:"afferent"`                        // Ca: Number of functions that call this function (within project)
+	Efferent             int              `json:"efferent"`                        // Ce: Number of external functions/packages this function calls
+	FanOut               int              `json:"fan_out"`                         // Number of distinct functions and methods this function calls (see extractCallTargets)
+	Instability          float64          `json:"instability"`                     // I: Ce / (Ca + Ce)
+	HasTestReference     bool             `json:"has_test_reference"`              // True if test files reference this function directly or transitively
+	Unreferenced         bool             `json:"unreferenced,omitempty"`          // True if the function is unexported and no other declaration in the package refers to it
+	MagicNumbers         int              `json:"magic_numbers"`                   // Numeric literals used in logic (excluding 0, 1, consts, and array sizes)
+	AssertionSubject     string           `json:"assertion_subject,omitempty"`     // Expression type-asserted to the most distinct types
+	AssertedTypes        []string         `json:"asserted_types,omitempty"`        // Distinct types AssertionSubject is asserted to (set when >= 2)
+	ParamCount           int              `json:"param_count"`                     // Number of parameters (receiver excluded, variadic counts once)
+	ResultCount          int              `json:"result_count"`                    // Number of values the function returns
+	ResultTypes          []string         `json:"result_types,omitempty"`          // Type of each returned value
+	SurfaceComplexity    int              `json:"surface_complexity"`              // Parameters plus results plus penalties for any params and extra error results (see signature.go)
+	LoopAllocations      []LoopAllocation `json:"loop_allocations,omitempty"`      // Loops containing allocations (only with perf hints enabled)
+	Line                 int              `json:"line"`                            // Line of the function declaration
+	EndLine              int              `json:"end_line"`                        // Line of the function's closing brace
+	AnonymousTypes       int              `json:"anonymous_types"`                 // Non-empty struct/interface types written inline in the signature and body
+	IsTest               bool             `json:"is_test,omitempty"`               // True if the function is declared in a _test.go file
+	HalsteadVolume       float64          `json:"halstead_volume"`                 // Halstead volume: program length times log2 of the vocabulary (see halstead.go)
+	HalsteadEffort       float64          `json:"halstead_effort"`                 // Halstead effort: difficulty times volume
+	MaintainabilityIndex float64          `json:"maintainability_index"`           // Maintainability Index (0-100) from Halstead volume, complexity, and source LoC
+	LiteralLoC           int              `json:"literal_loc,omitempty"`           // Lines spanned by the largest composite literal in the body
+	Params               []Param          `json:"params,omitempty"`                // Name and normalized type of each parameter (see data_clumps.go)
+	Suppressed           []string         `json:"suppressed,omitempty"`            // Diagnostic types ignored by //codehealth:ignore directives ("*" for all, see suppress.go)
+	BodyHash             string           `json:"body_hash,omitempty"`             // Hash of the body's structure, ignoring names and literal values (see clones.go)
+	Churn                int              `json:"churn,omitempty"`                 // Commits touching the function's file within the churn window (only with churn enabled)
+	IsRecursive          bool             `json:"is_recursive,omitempty"`          // True if the function calls itself, directly or through a recursive closure
+	IsMutuallyRecursive  bool             `json:"is_mutually_recursive,omitempty"` // True if the function is in a call cycle with other functions of its package
+}
+
+// Param represents one parameter of a function signature
+type Param struct {
+	Name string `json:"name"` // Parameter name (empty for unnamed parameters)
+	Type string `json:"type"` // Type with qualifiers normalized to package names (see normalizeTypeName)
+}
+
+// LoopAllocation represents a loop whose body allocates on every iteration
+type LoopAllocation struct {
+	Line        int      `json:"line"`        // Line of the for/range statement
+	Allocations []string `json:"allocations"` // Kinds of allocations found in the loop body
+}
+
+// FeatureEnvy represents a method that reads more fields of another struct than of its own receiver
+type FeatureEnvy struct {
+	Method       string   `json:"method"`        // Method name
+	FilePath     string   `json:"file_path"`     // Source file of the method
+	Line         int      `json:"line"`          // Line of the method declaration
+	OwnFields    int      `json:"own_fields"`    // Distinct receiver fields the method accesses
+	EnviedStruct string   `json:"envied_struct"` // Struct whose fields the method reads the most
+	EnviedFields []string `json:"envied_fields"` // Distinct fields of that struct the method reads
 }
@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// markUnreferencedStructs sets Unreferenced on structs that nothing in the project refers to.
+// A reference is any use of the type's name outside its own declaration and the receivers of
+// its methods: struct literals, variable and parameter types, conversions, type assertions,
+// field types, and map, slice, and channel element types all count. Other packages refer to a
+// type through an import (pkg.T). Test and generated files count as references.
+//
+// Types that implement a project interface are left unmarked, since they may be used only
+// through it. Structs declared in _test.go files are never marked.
+func markUnreferencedStructs(packageResults []PackageResult, packages map[string]*ParsedPackage, modules projectModules) {
+	// Names used in each package, and names used through imports by other packages
+	localRefs := make(map[string]map[string]bool)
+	importedRefs := make(map[string]map[string]bool)
+	for pkgPath := range packages {
+		localRefs[pkgPath] = make(map[string]bool)
+		importedRefs[pkgPath] = make(map[string]bool)
+	}
+
+	for pkgPath, pkg := range packages {
+		files := make([]*ast.File, 0, len(pkg.Package.Files))
+		for _, file := range pkg.Package.Files {
+			files = append(files, file)
+		}
+		for _, file := range pkg.referenceFiles() {
+			files = append(files, file)
+		}
+
+		for _, file := range files {
+			collectTypeReferences(file, localRefs[pkgPath])
+
+			projectImports := projectImportNames(file, pkgPath, packages, modules)
+			if len(projectImports) == 0 {
+				continue
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				selector, ok := n.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if ident, ok := selector.X.(*ast.Ident); ok && ident.Obj == nil {
+					if importedPath, ok := projectImports[ident.Name]; ok && importedRefs[importedPath] != nil {
+						importedRefs[importedPath][selector.Sel.Name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	// Project types implementing an interface, by package path and name
+	implementers := make(map[string]bool)
+	for _, pkg := range packageResults {
+		for _, iface := range pkg.Interfaces {
+			for _, implementer := range iface.Implementers {
+				implementers[strings.TrimPrefix(implementer, "*")] = true
+			}
+		}
+	}
+
+	for i := range packageResults {
+		pkg := &packageResults[i]
+		for j := range pkg.Structs {
+			s := &pkg.Structs[j]
+			if s.IsTest || s.StructName == "_" || implementers[qualifiedTypeName(pkg.Path, s.StructName)] {
+				continue
+			}
+			s.Unreferenced = !localRefs[pkg.Path][s.StructName] && !importedRefs[pkg.Path][s.StructName]
+		}
+	}
+}
+
+// collectTypeReferences adds every identifier of a file that may refer to a type to refs,
+// leaving out the names being declared by type specs and the receivers of methods
+func collectTypeReferences(file *ast.File, refs map[string]bool) {
+	addIdents := func(node ast.Node, skip string) {
+		if node == nil {
+			return
+		}
+		ast.Inspect(node, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && ident.Name != skip {
+				refs[ident.Name] = true
+			}
+			return true
+		})
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			addIdents(d.Type, "")
+			if d.Body != nil {
+				addIdents(d.Body, "")
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					// A self-referencing field (next *Node) is not a use of the type
+					if typeSpec.TypeParams != nil {
+						addIdents(typeSpec.TypeParams, typeSpec.Name.Name)
+					}
+					addIdents(typeSpec.Type, typeSpec.Name.Name)
+					continue
+				}
+				addIdents(spec, "")
+			}
+		}
+	}
+}
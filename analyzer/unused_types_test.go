@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestUnusedTypes(t *testing.T) {
+	report := analyzeFixture(t, map[string]string{
+		"store/store.go": `package store
+
+// Orphan is declared but never used
+type Orphan struct {
+	ID int
+}
+
+// onlyMethods is mentioned only in its own method receivers
+type onlyMethods struct{ n int }
+
+func (o *onlyMethods) Get() int { return o.n }
+
+// entry is used only as a map value type
+type entry struct {
+	value string
+}
+
+var cache map[string]entry
+
+func Get(key string) string { return cache[key].value }
+
+// Exported is used from another package
+type Exported struct{}
+
+// Finder is implemented by memoryFinder, which is only reached through the interface
+type Finder interface {
+	Find(id int) string
+}
+
+type memoryFinder struct{}
+
+func (memoryFinder) Find(id int) string { return "" }
+
+// fixture is used only by the tests
+type fixture struct{}
+`,
+		"store/store_test.go": "package store\n\nvar _ = fixture{}\n",
+		"app/app.go":          "package app\n\nimport \"example.com/app/store\"\n\nvar _ *store.Exported\n",
+	}, nil)
+
+	var unused []string
+	exported := make(map[string]bool)
+	for _, d := range diagnosticsOfType(report, DiagnosticUnusedType) {
+		unused = append(unused, d.TargetName)
+		if evidence, ok := d.Evidence.(UnusedTypeEvidence); ok {
+			exported[d.TargetName] = evidence.Exported
+		}
+	}
+	sort.Strings(unused)
+	if want := []string{"store.Orphan", "store.onlyMethods"}; !reflect.DeepEqual(unused, want) {
+		t.Errorf("unused types = %v, want %v", unused, want)
+	}
+
+	pkg := findPackage(t, report, "store")
+	if findStruct(t, pkg, "entry").Unreferenced {
+		t.Errorf("entry is used as a map value type but was marked unreferenced")
+	}
+	if !exported["store.Orphan"] || exported["store.onlyMethods"] {
+		t.Errorf("exported = %v, want only store.Orphan", exported)
+	}
+}
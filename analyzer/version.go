@@ -0,0 +1,24 @@
+package analyzer
+
+import "runtime/debug"
+
+// SchemaVersion is the version of the JSON report format (Report and everything it contains).
+// Bump it whenever fields are added, removed, renamed, or change meaning, so downstream tools
+// can detect the change. Reports written before versioning have no schema_version.
+const SchemaVersion = "12"
+
+// Version is the analyzer version reported in Report.AnalyzerVersion. Release builds set it with
+// -ldflags "-X github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer.Version=v1.2.3";
+// otherwise the module version from the build info (go install ...@v1.2.3) or "dev" is used.
+var Version = ""
+
+// analyzerVersion returns the version of this analyzer build
+func analyzerVersion() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
@@ -0,0 +1,18 @@
+package analyzer
+
+import "strings"
+
+// applyWeightedMethods sets each struct's WMC (Weighted Methods per Class): the sum of the
+// cyclomatic complexity of its methods, joined by the "Struct.Method" function name
+func applyWeightedMethods(structs []StructResult, functions []FunctionResult) {
+	wmc := make(map[string]int)
+	for _, f := range functions {
+		if structName, _, isMethod := strings.Cut(f.FuncName, "."); isMethod {
+			wmc[structName] += f.Complexity
+		}
+	}
+
+	for i := range structs {
+		structs[i].WMC = wmc[structs[i].StructName]
+	}
+}
@@ -0,0 +1,81 @@
+package analyzer
+
+import "testing"
+
+// wmcFixture declares a struct whose three methods have complexities 3, 5, and 9
+var wmcFixture = map[string]string{
+	"shape/shape.go": `package shape
+
+type Shape struct{ kind, sides int }
+
+func (s *Shape) Small() bool {
+	if s.sides > 0 && s.sides < 3 {
+		return true
+	}
+	return false
+}
+
+func (s *Shape) Name() string {
+	switch s.kind {
+	case 1:
+		return "triangle"
+	case 2:
+		return "square"
+	case 3:
+		return "pentagon"
+	}
+	return ""
+}
+
+func (s *Shape) Score() int {
+	n := 0
+	for i := 0; i < s.sides; i++ {
+		if i%2 == 0 || i%3 == 0 {
+			n++
+		}
+		if i%5 == 0 && i%7 == 0 {
+			n--
+		}
+		if i > 10 {
+			break
+		}
+		if i > 20 || i < -1 {
+			n = 0
+		}
+	}
+	return n
+}
+
+func Free(n int) int {
+	if n > 0 {
+		return n
+	}
+	return 0
+}
+`,
+}
+
+func TestWeightedMethodsPerClass(t *testing.T) {
+	report := analyzeFixture(t, wmcFixture, nil)
+
+	pkg := findPackage(t, report, "shape")
+	for name, want := range map[string]int{"Shape.Small": 3, "Shape.Name": 5, "Shape.Score": 9} {
+		if got := findFunction(t, pkg, name).Complexity; got != want {
+			t.Fatalf("%s complexity = %d, want %d", name, got, want)
+		}
+	}
+	if got := findStruct(t, pkg, "Shape").WMC; got != 17 {
+		t.Errorf("WMC = %d, want 17 (3+5+9, Free not counted)", got)
+	}
+}
+
+func TestHighStructComplexityThreshold(t *testing.T) {
+	for threshold, want := range map[int]int{17: 1, 18: 0, 0: 0} {
+		cfg := DefaultConfig()
+		cfg.WMCThreshold = threshold
+		report := analyzeFixture(t, wmcFixture, cfg)
+		if got := len(diagnosticsOfType(report, DiagnosticHighStructComplexity)); got != want {
+			t.Errorf("threshold %d: %d diagnostics, want %d", threshold, got, want)
+		}
+	}
+}
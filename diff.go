@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/reporter"
+)
+
+// runDiff implements the diff subcommand, comparing two JSON reports
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	formatFlag := fs.String("format", "html", "Output format: html, markdown, or json")
+	outputFlag := fs.String("output", "", "Output file path (default: code_health_diff.html, .md, or .json)")
+	quietFlag := fs.Bool("quiet", false, "Print nothing but errors")
+	fs.Usage = printDiffUsage
+
+	positional, err := parseInterleaved(fs, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(positional) != 2 {
+		printDiffUsage()
+		os.Exit(1)
+	}
+	oldPath, newPath := positional[0], positional[1]
+	if *quietFlag {
+		out = io.Discard
+	}
+
+	oldReport, err := reporter.LoadJSONReport(oldPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	newReport, err := reporter.LoadJSONReport(newPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	diff := analyzer.CompareReports(oldReport, newReport)
+
+	if err := generateDiff(diff, oldPath, newPath, strings.ToLower(*formatFlag), *outputFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(out, "\n✅ Diff complete!\n")
+	fmt.Fprintf(out, "   New diagnostics: %d\n", len(diff.NewDiagnostics))
+	fmt.Fprintf(out, "   Fixed diagnostics: %d\n", len(diff.FixedDiagnostics))
+	fmt.Fprintf(out, "   Added packages: %d, removed packages: %d\n", len(diff.AddedPackages), len(diff.RemovedPackages))
+	fmt.Fprintln(out)
+}
+
+// generateDiff writes the diff in the requested format
+func generateDiff(diff *analyzer.ReportDiff, oldPath, newPath, format, outputPath string) error {
+	var defaultOutput string
+	switch format {
+	case "html":
+		defaultOutput = "code_health_diff.html"
+	case "markdown", "md":
+		format = "markdown"
+		defaultOutput = "code_health_diff.md"
+	case "json":
+		defaultOutput = "code_health_diff.json"
+	default:
+		return fmt.Errorf("invalid format '%s'. Use 'html', 'markdown', or 'json'", format)
+	}
+
+	if outputPath == "" {
+		outputPath = defaultOutput
+	}
+
+	absOutputPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return fmt.Errorf("error resolving output path: %w", err)
+	}
+
+	fmt.Fprintf(out, "Generating %s diff report...\n", format)
+	switch format {
+	case "html":
+		err = reporter.GenerateDiffHTMLReport(diff, oldPath, newPath, absOutputPath)
+	case "markdown":
+		err = reporter.GenerateDiffMarkdownReport(diff, oldPath, newPath, absOutputPath)
+	case "json":
+		err = reporter.GenerateDiffJSONReport(diff, absOutputPath)
+	}
+	if err != nil {
+		return fmt.Errorf("error generating diff report: %w", err)
+	}
+
+	fmt.Fprintf(out, "📊 Diff report saved to: %s\n", absOutputPath)
+	return nil
+}
+
+// parseInterleaved parses flags that may appear before, between, or after positional arguments
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func printDiffUsage() {
+	fmt.Println("Go Code Health Analyzer - diff")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  go-code-health-analyzer diff [options] <old-report.json> <new-report.json>")
+	fmt.Println()
+	fmt.Println("Options:")
+	fmt.Println("  -format string")
+	fmt.Println("        Output format: html, markdown, or json (default: html)")
+	fmt.Println("  -output string")
+	fmt.Println("        Output file path (default: code_health_diff.html, .md, or .json)")
+	fmt.Println("  -quiet")
+	fmt.Println("        Print nothing but errors")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  # Compare two releases as an HTML report")
+	fmt.Println("  go-code-health-analyzer diff v1.json v2.json")
+	fmt.Println()
+	fmt.Println("  # Compare two releases as Markdown")
+	fmt.Println("  go-code-health-analyzer diff v1.json v2.json -format markdown -output diff.md")
+}
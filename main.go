@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,26 +12,93 @@ import (
 	"github.com/hiroki-yamauchi/go-code-health-analyzer/reporter"
 )
 
+// stdoutPath is the -output value that writes the report to stdout
+const stdoutPath = "-"
+
+// out receives progress messages and the summary. -quiet discards them; errors always go to stderr.
+var out io.Writer = os.Stdout
+
 func main() {
+	// Dispatch subcommands
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+
+	// prepare takes the same flags as an analysis but only fills the -cache-dir cache
+	cliArgs := os.Args[1:]
+	prepare := len(cliArgs) > 0 && cliArgs[0] == "prepare"
+	if prepare {
+		cliArgs = cliArgs[1:]
+	}
+
 	// Define command line flags
-	formatFlag := flag.String("format", "html", "Output format: html, json, or both")
-	outputFlag := flag.String("output", "", "Output file path (default: code_health_report.html or code_health_report.json)")
-	excludeFlag := flag.String("exclude", "", "Comma-separated list of directory names to exclude (e.g., vendor,node_modules,tmp)")
+	formatFlag := flag.String("format", "html", "Output format: html, json, jsonl, both, prometheus, openmetrics, github, or mermaid")
+	outputFlag := flag.String("output", "", "Output file path, or - for stdout (default: code_health_report.html, .json, .jsonl, .prom, or .om)")
+	excludeFlag := flag.String("exclude", "", "Comma-separated list of directories, globs, or regex: patterns to exclude (e.g., vendor,internal/**,*.pb)")
+	perfHintsFlag := flag.Bool("perf-hints", false, "Enable heuristic performance diagnostics such as allocations inside loops")
+	experimentalFlag := flag.Bool("experimental", false, "Enable experimental diagnostics such as unsynchronized map and field access (syntactic heuristics without type information: false positives and misses are expected)")
+	failOnFlag := flag.String("fail-on", "none", "Exit with status 1 if diagnostics at or above this severity exist: none, warning, or critical")
+	includeTestsFlag := flag.Bool("include-tests", false, "Measure _test.go files alongside production code")
+	includeGeneratedFlag := flag.Bool("include-generated", false, "Measure generated files (// Code generated ... DO NOT EDIT.)")
+	lcomTransitiveFlag := flag.Bool("lcom-transitive", false, "Count fields reached through same-struct method calls in LCOM4")
+	typeCheckFlag := flag.Bool("typecheck", false, "Resolve call targets with type information (falls back to AST matching if type-checking fails)")
+	baselineFlag := flag.String("baseline", "", "Baseline JSON report to compare the analysis against (writes code_health_diff.html or .json)")
+	configFlag := flag.String("config", "", "Configuration file path (default: .codehealth.json in the target directory)")
+	topFlag := flag.Int("top", 10, "Number of entries in the ranked list of worst offenders (0 = none)")
+	externalCouplingFlag := flag.String("external-coupling", "all", "External imports counted in function efferent coupling: all, stdlib, or thirdparty")
+	var internalPrefixFlag stringList
+	locModeFlag := flag.String("loc-mode", "physical", "How package and project LoC are counted: physical, source, or statements")
+	flag.Var(&internalPrefixFlag, "internal-prefix", "Import path prefix treated as internal besides the project's modules (repeatable, e.g. github.com/org)")
+	churnDaysFlag := flag.Int("churn-days", 0, "Count git commits per file over this many days and rank hotspots by complexity x churn (0 = disabled)")
+	fromJSONFlag := flag.String("from-json", "", "Render a JSON report saved earlier instead of analyzing a target (e.g. -from-json report.json -format html)")
+	remoteFlag := flag.Bool("remote", false, "Treat the target as a module path@version to download and analyze (e.g. github.com/user/repo@latest)")
+	quietFlag := flag.Bool("quiet", false, "Print nothing but errors (reports are still written)")
+	verboseFlag := flag.Bool("verbose", false, "Report analysis progress and phase timings on stderr")
+	seedFlag := flag.Int64("seed", 0, "Seed for the PCA power iteration used in field clustering (default: config value, 0 = fixed start vector)")
+	cacheDirFlag := flag.String("cache-dir", "", "Directory caching analysis results; an unchanged target with the same options is loaded from it instead of analyzed")
 	flag.Usage = printUsage
-	flag.Parse()
+	flag.CommandLine.Parse(cliArgs)
 
-	// Get target path from positional argument
-	args := flag.Args()
-	if len(args) < 1 {
-		printUsage()
+	// With the report on stdout, progress and the summary move to stderr
+	if *quietFlag {
+		out = io.Discard
+	} else if *outputFlag == stdoutPath {
+		out = os.Stderr
+	}
+
+	if *outputFlag == stdoutPath && strings.ToLower(*formatFlag) == "both" {
+		fmt.Fprintf(os.Stderr, "Error: -output - cannot be used with -format both, which writes two reports\n")
+		os.Exit(1)
+	}
+
+	// Validate the failure threshold before doing any work
+	failOn := strings.ToLower(*failOnFlag)
+	if failOn != "none" && failOn != "warning" && failOn != "critical" {
+		fmt.Fprintf(os.Stderr, "Error: Invalid -fail-on value '%s'. Use 'none', 'warning', or 'critical'\n", *failOnFlag)
 		os.Exit(1)
 	}
 
-	targetPath := args[0]
+	if *topFlag < 0 {
+		fmt.Fprintf(os.Stderr, "Error: Invalid -top value %d. Use 0 or a positive number\n", *topFlag)
+		os.Exit(1)
+	}
+
+	externalCoupling := strings.ToLower(*externalCouplingFlag)
+	if externalCoupling != analyzer.ExternalCouplingAll && externalCoupling != analyzer.ExternalCouplingStdlib &&
+		externalCoupling != analyzer.ExternalCouplingThirdParty {
+		fmt.Fprintf(os.Stderr, "Error: Invalid -external-coupling value '%s'. Use 'all', 'stdlib', or 'thirdparty'\n", *externalCouplingFlag)
+		os.Exit(1)
+	}
+
+	locMode := strings.ToLower(*locModeFlag)
+	if locMode != analyzer.LoCModePhysical && locMode != analyzer.LoCModeSource && locMode != analyzer.LoCModeStatements {
+		fmt.Fprintf(os.Stderr, "Error: Invalid -loc-mode value '%s'. Use 'physical', 'source', or 'statements'\n", *locModeFlag)
+		os.Exit(1)
+	}
 
-	// Check if target path exists
-	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: Target path does not exist: %s\n", targetPath)
+	if *churnDaysFlag < 0 {
+		fmt.Fprintf(os.Stderr, "Error: Invalid -churn-days value %d. Use 0 or a positive number\n", *churnDaysFlag)
 		os.Exit(1)
 	}
 
@@ -44,16 +112,147 @@ func main() {
 		}
 	}
 
-	fmt.Printf("Analyzing Go project at: %s\n", targetPath)
-	if len(excludeDirs) > 0 {
-		fmt.Printf("Excluding directories: %s\n", strings.Join(excludeDirs, ", "))
+	// analyzeTarget loads the target's configuration, applies the command line overrides, and
+	// analyzes it, exiting on failure
+	analyzeTarget := func(targetPath string) *analyzer.Report {
+		// Load configuration from the project root, then apply command line overrides
+		cfg, err := analyzer.DiscoverConfigWithFile(targetPath, *configFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "seed":
+				cfg.Seed = *seedFlag
+			case "perf-hints":
+				cfg.PerfHints = *perfHintsFlag
+			case "experimental":
+				cfg.Experimental = *experimentalFlag
+			case "include-tests":
+				cfg.IncludeTests = *includeTestsFlag
+			case "include-generated":
+				cfg.IncludeGenerated = *includeGeneratedFlag
+			case "typecheck":
+				cfg.TypeCheck = *typeCheckFlag
+			case "lcom-transitive":
+				cfg.LCOMTransitive = *lcomTransitiveFlag
+			case "top":
+				cfg.TopOffenders = *topFlag
+			case "external-coupling":
+				cfg.ExternalCoupling = externalCoupling
+			case "loc-mode":
+				cfg.LoCMode = locMode
+			case "churn-days":
+				cfg.ChurnDays = *churnDaysFlag
+			case "internal-prefix":
+				cfg.InternalPrefixes = append(cfg.InternalPrefixes, internalPrefixFlag...)
+			}
+		})
+
+		// Perform analysis, reporting progress on stderr with -verbose
+		opts := analyzer.AnalyzeOptions{ExcludeDirs: excludeDirs, Config: cfg, CacheDir: *cacheDirFlag}
+		if *verboseFlag {
+			opts.Logger = os.Stderr
+		}
+		report, err := analyzer.AnalyzeWithOptions(targetPath, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error during analysis: %v\n", err)
+			os.Exit(1)
+		}
+		return report
 	}
 
-	// Perform analysis
-	report, err := analyzer.Analyze(targetPath, excludeDirs)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error during analysis: %v\n", err)
-		os.Exit(1)
+	// prepare analyzes each target into the cache and writes no report
+	if prepare {
+		if *cacheDirFlag == "" {
+			fmt.Fprintf(os.Stderr, "Error: prepare requires -cache-dir\n")
+			os.Exit(1)
+		}
+		if *fromJSONFlag != "" || *remoteFlag || *baselineFlag != "" {
+			fmt.Fprintf(os.Stderr, "Error: prepare analyzes local directories and takes no -from-json, -remote, or -baseline\n")
+			os.Exit(1)
+		}
+		if flag.NArg() < 1 {
+			printUsage()
+			os.Exit(1)
+		}
+		for _, targetPath := range flag.Args() {
+			if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Error: Target path does not exist: %s\n", targetPath)
+				os.Exit(1)
+			}
+			fmt.Fprintf(out, "Preparing cache for: %s\n", targetPath)
+			report := analyzeTarget(targetPath)
+			fmt.Fprintf(out, "Cached %d packages and %d diagnostics in %s\n", len(report.Packages), len(report.Diagnostics), *cacheDirFlag)
+		}
+		return
+	}
+
+	args := flag.Args()
+	var targetPath string
+	if *fromJSONFlag != "" {
+		// -from-json renders a saved report, so there is nothing to analyze
+		if len(args) > 0 || *remoteFlag {
+			fmt.Fprintf(os.Stderr, "Error: -from-json renders a saved report and takes no target directory or -remote module\n")
+			os.Exit(1)
+		}
+	} else {
+		// Get target path from positional argument
+		if len(args) < 1 {
+			printUsage()
+			os.Exit(1)
+		}
+
+		targetPath = args[0]
+
+		// With -remote, the target is a module to fetch into the module cache and analyze there
+		if *remoteFlag {
+			fmt.Fprintf(out, "Downloading module %s...\n", targetPath)
+			module, err := downloadModule(targetPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(out, "Downloaded %s@%s to %s\n", module.Path, module.Version, module.Dir)
+			targetPath = module.Dir
+		}
+
+		// Check if target path exists
+		if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: Target path does not exist: %s\n", targetPath)
+			os.Exit(1)
+		}
+	}
+
+	// Load the baseline before analyzing so a bad path fails fast
+	var baseline *analyzer.Report
+	if *baselineFlag != "" {
+		var err error
+		baseline, err = reporter.LoadJSONReport(*baselineFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading baseline: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Load the saved report with -from-json, or analyze the target
+	var report *analyzer.Report
+	if *fromJSONFlag != "" {
+		var err error
+		report, err = reporter.LoadJSONReport(*fromJSONFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		targetPath = report.TargetPath
+		fmt.Fprintf(out, "Loaded report of %s from %s\n", report.TargetPath, *fromJSONFlag)
+	} else {
+		fmt.Fprintf(out, "Analyzing Go project at: %s\n", targetPath)
+		if len(excludeDirs) > 0 {
+			fmt.Fprintf(out, "Excluding directories: %s\n", strings.Join(excludeDirs, ", "))
+		}
+		report = analyzeTarget(targetPath)
 	}
 
 	// Normalize format flag
@@ -71,6 +270,11 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+	case "jsonl":
+		if err := generateJSONL(report, *outputFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	case "both":
 		htmlOutput := *outputFlag
 		if htmlOutput == "" {
@@ -86,13 +290,73 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error generating JSON: %v\n", err)
 			os.Exit(1)
 		}
+	case "prometheus":
+		if err := generatePrometheus(report, *outputFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "openmetrics":
+		if err := generateOpenMetrics(report, *outputFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "github":
+		if err := generateGitHubAnnotations(report, *outputFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "mermaid":
+		if err := generateMermaid(report, *outputFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	default:
-		fmt.Fprintf(os.Stderr, "Error: Invalid format '%s'. Use 'html', 'json', or 'both'\n", format)
+		fmt.Fprintf(os.Stderr, "Error: Invalid format '%s'. Use 'html', 'json', 'jsonl', 'both', 'prometheus', 'openmetrics', 'github', or 'mermaid'\n", format)
 		os.Exit(1)
 	}
 
 	// Print summary
 	printSummary(report)
+
+	// Compare against the baseline (JSON diff for JSON output, HTML otherwise)
+	if baseline != nil {
+		diffFormat := "html"
+		if format == "json" {
+			diffFormat = "json"
+		}
+		diff := analyzer.CompareReports(baseline, report)
+		if err := generateDiff(diff, *baselineFlag, targetPath, diffFormat, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(out, "   New diagnostics since baseline: %d\n", len(diff.NewDiagnostics))
+		fmt.Fprintf(out, "   Fixed diagnostics since baseline: %d\n", len(diff.FixedDiagnostics))
+		fmt.Fprintln(out)
+	}
+
+	// Fail the build (after the reports are written) if diagnostics reach the -fail-on severity
+	if failing := countFailingDiagnostics(report, failOn); failing > 0 {
+		fmt.Fprintf(os.Stderr, "❌ %d diagnostic(s) at or above %s severity (-fail-on %s)\n", failing, failOn, failOn)
+		os.Exit(1)
+	}
+}
+
+// countFailingDiagnostics counts diagnostics at or above the -fail-on severity
+func countFailingDiagnostics(report *analyzer.Report, failOn string) int {
+	count := 0
+	for _, d := range report.Diagnostics {
+		switch failOn {
+		case "critical":
+			if d.Severity == "Critical" {
+				count++
+			}
+		case "warning":
+			if d.Severity == "Critical" || d.Severity == "Warning" {
+				count++
+			}
+		}
+	}
+	return count
 }
 
 func generateHTML(report *analyzer.Report, outputPath string) error {
@@ -100,17 +364,20 @@ func generateHTML(report *analyzer.Report, outputPath string) error {
 		outputPath = "code_health_report.html"
 	}
 
-	absOutputPath, err := filepath.Abs(outputPath)
+	w, absOutputPath, err := openOutput(outputPath)
 	if err != nil {
-		return fmt.Errorf("error resolving output path: %w", err)
+		return err
 	}
+	defer w.Close()
 
-	fmt.Printf("Generating HTML report...\n")
-	if err := reporter.GenerateHTMLReport(report, absOutputPath); err != nil {
+	fmt.Fprintf(out, "Generating HTML report...\n")
+	if err := reporter.WriteHTMLReport(report, w); err != nil {
 		return fmt.Errorf("error generating HTML report: %w", err)
 	}
 
-	fmt.Printf("📊 HTML report saved to: %s\n", absOutputPath)
+	if absOutputPath != "" {
+		fmt.Fprintf(out, "📊 HTML report saved to: %s\n", absOutputPath)
+	}
 	return nil
 }
 
@@ -119,23 +386,176 @@ func generateJSON(report *analyzer.Report, outputPath string) error {
 		outputPath = "code_health_report.json"
 	}
 
-	absOutputPath, err := filepath.Abs(outputPath)
+	w, absOutputPath, err := openOutput(outputPath)
 	if err != nil {
-		return fmt.Errorf("error resolving output path: %w", err)
+		return err
 	}
+	defer w.Close()
 
-	fmt.Printf("Generating JSON report...\n")
-	if err := reporter.GenerateJSONReport(report, absOutputPath); err != nil {
+	fmt.Fprintf(out, "Generating JSON report...\n")
+	if err := reporter.WriteJSONReport(report, w); err != nil {
 		return fmt.Errorf("error generating JSON report: %w", err)
 	}
 
-	fmt.Printf("📊 JSON report saved to: %s\n", absOutputPath)
+	if absOutputPath != "" {
+		fmt.Fprintf(out, "📊 JSON report saved to: %s\n", absOutputPath)
+	}
+	return nil
+}
+
+func generateJSONL(report *analyzer.Report, outputPath string) error {
+	if outputPath == "" {
+		outputPath = "code_health_report.jsonl"
+	}
+
+	w, absOutputPath, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	fmt.Fprintf(out, "Generating JSON lines report...\n")
+	if err := reporter.WriteJSONLReport(report, w); err != nil {
+		return fmt.Errorf("error generating JSON lines report: %w", err)
+	}
+
+	if absOutputPath != "" {
+		fmt.Fprintf(out, "📊 JSON lines report saved to: %s\n", absOutputPath)
+	}
+	return nil
+}
+
+func generatePrometheus(report *analyzer.Report, outputPath string) error {
+	if outputPath == "" {
+		outputPath = "code_health_report.prom"
+	}
+
+	w, absOutputPath, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	fmt.Fprintf(out, "Generating Prometheus metrics...\n")
+	if err := reporter.GeneratePrometheusReport(report, w); err != nil {
+		return fmt.Errorf("error generating Prometheus metrics: %w", err)
+	}
+
+	if absOutputPath != "" {
+		fmt.Fprintf(out, "📊 Prometheus metrics saved to: %s\n", absOutputPath)
+	}
+	return nil
+}
+
+func generateMermaid(report *analyzer.Report, outputPath string) error {
+	if outputPath == "" {
+		outputPath = "code_health_graph.md"
+	}
+
+	w, absOutputPath, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	fmt.Fprintf(out, "Generating Mermaid dependency graph...\n")
+	if err := reporter.GenerateMermaidGraph(report, w); err != nil {
+		return fmt.Errorf("error generating Mermaid graph: %w", err)
+	}
+
+	if absOutputPath != "" {
+		fmt.Fprintf(out, "📊 Mermaid dependency graph saved to: %s\n", absOutputPath)
+	}
+	return nil
+}
+
+func generateOpenMetrics(report *analyzer.Report, outputPath string) error {
+	if outputPath == "" {
+		outputPath = "code_health_report.om"
+	}
+
+	w, absOutputPath, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	fmt.Fprintf(out, "Generating OpenMetrics metrics...\n")
+	if err := reporter.GenerateOpenMetricsReport(report, w); err != nil {
+		return fmt.Errorf("error generating OpenMetrics metrics: %w", err)
+	}
+
+	if absOutputPath != "" {
+		fmt.Fprintf(out, "📊 OpenMetrics metrics saved to: %s\n", absOutputPath)
+	}
+	return nil
+}
+
+// generateGitHubAnnotations writes GitHub Actions workflow commands to stdout, where the runner
+// picks them up, or to a file if an output path is given
+func generateGitHubAnnotations(report *analyzer.Report, outputPath string) error {
+	if outputPath == "" {
+		outputPath = stdoutPath
+	}
+
+	w, absOutputPath, err := openOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := reporter.GenerateGitHubAnnotations(report, w); err != nil {
+		return fmt.Errorf("error generating GitHub annotations: %w", err)
+	}
+
+	if absOutputPath != "" {
+		fmt.Fprintf(out, "📊 GitHub annotations saved to: %s\n", absOutputPath)
+	}
+	return nil
+}
+
+// openOutput opens a report destination: stdout for stdoutPath, otherwise the file at outputPath,
+// whose absolute path is returned for messages (empty for stdout). Closing stdout does nothing.
+func openOutput(outputPath string) (io.WriteCloser, string, error) {
+	if outputPath == stdoutPath {
+		return nopWriteCloser{os.Stdout}, "", nil
+	}
+
+	absOutputPath, err := filepath.Abs(outputPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("error resolving output path: %w", err)
+	}
+
+	file, err := os.Create(absOutputPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating output file: %w", err)
+	}
+	return file, absOutputPath, nil
+}
+
+// nopWriteCloser is a writer whose Close does nothing, so stdout stays open after a report
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// stringList is a flag that can be given several times, collecting every value
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	if strings.Trim(value, "/") == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	*l = append(*l, value)
 	return nil
 }
 
 func printSummary(report *analyzer.Report) {
-	fmt.Printf("\n✅ Analysis complete!\n")
-	fmt.Printf("   Analyzed packages: %d\n", len(report.Packages))
+	fmt.Fprintf(out, "\n✅ Analysis complete!\n")
+	fmt.Fprintf(out, "   Analyzed packages: %d\n", len(report.Packages))
 
 	totalStructs := 0
 	totalFunctions := 0
@@ -144,9 +564,26 @@ func printSummary(report *analyzer.Report) {
 		totalFunctions += len(pkg.Functions)
 	}
 
-	fmt.Printf("   Analyzed structs: %d\n", totalStructs)
-	fmt.Printf("   Analyzed functions: %d\n", totalFunctions)
-	fmt.Println()
+	fmt.Fprintf(out, "   Analyzed structs: %d\n", totalStructs)
+	fmt.Fprintf(out, "   Analyzed functions: %d\n", totalFunctions)
+	fmt.Fprintf(out, "   Health score: %.1f / 100\n", report.HealthScore)
+	fmt.Fprintln(out)
+
+	if len(report.TopOffenders) > 0 {
+		fmt.Fprintf(out, "   Top offenders:\n")
+		for i, o := range report.TopOffenders {
+			fmt.Fprintf(out, "   %2d. %s (%s, score %.2f)\n", i+1, o.Name, o.Reason, o.Score)
+		}
+		fmt.Fprintln(out)
+	}
+
+	if len(report.Hotspots) > 0 {
+		fmt.Fprintf(out, "   Hotspots (complexity x churn):\n")
+		for i, h := range report.Hotspots {
+			fmt.Fprintf(out, "   %2d. %s (complexity %d x %d commits = %d)\n", i+1, h.Name, h.Complexity, h.Churn, h.Score)
+		}
+		fmt.Fprintln(out)
+	}
 }
 
 func printUsage() {
@@ -154,18 +591,88 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Usage:")
 	fmt.Println("  go-code-health-analyzer [options] <target-directory>")
+	fmt.Println("  go-code-health-analyzer -remote [options] <module-path>[@version]")
+	fmt.Println("  go-code-health-analyzer -from-json <report.json> [options]")
+	fmt.Println("  go-code-health-analyzer prepare -cache-dir <dir> [options] <target-directory>...")
+	fmt.Println("  go-code-health-analyzer diff [options] <old-report.json> <new-report.json>")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  -format string")
-	fmt.Println("        Output format: html, json, or both (default: html)")
+	fmt.Println("        Output format: html, json, jsonl, both, prometheus, openmetrics, github, or mermaid (default: html)")
 	fmt.Println("  -output string")
-	fmt.Println("        Output file path (default: code_health_report.html or .json)")
+	fmt.Println("        Output file path (default: code_health_report.html, .json, .jsonl, .prom, or .om;")
+	fmt.Println("        code_health_graph.md for mermaid; stdout for github)")
+	fmt.Println("        Use - to write the report to stdout (messages then go to stderr; not with both)")
+	fmt.Println("  -baseline string")
+	fmt.Println("        Baseline JSON report to compare against; writes code_health_diff.html")
+	fmt.Println("        (or code_health_diff.json with -format json)")
+	fmt.Println("  -cache-dir string")
+	fmt.Println("        Directory caching analysis results: a target whose Go files, module files, and")
+	fmt.Println("        options are unchanged is loaded from the cache instead of analyzed again")
+	fmt.Println("        (churn is cached with the rest of the report)")
+	fmt.Println("  -churn-days int")
+	fmt.Println("        Count the git commits touching each file over this many days and rank")
+	fmt.Println("        functions by complexity x churn (default: 0, disabled; skipped without git)")
+	fmt.Println("  -config string")
+	fmt.Println("        Configuration file path (default: .codehealth.json in the target directory)")
 	fmt.Println("  -exclude string")
-	fmt.Println("        Comma-separated list of directory names to exclude")
+	fmt.Println("        Comma-separated list of directory names, paths, or globs to exclude")
+	fmt.Println("        (e.g. build, internal/**, *.pb); prefix with regex: for a regular expression")
 	fmt.Println("        Default excludes: vendor, testdata (always excluded)")
+	fmt.Println("  -experimental")
+	fmt.Println("        Enable experimental diagnostics (map and struct fields accessed without a lock)")
+	fmt.Println("        These are syntactic heuristics without type information: goroutines are")
+	fmt.Println("        matched only when the receiver's type is spelled out (recv.M, v := &T{}),")
+	fmt.Println("        locks by source order rather than control flow; expect false positives and misses")
+	fmt.Println("  -external-coupling string")
+	fmt.Println("        External imports counted in function efferent coupling (Ce): all, stdlib,")
+	fmt.Println("        or thirdparty; project imports always count (default: all)")
+	fmt.Println("  -fail-on string")
+	fmt.Println("        Exit with status 1 if diagnostics at or above this severity exist:")
+	fmt.Println("        none, warning, or critical (default: none)")
+	fmt.Println("  -from-json string")
+	fmt.Println("        Render a JSON report saved earlier (-format json) in the -format output")
+	fmt.Println("        without analyzing again; takes no target, and analysis options are ignored")
+	fmt.Println("  -include-generated")
+	fmt.Println("        Measure generated files (marked \"// Code generated ... DO NOT EDIT.\"),")
+	fmt.Println("        which are skipped by default")
+	fmt.Println("  -include-tests")
+	fmt.Println("        Measure _test.go files too; results from test files are marked is_test")
+	fmt.Println("        External test packages (package foo_test) are reported as <path>_test")
+	fmt.Println("  -internal-prefix string")
+	fmt.Println("        Import path prefix treated as internal in addition to the project's modules")
+	fmt.Println("        (e.g. github.com/org); repeat the flag for several prefixes, which are added")
+	fmt.Println("        to internal_prefixes from the configuration file")
+	fmt.Println("  -lcom-transitive")
+	fmt.Println("        In LCOM4, connect a method to the fields used by the same-struct methods it calls")
+	fmt.Println("  -loc-mode string")
+	fmt.Println("        How total_loc and avg_func_loc are counted: physical (every line), source")
+	fmt.Println("        (lines containing code), or statements (statements in function bodies)")
+	fmt.Println("        (default: physical)")
+	fmt.Println("  -perf-hints")
+	fmt.Println("        Enable heuristic performance diagnostics (allocations inside loops)")
+	fmt.Println("  -quiet")
+	fmt.Println("        Print nothing but errors; reports are still written and -fail-on still")
+	fmt.Println("        sets the exit status (github annotations on stdout are not affected)")
+	fmt.Println("  -remote")
+	fmt.Println("        Treat the target as a module path with an optional @version (default: @latest),")
+	fmt.Println("        download it into the module cache with go mod download, and analyze its source")
+	fmt.Println("  -seed int")
+	fmt.Println("        Seed for the PCA power iteration used in field clustering")
+	fmt.Println("        Results are deterministic for a given seed (default: 0, fixed start vector)")
+	fmt.Println("  -top int")
+	fmt.Println("        Number of entries in the ranked list of worst functions, structs, and critical")
+	fmt.Println("        diagnostics shown in the reports and the summary (default: 10, 0 = none)")
+	fmt.Println("  -typecheck")
+	fmt.Println("        Resolve function call targets with type information for afferent coupling")
+	fmt.Println("        Packages that fail to type-check fall back to AST matching")
+	fmt.Println("  -verbose")
+	fmt.Println("        Report progress on stderr: directories being parsed, packages found,")
+	fmt.Println("        and the time taken by each phase (parse, LCOM4, complexity, coupling, diagnostics)")
 	fmt.Println()
 	fmt.Println("Arguments:")
 	fmt.Println("  target-directory  Path to the Go project directory to analyze")
+	fmt.Println("  module-path       Module to download and analyze with -remote (e.g. github.com/user/repo@v1.2.3)")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  # Generate HTML report (default)")
@@ -174,9 +681,43 @@ func printUsage() {
 	fmt.Println("  # Generate JSON report")
 	fmt.Println("  go-code-health-analyzer -format json ./myproject")
 	fmt.Println()
+	fmt.Println("  # Stream results as JSON lines (one object per line) for very large projects")
+	fmt.Println("  go-code-health-analyzer -format jsonl ./myproject")
+	fmt.Println()
+	fmt.Println("  # Render the HTML report from a saved JSON report")
+	fmt.Println("  go-code-health-analyzer -from-json report.json -output report.html")
+	fmt.Println()
+	fmt.Println("  # Audit a module without cloning it")
+	fmt.Println("  go-code-health-analyzer -remote github.com/user/repo@latest")
+	fmt.Println()
+	fmt.Println("  # Pipe the JSON report into jq")
+	fmt.Println("  go-code-health-analyzer -format json -output - ./myproject | jq .health_score")
+	fmt.Println()
 	fmt.Println("  # Generate both HTML and JSON reports")
 	fmt.Println("  go-code-health-analyzer -format both ./myproject")
 	fmt.Println()
+	fmt.Println("  # Generate Prometheus metrics for a textfile collector")
+	fmt.Println("  go-code-health-analyzer -format prometheus -output code_health.prom ./myproject")
+	fmt.Println()
+	fmt.Println("  # Warm a cache once, then let parallel CI jobs reuse it")
+	fmt.Println("  go-code-health-analyzer prepare -cache-dir .codehealth-cache ./myproject")
+	fmt.Println("  go-code-health-analyzer -cache-dir .codehealth-cache -format json ./myproject")
+	fmt.Println()
+	fmt.Println("  # Compare two JSON reports")
+	fmt.Println("  go-code-health-analyzer diff old.json new.json -format markdown")
+	fmt.Println()
+	fmt.Println("  # Generate OpenMetrics with exemplars linking complexity to source locations")
+	fmt.Println("  go-code-health-analyzer -format openmetrics ./myproject")
+	fmt.Println()
+	fmt.Println("  # Annotate a pull request from a GitHub Actions step")
+	fmt.Println("  go-code-health-analyzer -format github .")
+	fmt.Println()
+	fmt.Println("  # Draw the package dependency graph as a Mermaid diagram for Markdown")
+	fmt.Println("  go-code-health-analyzer -format mermaid ./myproject")
+	fmt.Println()
+	fmt.Println("  # Fail a CI job on critical diagnostics")
+	fmt.Println("  go-code-health-analyzer -fail-on critical ./myproject")
+	fmt.Println()
 	fmt.Println("  # Exclude specific directories")
 	fmt.Println("  go-code-health-analyzer -exclude \"build,dist,tmp\" ./myproject")
 	fmt.Println()
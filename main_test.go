@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+// binaryPath is the analyzer built once for the command line tests
+var binaryPath string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "go-code-health-analyzer")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	binaryPath = filepath.Join(dir, "go-code-health-analyzer")
+	if out, err := exec.Command("go", "build", "-o", binaryPath, ".").CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build the analyzer: %v\n%s", err, out)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// cliResult is the outcome of one run of the analyzer
+type cliResult struct {
+	stdout   string
+	stderr   string
+	exitCode int
+}
+
+// runCLI runs the analyzer in dir with the given arguments
+func runCLI(t *testing.T, dir string, args ...string) cliResult {
+	t.Helper()
+
+	cmd := exec.Command(binaryPath, args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := cliResult{}
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("failed to run the analyzer: %v", err)
+		}
+		result.exitCode = exitErr.ExitCode()
+	}
+	result.stdout = stdout.String()
+	result.stderr = stderr.String()
+	return result
+}
+
+// writeProject writes files (relative path to content) under a temporary directory and returns it.
+// A go.mod for module example.com/app is added unless the files include one.
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if _, ok := files["go.mod"]; !ok {
+		files["go.mod"] = "module example.com/app\n\ngo 1.24\n"
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFailOn(t *testing.T) {
+	// Two packages importing each other form a cyclic dependency, a Critical diagnostic
+	cyclic := writeProject(t, map[string]string{
+		"a/a.go": "package a\n\nimport \"example.com/app/b\"\n\nfunc A() int { return b.B() }\n",
+		"b/b.go": "package b\n\nimport \"example.com/app/a\"\n\nfunc B() int { return a.A() }\n",
+	})
+	clean := writeProject(t, map[string]string{
+		"a/a.go": "package a\n\n// A returns one\nfunc A() int { return 1 }\n",
+	})
+
+	tests := []struct {
+		name     string
+		dir      string
+		failOn   string
+		wantCode int
+	}{
+		{"none ignores critical diagnostics", cyclic, "none", 0},
+		{"critical fails on a cycle", cyclic, "critical", 1},
+		{"warning fails on a cycle", cyclic, "warning", 1},
+		{"critical passes a clean project", clean, "critical", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "report.json")
+			result := runCLI(t, tt.dir, "-format", "json", "-output", output, "-fail-on", tt.failOn, ".")
+			if result.exitCode != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d\nstderr: %s", result.exitCode, tt.wantCode, result.stderr)
+			}
+			if _, err := os.Stat(output); err != nil {
+				t.Errorf("report not written before exiting: %v", err)
+			}
+			if tt.wantCode != 0 && !strings.Contains(result.stderr, "at or above "+tt.failOn+" severity") {
+				t.Errorf("stderr does not report the failing count: %s", result.stderr)
+			}
+		})
+	}
+}
+
+func TestFailOnRejectsUnknownSeverity(t *testing.T) {
+	result := runCLI(t, t.TempDir(), "-fail-on", "fatal", ".")
+	if result.exitCode != 1 || !strings.Contains(result.stderr, "Invalid -fail-on value") {
+		t.Errorf("exit code = %d, stderr = %q, want 1 and an invalid value error", result.exitCode, result.stderr)
+	}
+}
+
+func TestVerboseLogsProgressToStderr(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"app/app.go": "package app\n\nfunc Run() {}\n",
+	})
+	output := filepath.Join(t.TempDir(), "report.json")
+
+	quiet := runCLI(t, dir, "-format", "json", "-output", output, ".")
+	verbose := runCLI(t, dir, "-verbose", "-format", "json", "-output", output, ".")
+	if quiet.exitCode != 0 || verbose.exitCode != 0 {
+		t.Fatalf("exit codes = %d, %d, want 0\nstderr: %s", quiet.exitCode, verbose.exitCode, verbose.stderr)
+	}
+	if strings.Contains(quiet.stderr, "Parsing took") {
+		t.Errorf("progress logged without -verbose: %s", quiet.stderr)
+	}
+	if !strings.Contains(verbose.stderr, "Parsing app") || !strings.Contains(verbose.stderr, "Diagnostics took") {
+		t.Errorf("-verbose stderr is missing progress: %s", verbose.stderr)
+	}
+}
+
+func TestQuietSuppressesStdout(t *testing.T) {
+	// An import cycle gives a Critical diagnostic for -fail-on
+	dir := writeProject(t, map[string]string{
+		"a/a.go": "package a\n\nimport \"example.com/app/b\"\n\nfunc A() int { return b.B() }\n",
+		"b/b.go": "package b\n\nimport \"example.com/app/a\"\n\nfunc B() int { return a.A() }\n",
+	})
+	output := filepath.Join(t.TempDir(), "report.json")
+
+	normal := runCLI(t, dir, "-format", "json", "-output", output, ".")
+	if normal.exitCode != 0 || normal.stdout == "" {
+		t.Fatalf("default run: exit code %d, stdout %q, want 0 and progress output", normal.exitCode, normal.stdout)
+	}
+
+	os.Remove(output)
+	quiet := runCLI(t, dir, "-quiet", "-format", "json", "-output", output, "-fail-on", "critical", ".")
+	if quiet.stdout != "" {
+		t.Errorf("-quiet printed to stdout: %q", quiet.stdout)
+	}
+	if quiet.exitCode != 1 || !strings.Contains(quiet.stderr, "at or above critical severity") {
+		t.Errorf("-quiet -fail-on: exit code %d, stderr %q, want 1 and the failure message", quiet.exitCode, quiet.stderr)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("-quiet did not write the report: %v", err)
+	}
+
+	missing := runCLI(t, dir, "-quiet", "-format", "json", "-output", output, "does-not-exist")
+	if missing.exitCode == 0 || missing.stdout != "" || missing.stderr == "" {
+		t.Errorf("-quiet on a missing path: exit code %d, stdout %q, stderr %q, want an error on stderr only", missing.exitCode, missing.stdout, missing.stderr)
+	}
+}
+
+func TestOutputToStdout(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"app/app.go": "package app\n\nfunc Run() {}\n",
+	})
+
+	result := runCLI(t, dir, "-format", "json", "-output", "-", ".")
+	if result.exitCode != 0 {
+		t.Fatalf("exit code = %d\nstderr: %s", result.exitCode, result.stderr)
+	}
+	var report analyzer.Report
+	if err := json.Unmarshal([]byte(result.stdout), &report); err != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", err, result.stdout)
+	}
+	if len(report.Packages) != 1 || report.Packages[0].Path != "app" {
+		t.Errorf("packages = %+v, want app", report.Packages)
+	}
+	if strings.Contains(result.stderr, "saved to") {
+		t.Errorf("stderr mentions a saved file: %s", result.stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "-")); !os.IsNotExist(err) {
+		t.Errorf("a file named - was written")
+	}
+
+	both := runCLI(t, dir, "-format", "both", "-output", "-", ".")
+	if both.exitCode != 1 || !strings.Contains(both.stderr, "cannot be used with -format both") {
+		t.Errorf("-format both: exit code %d, stderr %q, want 1 and an error", both.exitCode, both.stderr)
+	}
+}
+
+func TestInternalPrefixFlag(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"app/app.go": "package app\n\nimport (\n\t\"github.com/org/a\"\n\t\"github.com/org/b\"\n)\n\nfunc Run() { a.A(); b.B() }\n",
+	})
+
+	efferent := func(args ...string) int {
+		t.Helper()
+		result := runCLI(t, dir, append(args, "-format", "json", "-output", "-", ".")...)
+		if result.exitCode != 0 {
+			t.Fatalf("exit code = %d\nstderr: %s", result.exitCode, result.stderr)
+		}
+		var report analyzer.Report
+		if err := json.Unmarshal([]byte(result.stdout), &report); err != nil || len(report.Packages) != 1 {
+			t.Fatalf("unexpected report: %v\n%s", err, result.stdout)
+		}
+		return report.Packages[0].Efferent
+	}
+
+	if got := efferent(); got != 0 {
+		t.Errorf("Ce without -internal-prefix = %d, want 0", got)
+	}
+	if got := efferent("-internal-prefix", "github.com/org/a", "-internal-prefix", "github.com/org/b"); got != 2 {
+		t.Errorf("Ce with both prefixes = %d, want 2", got)
+	}
+}
+
+func TestFromJSONMatchesDirectRender(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"store/store.go": `package store
+
+type Store struct {
+	items map[string]int
+	log   []string
+}
+
+func (s *Store) Get(k string) int { return s.items[k] }
+
+func (s *Store) Record(msg string) { s.log = append(s.log, msg) }
+`,
+		"app/app.go": "package app\n\nimport \"example.com/app/store\"\n\nfunc Run(s *store.Store) int {\n\tif s == nil {\n\t\treturn 0\n\t}\n\treturn s.Get(\"a\")\n}\n",
+	})
+	out := t.TempDir()
+
+	// One analysis written as both HTML and JSON, then the JSON rendered again
+	direct := runCLI(t, dir, "-format", "both", "-output", filepath.Join(out, "direct.html"), ".")
+	if direct.exitCode != 0 {
+		t.Fatalf("analysis failed with exit code %d\nstderr: %s", direct.exitCode, direct.stderr)
+	}
+	rerender := runCLI(t, dir, "-from-json", filepath.Join(out, "direct.json"), "-format", "html", "-output", filepath.Join(out, "rerendered.html"))
+	if rerender.exitCode != 0 {
+		t.Fatalf("-from-json failed with exit code %d\nstderr: %s", rerender.exitCode, rerender.stderr)
+	}
+
+	want, err := os.ReadFile(filepath.Join(out, "direct.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(out, "rerendered.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("HTML rendered from JSON differs from the direct render (%d vs %d bytes)", len(got), len(want))
+	}
+
+	// -from-json takes no target to analyze
+	withTarget := runCLI(t, dir, "-from-json", filepath.Join(out, "direct.json"), ".")
+	if withTarget.exitCode != 1 || !strings.Contains(withTarget.stderr, "takes no target directory") {
+		t.Errorf("exit code = %d, stderr = %q, want 1 and a usage error", withTarget.exitCode, withTarget.stderr)
+	}
+}
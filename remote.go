@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// remoteModule is the part of `go mod download -json` output used to locate a module's source
+type remoteModule struct {
+	Path    string
+	Version string
+	Dir     string // Extracted source in the module cache
+	Error   string
+}
+
+// downloadModule downloads the module named by spec ("path@version", or "path" for @latest) into
+// the module cache with `go mod download` and returns it, Dir holding the extracted source.
+// The go command resolves the version and honors GOPROXY, GOPRIVATE, and GOFLAGS as usual.
+func downloadModule(spec string) (*remoteModule, error) {
+	if spec == "" || strings.HasPrefix(spec, "@") {
+		return nil, fmt.Errorf("invalid module %q: use path@version, e.g. github.com/user/repo@latest", spec)
+	}
+	if !strings.Contains(spec, "@") {
+		spec += "@latest"
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return nil, fmt.Errorf("the go command is required for -remote: %w", err)
+	}
+
+	// Run outside any module so the download cannot touch a local go.mod or go.sum
+	cmd := exec.Command(goBin, "mod", "download", "-json", spec)
+	cmd.Dir = os.TempDir()
+	cmd.Env = append(os.Environ(), "GO111MODULE=on")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	// The go command reports module errors in the JSON output and other failures on stderr
+	var module remoteModule
+	if err := json.Unmarshal(stdout.Bytes(), &module); err != nil && runErr == nil {
+		return nil, fmt.Errorf("failed to read go mod download output for %s: %w", spec, err)
+	}
+	if module.Error != "" || runErr != nil {
+		message := module.Error
+		if message == "" {
+			message = strings.TrimSpace(stderr.String())
+		}
+		if message == "" {
+			message = runErr.Error()
+		}
+		return nil, fmt.Errorf("failed to download %s: %s%s", spec, message, downloadHint(message))
+	}
+	if module.Dir == "" {
+		return nil, fmt.Errorf("failed to download %s: the go command did not report a source directory", spec)
+	}
+
+	return &module, nil
+}
+
+// downloadHint suggests a fix for common download failures: private repositories and no network
+func downloadHint(message string) string {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "terminal prompts disabled") || strings.Contains(lower, "could not read username") ||
+		strings.Contains(lower, "401") || strings.Contains(lower, "403") || strings.Contains(lower, "410 gone"):
+		return "\n(for a private module, set GOPRIVATE to its path and configure git or .netrc credentials)"
+	case strings.Contains(lower, "dial tcp") || strings.Contains(lower, "no such host") || strings.Contains(lower, "timeout"):
+		return "\n(check the network connection, or set GOPROXY to a reachable proxy)"
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeModuleProxy lays out a file:// module proxy under a temporary directory serving
+// example.com/remote at v1.0.0 and v1.1.0 with the given files, and returns its URL
+func writeModuleProxy(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	const modulePath = "example.com/remote"
+	root := t.TempDir()
+	versionDir := filepath.Join(root, filepath.FromSlash(modulePath), "@v")
+	if err := os.MkdirAll(versionDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	goMod := "module " + modulePath + "\n\ngo 1.24\n"
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(versionDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("list", "v1.0.0\nv1.1.0\n")
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		writeFile(version+".info", `{"Version": "`+version+`", "Time": "2024-01-01T00:00:00Z"}`)
+		writeFile(version+".mod", goMod)
+
+		archive, err := os.Create(filepath.Join(versionDir, version+".zip"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		zw := zip.NewWriter(archive)
+		contents := map[string]string{"go.mod": goMod}
+		for name, content := range files {
+			contents[name] = content
+		}
+		for name, content := range contents {
+			w, err := zw.Create(modulePath + "@" + version + "/" + name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := w.Write([]byte(content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		archive.Close()
+	}
+
+	return "file://" + filepath.ToSlash(root)
+}
+
+// useModuleProxy points the go command at proxy with an empty module cache and no checksum database
+func useModuleProxy(t *testing.T, proxy string) {
+	t.Setenv("GOPROXY", proxy)
+	t.Setenv("GOSUMDB", "off")
+	t.Setenv("GOMODCACHE", t.TempDir())
+	t.Setenv("GOFLAGS", "-modcacherw")
+}
+
+func TestDownloadModule(t *testing.T) {
+	useModuleProxy(t, writeModuleProxy(t, map[string]string{
+		"lib/lib.go": "package lib\n\nfunc Hello() string { return \"hello\" }\n",
+	}))
+
+	module, err := downloadModule("example.com/remote")
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if module.Version != "v1.1.0" {
+		t.Errorf("version = %q, want v1.1.0 (latest)", module.Version)
+	}
+	if _, err := os.Stat(filepath.Join(module.Dir, "lib", "lib.go")); err != nil {
+		t.Errorf("module source not extracted: %v", err)
+	}
+
+	pinned, err := downloadModule("example.com/remote@v1.0.0")
+	if err != nil || pinned.Version != "v1.0.0" {
+		t.Errorf("pinned download = %+v, %v, want v1.0.0", pinned, err)
+	}
+
+	if _, err := downloadModule("example.com/missing@latest"); err == nil || !strings.Contains(err.Error(), "failed to download example.com/missing@latest") {
+		t.Errorf("error = %v, want a download failure naming the module", err)
+	}
+	if _, err := downloadModule("@v1.0.0"); err == nil || !strings.Contains(err.Error(), "invalid module") {
+		t.Errorf("error = %v, want an invalid module error", err)
+	}
+}
+
+func TestRemoteFlag(t *testing.T) {
+	useModuleProxy(t, writeModuleProxy(t, map[string]string{
+		"lib/lib.go": "package lib\n\nfunc Hello() string { return \"hello\" }\n",
+	}))
+	output := filepath.Join(t.TempDir(), "report.json")
+
+	result := runCLI(t, t.TempDir(), "-remote", "-format", "json", "-output", output, "example.com/remote@v1.0.0")
+	if result.exitCode != 0 {
+		t.Fatalf("exit code = %d\nstderr: %s", result.exitCode, result.stderr)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Packages []struct {
+			Path string `json:"path"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Packages) != 1 || report.Packages[0].Path != "lib" {
+		t.Errorf("packages = %+v, want lib", report.Packages)
+	}
+}
@@ -0,0 +1,193 @@
+package reporter
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+//go:embed diff_template.html
+var diffHTMLTemplate string
+
+// DiffTemplateData holds the data passed to the diff HTML template
+type DiffTemplateData struct {
+	OldPath string
+	NewPath string
+	Diff    *analyzer.ReportDiff
+	// Sections lists the metric change tables in display order
+	Sections []DiffSection
+}
+
+// DiffSection is a titled table of metric changes in the diff HTML report
+type DiffSection struct {
+	Title  string
+	Deltas []analyzer.EntityDelta
+}
+
+// GenerateDiffHTMLReport generates an HTML report describing the changes between two reports
+func GenerateDiffHTMLReport(diff *analyzer.ReportDiff, oldPath, newPath, outputPath string) error {
+	tmpl, err := template.New("diff").Funcs(template.FuncMap{
+		"formatValue": formatMetricValue,
+		"formatDelta": formatMetricDelta,
+		"deltaClass":  deltaClass,
+	}).Parse(diffHTMLTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	data := DiffTemplateData{
+		OldPath: oldPath,
+		NewPath: newPath,
+		Diff:    diff,
+		Sections: []DiffSection{
+			{Title: "Package Changes", Deltas: diff.PackageChanges},
+			{Title: "Struct Changes", Deltas: diff.StructChanges},
+			{Title: "Function Changes", Deltas: diff.FunctionChanges},
+		},
+	}
+	if err := tmpl.Execute(file, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateDiffMarkdownReport generates a Markdown report describing the changes between two reports
+func GenerateDiffMarkdownReport(diff *analyzer.ReportDiff, oldPath, newPath, outputPath string) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# Code Health Diff\n\n")
+	fmt.Fprintf(&buf, "- Old: `%s`\n- New: `%s`\n\n", oldPath, newPath)
+
+	buf.WriteString("## Summary\n\n")
+	buf.WriteString("| Metric | Old | New | Delta |\n|---|---:|---:|---:|\n")
+	for _, m := range diff.Summary {
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s |\n", m.Metric, formatMetricValue(m.Old), formatMetricValue(m.New), formatMetricDelta(m.Delta))
+	}
+
+	writeMarkdownDiagnostics(&buf, "New Diagnostics", diff.NewDiagnostics)
+	writeMarkdownDiagnostics(&buf, "Fixed Diagnostics", diff.FixedDiagnostics)
+
+	writeMarkdownList(&buf, "Added Packages", diff.AddedPackages)
+	writeMarkdownList(&buf, "Removed Packages", diff.RemovedPackages)
+	writeMarkdownList(&buf, "Moved Packages", formatPackageMoves(diff.MovedPackages))
+
+	writeMarkdownDeltas(&buf, "Package Changes", diff.PackageChanges)
+	writeMarkdownDeltas(&buf, "Struct Changes", diff.StructChanges)
+	writeMarkdownDeltas(&buf, "Function Changes", diff.FunctionChanges)
+
+	writeMarkdownList(&buf, "Added Functions", diff.AddedFunctions)
+	writeMarkdownList(&buf, "Removed Functions", diff.RemovedFunctions)
+
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateDiffJSONReport writes the diff as indented JSON
+func GenerateDiffJSONReport(diff *analyzer.ReportDiff, outputPath string) error {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	if err := os.WriteFile(outputPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	return nil
+}
+
+// writeMarkdownDiagnostics writes a section listing diagnostics
+func writeMarkdownDiagnostics(buf *bytes.Buffer, title string, diagnostics []analyzer.DiagnosticResult) {
+	fmt.Fprintf(buf, "\n## %s (%d)\n\n", title, len(diagnostics))
+	if len(diagnostics) == 0 {
+		buf.WriteString("None.\n")
+		return
+	}
+	for _, d := range diagnostics {
+		fmt.Fprintf(buf, "- **[%s] %s** `%s`: %s\n", d.Severity, d.Type, d.TargetName, d.Message)
+	}
+}
+
+// formatPackageMoves formats package moves as "old -> new"
+func formatPackageMoves(moves []analyzer.PackageMove) []string {
+	var items []string
+	for _, move := range moves {
+		items = append(items, fmt.Sprintf("%s -> %s", move.From, move.To))
+	}
+	return items
+}
+
+// writeMarkdownList writes a section listing names, skipping it when empty
+func writeMarkdownList(buf *bytes.Buffer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "\n## %s (%d)\n\n", title, len(items))
+	for _, item := range items {
+		fmt.Fprintf(buf, "- `%s`\n", item)
+	}
+}
+
+// writeMarkdownDeltas writes a table of metric changes, skipping it when empty
+func writeMarkdownDeltas(buf *bytes.Buffer, title string, deltas []analyzer.EntityDelta) {
+	if len(deltas) == 0 {
+		return
+	}
+	fmt.Fprintf(buf, "\n## %s (%d)\n\n", title, len(deltas))
+	buf.WriteString("| Target | Metric | Old | New | Delta |\n|---|---|---:|---:|---:|\n")
+	for _, d := range deltas {
+		target := d.Package
+		if target == "" {
+			target = "."
+		}
+		if d.Name != "" {
+			target += "." + d.Name
+		}
+		for _, m := range d.Changes {
+			fmt.Fprintf(buf, "| `%s` | %s | %s | %s | %s |\n", target, m.Metric, formatMetricValue(m.Old), formatMetricValue(m.New), formatMetricDelta(m.Delta))
+		}
+	}
+}
+
+// formatMetricValue formats whole numbers without decimals and others with two
+func formatMetricValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// formatMetricDelta formats a delta with an explicit sign
+func formatMetricDelta(v float64) string {
+	if v > 0 {
+		return "+" + formatMetricValue(v)
+	}
+	return formatMetricValue(v)
+}
+
+// deltaClass returns the CSS class for a delta. Most compared metrics (complexity, coupling,
+// LCOM4, issue counts) are better when lower, so increases are highlighted in red.
+func deltaClass(v float64) string {
+	if v > 0 {
+		return "text-red-600"
+	} else if v < 0 {
+		return "text-green-600"
+	}
+	return "text-gray-500"
+}
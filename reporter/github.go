@@ -0,0 +1,97 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+// GenerateGitHubAnnotations writes each diagnostic as a GitHub Actions workflow command
+// (::error, ::warning, or ::notice), so findings appear as inline annotations on pull requests.
+// File paths are made relative to GITHUB_WORKSPACE (or the working directory when it is unset).
+func GenerateGitHubAnnotations(report *analyzer.Report, w io.Writer) error {
+	workspace := os.Getenv("GITHUB_WORKSPACE")
+	if workspace == "" {
+		if wd, err := os.Getwd(); err == nil {
+			workspace = wd
+		}
+	}
+
+	// Struct and function diagnostics link to their declaration; use it to locate the line
+	locations := relatedLocations(report)
+
+	var buf bytes.Buffer
+	for _, d := range report.Diagnostics {
+		var properties []string
+
+		if d.Evidence != nil {
+			if files := d.Evidence.SourceFiles(); len(files) > 0 {
+				properties = append(properties, "file="+escapeGitHubProperty(workspaceRelPath(workspace, files[0])))
+				if location, ok := locations[d.RelatedPath]; ok && location.Line > 0 {
+					properties = append(properties, fmt.Sprintf("line=%d", location.Line))
+					if location.EndLine > location.Line {
+						properties = append(properties, fmt.Sprintf("endLine=%d", location.EndLine))
+					}
+				}
+			}
+		}
+		properties = append(properties, "title="+escapeGitHubProperty(fmt.Sprintf("%s: %s", d.Type, d.TargetName)))
+
+		fmt.Fprintf(&buf, "::%s %s::%s\n", gitHubCommand(d.Severity), strings.Join(properties, ","), escapeGitHubData(d.Message))
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+
+	return nil
+}
+
+// gitHubCommand maps a diagnostic severity to a workflow command
+func gitHubCommand(severity string) string {
+	switch severity {
+	case "Critical":
+		return "error"
+	case "Warning":
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// workspaceRelPath returns a slash-separated path relative to the workspace, or the path unchanged
+// if it lies outside of it
+func workspaceRelPath(workspace string, filePath string) string {
+	if workspace == "" {
+		return filepath.ToSlash(filePath)
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.ToSlash(filePath)
+	}
+	rel, err := filepath.Rel(workspace, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(filePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// escapeGitHubData escapes a workflow command message
+func escapeGitHubData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeGitHubProperty escapes a workflow command property value
+func escapeGitHubProperty(s string) string {
+	s = escapeGitHubData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
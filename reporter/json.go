@@ -3,6 +3,7 @@ package reporter
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
@@ -17,8 +18,13 @@ func GenerateJSONReport(report *analyzer.Report, outputPath string) error {
 	}
 	defer file.Close()
 
+	return WriteJSONReport(report, file)
+}
+
+// WriteJSONReport writes the JSON report to w, e.g. stdout for piping into jq
+func WriteJSONReport(report *analyzer.Report, w io.Writer) error {
 	// Create JSON encoder with indentation for readability
-	encoder := json.NewEncoder(file)
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 
 	// Encode report to JSON
@@ -28,3 +34,20 @@ func GenerateJSONReport(report *analyzer.Report, outputPath string) error {
 
 	return nil
 }
+
+// LoadJSONReport reads a report previously written by GenerateJSONReport.
+// Reports written before schema versioning have no schema_version or metadata and load with those fields empty;
+// unknown fields from newer versions are ignored.
+func LoadJSONReport(path string) (*analyzer.Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var report analyzer.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode report %s: %w", path, err)
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,85 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+// analyzeProject writes files (relative path to content) plus a go.mod under a temporary
+// directory and analyzes it
+func analyzeProject(t *testing.T, files map[string]string) *analyzer.Report {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.24\n"
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := analyzer.Analyze(dir, nil)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	return report
+}
+
+func TestJSONReportMetadata(t *testing.T) {
+	report := analyzeProject(t, map[string]string{
+		"app/app.go": "package app\n\nfunc Run() {}\n",
+	})
+
+	var buf bytes.Buffer
+	if err := WriteJSONReport(report, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var fields struct {
+		SchemaVersion   string `json:"schema_version"`
+		GeneratedAt     string `json:"generated_at"`
+		AnalyzerVersion string `json:"analyzer_version"`
+		TargetPath      string `json:"target_path"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if fields.SchemaVersion != analyzer.SchemaVersion {
+		t.Errorf("schema_version = %q, want %q", fields.SchemaVersion, analyzer.SchemaVersion)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, fields.GeneratedAt); err != nil {
+		t.Errorf("generated_at %q is not a timestamp: %v", fields.GeneratedAt, err)
+	}
+	if fields.AnalyzerVersion == "" || fields.TargetPath == "" {
+		t.Errorf("analyzer_version = %q, target_path = %q, want both set", fields.AnalyzerVersion, fields.TargetPath)
+	}
+}
+
+func TestLoadJSONReportWithoutSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.json")
+	legacy := `{"packages": [{"name": "app", "path": "app"}], "added_later": true}`
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := LoadJSONReport(path)
+	if err != nil {
+		t.Fatalf("failed to load a report without schema_version: %v", err)
+	}
+	if report.SchemaVersion != "" || !report.GeneratedAt.IsZero() {
+		t.Errorf("metadata = %q, %v, want empty", report.SchemaVersion, report.GeneratedAt)
+	}
+	if len(report.Packages) != 1 || report.Packages[0].Name != "app" {
+		t.Errorf("packages = %+v, want app", report.Packages)
+	}
+}
@@ -0,0 +1,163 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+// Record kinds of the JSON-lines report, in the order they are written
+const (
+	JSONLKindHeader     = "header"
+	JSONLKindOffender   = "offender"
+	JSONLKindHotspot    = "hotspot"
+	JSONLKindPackage    = "package"
+	JSONLKindStruct     = "struct"
+	JSONLKindFunction   = "function"
+	JSONLKindDiagnostic = "diagnostic"
+)
+
+// jsonlHeader is the first line of the JSON-lines report: report metadata and project-wide totals
+type jsonlHeader struct {
+	Kind            string    `json:"kind"`
+	SchemaVersion   string    `json:"schema_version"`
+	GeneratedAt     time.Time `json:"generated_at"`
+	AnalyzerVersion string    `json:"analyzer_version"`
+	TargetPath      string    `json:"target_path"`
+	TotalLoC        int       `json:"total_loc"`
+	HealthScore     float64   `json:"health_score"`
+	PackageCount    int       `json:"package_count"`
+	StructCount     int       `json:"struct_count"`
+	FunctionCount   int       `json:"function_count"`
+	DiagnosticCount int       `json:"diagnostic_count"`
+}
+
+// jsonlOffender is a line of the ranked top offenders list, in rank order
+type jsonlOffender struct {
+	Kind string `json:"kind"`
+	analyzer.Offender
+}
+
+// jsonlHotspot is a line of the complexity × churn ranking, in rank order
+type jsonlHotspot struct {
+	Kind string `json:"kind"`
+	analyzer.Hotspot
+}
+
+// jsonlPackage is a package line. Its structs and functions are written as lines of their own,
+// so the shadowing fields leave them out of the package line.
+type jsonlPackage struct {
+	Kind string `json:"kind"`
+	analyzer.PackageResult
+	Structs   []analyzer.StructResult   `json:"structs,omitempty"`
+	Functions []analyzer.FunctionResult `json:"functions,omitempty"`
+}
+
+// jsonlStruct is a struct line, tagged with the path of its package
+type jsonlStruct struct {
+	Kind    string `json:"kind"`
+	Package string `json:"package"`
+	analyzer.StructResult
+}
+
+// jsonlFunction is a function line, tagged with the path of its package
+type jsonlFunction struct {
+	Kind    string `json:"kind"`
+	Package string `json:"package"`
+	analyzer.FunctionResult
+}
+
+// jsonlDiagnostic is a diagnostic line
+type jsonlDiagnostic struct {
+	Kind string `json:"kind"`
+	analyzer.DiagnosticResult
+}
+
+// GenerateJSONLReport writes the analysis results as JSON lines: one compact JSON object per line,
+// each tagged with a "kind" field. The header line comes first, then the top offenders and the hotspots in
+// rank order, then every package followed by its structs and functions, then the diagnostics. Consumers can
+// process the file line by line instead of decoding one large document.
+func GenerateJSONLReport(report *analyzer.Report, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	return WriteJSONLReport(report, file)
+}
+
+// WriteJSONLReport writes the JSON-lines report to w (see GenerateJSONLReport)
+func WriteJSONLReport(report *analyzer.Report, out io.Writer) error {
+	w := bufio.NewWriter(out)
+	encoder := json.NewEncoder(w)
+
+	if err := encoder.Encode(newJSONLHeader(report)); err != nil {
+		return fmt.Errorf("failed to encode header: %w", err)
+	}
+
+	for _, o := range report.TopOffenders {
+		if err := encoder.Encode(jsonlOffender{Kind: JSONLKindOffender, Offender: o}); err != nil {
+			return fmt.Errorf("failed to encode offender %s: %w", o.Name, err)
+		}
+	}
+
+	for _, h := range report.Hotspots {
+		if err := encoder.Encode(jsonlHotspot{Kind: JSONLKindHotspot, Hotspot: h}); err != nil {
+			return fmt.Errorf("failed to encode hotspot %s: %w", h.Name, err)
+		}
+	}
+
+	for _, pkg := range report.Packages {
+		if err := encoder.Encode(jsonlPackage{Kind: JSONLKindPackage, PackageResult: pkg}); err != nil {
+			return fmt.Errorf("failed to encode package %s: %w", pkg.Path, err)
+		}
+		for _, s := range pkg.Structs {
+			if err := encoder.Encode(jsonlStruct{Kind: JSONLKindStruct, Package: pkg.Path, StructResult: s}); err != nil {
+				return fmt.Errorf("failed to encode struct %s: %w", s.StructName, err)
+			}
+		}
+		for _, f := range pkg.Functions {
+			if err := encoder.Encode(jsonlFunction{Kind: JSONLKindFunction, Package: pkg.Path, FunctionResult: f}); err != nil {
+				return fmt.Errorf("failed to encode function %s: %w", f.FuncName, err)
+			}
+		}
+	}
+
+	for _, d := range report.Diagnostics {
+		if err := encoder.Encode(jsonlDiagnostic{Kind: JSONLKindDiagnostic, DiagnosticResult: d}); err != nil {
+			return fmt.Errorf("failed to encode diagnostic %s: %w", d.TargetName, err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return nil
+}
+
+// newJSONLHeader returns the header line with the record counts of each kind
+func newJSONLHeader(report *analyzer.Report) jsonlHeader {
+	header := jsonlHeader{
+		Kind:            JSONLKindHeader,
+		SchemaVersion:   report.SchemaVersion,
+		GeneratedAt:     report.GeneratedAt,
+		AnalyzerVersion: report.AnalyzerVersion,
+		TargetPath:      report.TargetPath,
+		TotalLoC:        report.TotalLoC,
+		HealthScore:     report.HealthScore,
+		PackageCount:    len(report.Packages),
+		DiagnosticCount: len(report.Diagnostics),
+	}
+	for _, pkg := range report.Packages {
+		header.StructCount += len(pkg.Structs)
+		header.FunctionCount += len(pkg.Functions)
+	}
+	return header
+}
@@ -0,0 +1,94 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+func TestJSONLReportLineByLine(t *testing.T) {
+	report := &analyzer.Report{
+		SchemaVersion: analyzer.SchemaVersion,
+		Packages: []analyzer.PackageResult{
+			{
+				Name:      "app",
+				Path:      "app",
+				Structs:   []analyzer.StructResult{{StructName: "Server"}, {StructName: "Client"}},
+				Functions: []analyzer.FunctionResult{{FuncName: "Run"}},
+			},
+			{
+				Name:      "util",
+				Path:      "util",
+				Functions: []analyzer.FunctionResult{{FuncName: "Trim"}, {FuncName: "Pad"}},
+			},
+		},
+		Diagnostics: []analyzer.DiagnosticResult{
+			{Type: analyzer.DiagnosticLongFunction, TargetName: "app.Run", Severity: "Warning"},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.jsonl")
+	if err := GenerateJSONLReport(report, path); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var header jsonlHeader
+	counts := make(map[string]int)
+	functionsByPackage := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for line := 0; scanner.Scan(); line++ {
+		var record struct {
+			Kind    string `json:"kind"`
+			Package string `json:"package"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %d is not a JSON object: %v", line+1, err)
+		}
+		if line == 0 {
+			if record.Kind != JSONLKindHeader {
+				t.Fatalf("first line kind = %q, want header", record.Kind)
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+				t.Fatal(err)
+			}
+		}
+		counts[record.Kind]++
+		if record.Kind == JSONLKindFunction {
+			functionsByPackage[record.Package]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{
+		JSONLKindHeader:     1,
+		JSONLKindPackage:    2,
+		JSONLKindStruct:     2,
+		JSONLKindFunction:   3,
+		JSONLKindDiagnostic: 1,
+	}
+	for kind, n := range want {
+		if counts[kind] != n {
+			t.Errorf("%s lines = %d, want %d", kind, counts[kind], n)
+		}
+	}
+	if header.PackageCount != 2 || header.StructCount != 2 || header.FunctionCount != 3 || header.DiagnosticCount != 1 {
+		t.Errorf("header counts = %+v, want 2 packages, 2 structs, 3 functions, 1 diagnostic", header)
+	}
+	if header.SchemaVersion != analyzer.SchemaVersion {
+		t.Errorf("header schema_version = %q, want %q", header.SchemaVersion, analyzer.SchemaVersion)
+	}
+	if functionsByPackage["app"] != 1 || functionsByPackage["util"] != 2 {
+		t.Errorf("functions by package = %v, want app:1 util:2", functionsByPackage)
+	}
+}
@@ -0,0 +1,81 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+// GenerateMermaidGraph writes the internal package dependency graph as a fenced Mermaid
+// "graph LR" block, ready to be embedded in Markdown (GitHub renders it as a diagram).
+// Each node is labeled with the package path and its instability; each internal import is one edge.
+func GenerateMermaidGraph(report *analyzer.Report, w io.Writer) error {
+	var buf bytes.Buffer
+	writeMermaidGraph(&buf, report)
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+
+	return nil
+}
+
+// writeMermaidGraph writes the fenced graph block, with nodes and edges sorted by package path
+func writeMermaidGraph(buf *bytes.Buffer, report *analyzer.Report) {
+	packages := make([]analyzer.PackageResult, len(report.Packages))
+	copy(packages, report.Packages)
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+
+	buf.WriteString("```mermaid\n")
+	buf.WriteString("graph LR\n")
+
+	for _, pkg := range packages {
+		label := fmt.Sprintf("%s (I=%.2f)", packageLabel(pkg), pkg.Instability)
+		fmt.Fprintf(buf, "    %s[\"%s\"]\n", mermaidNodeID(pkg.Path), mermaidLabel(label))
+	}
+
+	for _, pkg := range packages {
+		seen := make(map[string]bool)
+		for _, imported := range pkg.InternalImports {
+			if imported == pkg.Path || seen[imported] {
+				continue
+			}
+			seen[imported] = true
+			fmt.Fprintf(buf, "    %s --> %s\n", mermaidNodeID(pkg.Path), mermaidNodeID(imported))
+		}
+	}
+
+	buf.WriteString("```\n")
+}
+
+// mermaidNodeID turns a package path into a valid, unique Mermaid node id.
+// Characters other than ASCII letters and digits are hex-escaped ("a/b_c" becomes "pkg_a_2fb_5fc"),
+// and the prefix keeps ids clear of Mermaid keywords such as "end" and of the root package's id.
+func mermaidNodeID(pkgPath string) string {
+	if pkgPath == "" {
+		return "root"
+	}
+
+	var b strings.Builder
+	b.WriteString("pkg_")
+	for i := 0; i < len(pkgPath); i++ {
+		c := pkgPath[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "_%02x", c)
+	}
+	return b.String()
+}
+
+// mermaidLabel escapes characters that would end a quoted node label
+func mermaidLabel(value string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(value)
+}
@@ -0,0 +1,66 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+func TestMermaidGraphEdges(t *testing.T) {
+	report := &analyzer.Report{
+		Packages: []analyzer.PackageResult{
+			{Name: "app", Path: "", InternalImports: []string{"internal/a"}},
+			{Name: "a", Path: "internal/a", Instability: 0.5, InternalImports: []string{"internal/b", "internal/b", "internal/a"}},
+			{Name: "b", Path: "internal/b", Instability: 0.25, InternalImports: []string{"internal/a"}},
+			{Name: "end", Path: "end"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateMermaidGraph(report, &buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	if !strings.HasPrefix(out, "```mermaid\ngraph LR\n") || !strings.HasSuffix(out, "```\n") {
+		t.Errorf("output is not a fenced graph LR block:\n%s", out)
+	}
+
+	var edges []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "-->") {
+			edges = append(edges, strings.TrimSpace(line))
+		}
+	}
+	// One line per internal edge: duplicates and self-imports dropped, both cycle edges kept
+	want := []string{
+		"root --> pkg_internal_2fa",
+		"pkg_internal_2fa --> pkg_internal_2fb",
+		"pkg_internal_2fb --> pkg_internal_2fa",
+	}
+	if strings.Join(edges, "\n") != strings.Join(want, "\n") {
+		t.Errorf("edges:\n%s\nwant:\n%s", strings.Join(edges, "\n"), strings.Join(want, "\n"))
+	}
+
+	if !strings.Contains(out, `pkg_internal_2fa["internal/a (I=0.50)"]`) {
+		t.Errorf("node label does not carry the instability:\n%s", out)
+	}
+	if !strings.Contains(out, "pkg_end[") {
+		t.Errorf("keyword package name not prefixed into a safe id:\n%s", out)
+	}
+}
+
+func TestMermaidNodeID(t *testing.T) {
+	for pkgPath, want := range map[string]string{
+		"":            "root",
+		"a/b_c":       "pkg_a_2fb_5fc",
+		"a-b":         "pkg_a_2db",
+		"cmd/tool.v2": "pkg_cmd_2ftool_2ev2",
+	} {
+		if got := mermaidNodeID(pkgPath); got != want {
+			t.Errorf("mermaidNodeID(%q) = %q, want %q", pkgPath, got, want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+// complexityBuckets are the upper bounds of the function complexity histogram
+var complexityBuckets = []int{1, 5, 10, 15, 20, 30, 50}
+
+// maxExemplarLabelRunes is the OpenMetrics limit on the combined length of an exemplar's label names and values
+const maxExemplarLabelRunes = 128
+
+// GenerateOpenMetricsReport writes the analysis results in OpenMetrics text format.
+// It contains the same gauges as the Prometheus format, plus a per-package histogram of
+// function complexity whose buckets carry exemplars pointing at a source location (file:line),
+// so dashboards can link from a complexity spike to the code.
+func GenerateOpenMetricsReport(report *analyzer.Report, w io.Writer) error {
+	var buf bytes.Buffer
+	writePrometheusMetrics(&buf, report)
+	writeComplexityHistogram(&buf, report)
+	buf.WriteString("# EOF\n")
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	return nil
+}
+
+// writeComplexityHistogram writes the function complexity histogram with one exemplar per bucket:
+// the most complex function that falls into that bucket
+func writeComplexityHistogram(buf *bytes.Buffer, report *analyzer.Report) {
+	packages := make([]analyzer.PackageResult, len(report.Packages))
+	copy(packages, report.Packages)
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+
+	const name = "code_health_function_complexity"
+	fmt.Fprintf(buf, "# HELP %s Distribution of function cyclomatic complexity.\n", name)
+	fmt.Fprintf(buf, "# TYPE %s histogram\n", name)
+
+	for _, pkg := range packages {
+		if len(pkg.Functions) == 0 {
+			continue
+		}
+		label := promLabel(packageLabel(pkg))
+
+		// Most complex function per bucket (index len(complexityBuckets) is +Inf)
+		exemplars := make([]*analyzer.FunctionResult, len(complexityBuckets)+1)
+		counts := make([]int, len(complexityBuckets)+1)
+		sum := 0
+		for i := range pkg.Functions {
+			f := &pkg.Functions[i]
+			sum += f.Complexity
+
+			bucket := sort.SearchInts(complexityBuckets, f.Complexity)
+			counts[bucket]++
+			if exemplars[bucket] == nil || f.Complexity > exemplars[bucket].Complexity {
+				exemplars[bucket] = f
+			}
+		}
+
+		cumulative := 0
+		for i := range counts {
+			cumulative += counts[i]
+			le := "+Inf"
+			if i < len(complexityBuckets) {
+				le = strconv.Itoa(complexityBuckets[i]) + ".0"
+			}
+			fmt.Fprintf(buf, "%s_bucket{package=%s,le=\"%s\"} %d", name, label, le, cumulative)
+			if exemplars[i] != nil {
+				fmt.Fprintf(buf, " # %s %d", exemplarLabels(pkg, exemplars[i]), exemplars[i].Complexity)
+			}
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "%s_count{package=%s} %d\n", name, label, len(pkg.Functions))
+		fmt.Fprintf(buf, "%s_sum{package=%s} %d\n", name, label, sum)
+	}
+}
+
+// exemplarLabels returns the exemplar label set locating a function's source.
+// The function label is dropped if the set would exceed the OpenMetrics length limit.
+func exemplarLabels(pkg analyzer.PackageResult, f *analyzer.FunctionResult) string {
+	file := filepath.Base(f.FilePath)
+	if pkg.Path != "" {
+		file = pkg.Path + "/" + file
+	}
+	line := strconv.Itoa(f.Line)
+
+	length := utf8.RuneCountInString("file" + file + "line" + line)
+	if length+utf8.RuneCountInString("function"+f.FuncName) <= maxExemplarLabelRunes {
+		return fmt.Sprintf("{file=%s,line=%s,function=%s}", promLabel(file), promLabel(line), promLabel(f.FuncName))
+	}
+	return fmt.Sprintf("{file=%s,line=%s}", promLabel(file), promLabel(line))
+}
@@ -0,0 +1,205 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+// GeneratePrometheusReport writes the analysis results in Prometheus text exposition format,
+// suitable for a Pushgateway or the node_exporter textfile collector
+func GeneratePrometheusReport(report *analyzer.Report, w io.Writer) error {
+	var buf bytes.Buffer
+	writePrometheusMetrics(&buf, report)
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+
+	return nil
+}
+
+// writePrometheusMetrics writes every metric family to the buffer
+func writePrometheusMetrics(buf *bytes.Buffer, report *analyzer.Report) {
+	// Sort packages by path for stable output
+	packages := make([]analyzer.PackageResult, len(report.Packages))
+	copy(packages, report.Packages)
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Path < packages[j].Path
+	})
+
+	// Project-level metrics
+	writeMetricHeader(buf, "code_health_total_loc", "Total lines of code in the project.")
+	fmt.Fprintf(buf, "code_health_total_loc %d\n", report.TotalLoC)
+
+	writeMetricHeader(buf, "code_health_packages", "Number of analyzed packages.")
+	fmt.Fprintf(buf, "code_health_packages %d\n", len(packages))
+
+	// Package-level metrics
+	writeMetricHeader(buf, "code_health_package_loc", "Lines of code in the package.")
+	for _, pkg := range packages {
+		fmt.Fprintf(buf, "code_health_package_loc{package=%s} %d\n", promLabel(packageLabel(pkg)), pkg.TotalLoC)
+	}
+
+	writeMetricHeader(buf, "code_health_package_afferent", "Number of project packages that depend on the package (Ca).")
+	for _, pkg := range packages {
+		fmt.Fprintf(buf, "code_health_package_afferent{package=%s} %d\n", promLabel(packageLabel(pkg)), pkg.Afferent)
+	}
+
+	writeMetricHeader(buf, "code_health_package_efferent", "Number of project packages the package depends on (Ce).")
+	for _, pkg := range packages {
+		fmt.Fprintf(buf, "code_health_package_efferent{package=%s} %d\n", promLabel(packageLabel(pkg)), pkg.Efferent)
+	}
+
+	writeMetricHeader(buf, "code_health_package_instability", "Package instability, Ce / (Ca + Ce).")
+	for _, pkg := range packages {
+		fmt.Fprintf(buf, "code_health_package_instability{package=%s} %g\n", promLabel(packageLabel(pkg)), pkg.Instability)
+	}
+
+	writeMetricHeader(buf, "code_health_package_abstractness", "Share of the package's type declarations that are interfaces (A).")
+	for _, pkg := range packages {
+		fmt.Fprintf(buf, "code_health_package_abstractness{package=%s} %g\n", promLabel(packageLabel(pkg)), pkg.Abstractness)
+	}
+
+	writeMetricHeader(buf, "code_health_package_distance", "Distance from the main sequence, |A + I - 1|.")
+	for _, pkg := range packages {
+		fmt.Fprintf(buf, "code_health_package_distance{package=%s} %g\n", promLabel(packageLabel(pkg)), pkg.Distance)
+	}
+
+	writeMetricHeader(buf, "code_health_package_dependency_depth", "Maximum depth of the package's internal dependency chain.")
+	for _, pkg := range packages {
+		fmt.Fprintf(buf, "code_health_package_dependency_depth{package=%s} %d\n", promLabel(packageLabel(pkg)), pkg.DependencyDepth)
+	}
+
+	// Struct-level metrics
+	writeMetricHeader(buf, "code_health_struct_lcom4", "LCOM4 score of the struct.")
+	for _, pkg := range packages {
+		structs := make([]analyzer.StructResult, len(pkg.Structs))
+		copy(structs, pkg.Structs)
+		sort.Slice(structs, func(i, j int) bool {
+			return structs[i].StructName < structs[j].StructName
+		})
+		for _, s := range structs {
+			fmt.Fprintf(buf, "code_health_struct_lcom4{package=%s,struct=%s} %d\n",
+				promLabel(packageLabel(pkg)), promLabel(s.StructName), s.LCOM4Score)
+		}
+	}
+
+	writeMetricHeader(buf, "code_health_struct_rfc", "Response for a class: methods of the struct plus the distinct functions and methods they call.")
+	for _, pkg := range packages {
+		structs := make([]analyzer.StructResult, len(pkg.Structs))
+		copy(structs, pkg.Structs)
+		sort.Slice(structs, func(i, j int) bool {
+			return structs[i].StructName < structs[j].StructName
+		})
+		for _, s := range structs {
+			fmt.Fprintf(buf, "code_health_struct_rfc{package=%s,struct=%s} %d\n",
+				promLabel(packageLabel(pkg)), promLabel(s.StructName), s.RFC)
+		}
+	}
+
+	// Function-level metrics
+	writeMetricHeader(buf, "code_health_complexity", "Cyclomatic complexity of the function.")
+	for _, pkg := range packages {
+		for _, f := range functionSeries(pkg) {
+			fmt.Fprintf(buf, "code_health_complexity{package=%s,file=%s,function=%s} %d\n",
+				promLabel(packageLabel(pkg)), promLabel(f.file), promLabel(f.name), f.complexity)
+		}
+	}
+
+	writeMetricHeader(buf, "code_health_function_loc", "Lines of code in the function body.")
+	for _, pkg := range packages {
+		for _, f := range functionSeries(pkg) {
+			fmt.Fprintf(buf, "code_health_function_loc{package=%s,file=%s,function=%s} %d\n",
+				promLabel(packageLabel(pkg)), promLabel(f.file), promLabel(f.name), f.loc)
+		}
+	}
+
+	// Diagnostic counts by type and severity
+	type diagnosticKey struct {
+		diagType string
+		severity string
+	}
+	counts := make(map[diagnosticKey]int)
+	for _, d := range report.Diagnostics {
+		counts[diagnosticKey{d.Type, d.Severity}]++
+	}
+	keys := make([]diagnosticKey, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].diagType != keys[j].diagType {
+			return keys[i].diagType < keys[j].diagType
+		}
+		return keys[i].severity < keys[j].severity
+	})
+
+	writeMetricHeader(buf, "code_health_diagnostics", "Number of diagnostics by type and severity.")
+	for _, key := range keys {
+		fmt.Fprintf(buf, "code_health_diagnostics{type=%s,severity=%s} %d\n",
+			promLabel(key.diagType), promLabel(key.severity), counts[key])
+	}
+}
+
+// writeMetricHeader writes the HELP and TYPE lines of a gauge metric family
+func writeMetricHeader(buf *bytes.Buffer, name string, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", name)
+}
+
+// promFunction is the label set and values of a function's series
+type promFunction struct {
+	name       string
+	file       string
+	complexity int
+	loc        int
+}
+
+// functionSeries returns the package's functions sorted by name and file, one per label set.
+// Methods are already told apart by their receiver (Type.Method) and same-named functions in
+// different files by the file label; functions sharing both (several init in one file) are merged
+// into one series summing their complexity and LoC, since Prometheus rejects duplicate series.
+func functionSeries(pkg analyzer.PackageResult) []promFunction {
+	index := make(map[promFunction]int)
+	var series []promFunction
+	for _, f := range pkg.Functions {
+		key := promFunction{name: f.FuncName, file: filepath.Base(f.FilePath)}
+		if i, ok := index[key]; ok {
+			series[i].complexity += f.Complexity
+			series[i].loc += f.LoC
+			continue
+		}
+		index[key] = len(series)
+		key.complexity = f.Complexity
+		key.loc = f.LoC
+		series = append(series, key)
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].name != series[j].name {
+			return series[i].name < series[j].name
+		}
+		return series[i].file < series[j].file
+	})
+	return series
+}
+
+// packageLabel returns the label value identifying a package (its path, or "." for the root)
+func packageLabel(pkg analyzer.PackageResult) string {
+	if pkg.Path == "" {
+		return "."
+	}
+	return pkg.Path
+}
+
+// promLabel quotes and escapes a label value for the exposition format
+func promLabel(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+	return `"` + escaped + `"`
+}
@@ -0,0 +1,54 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+func TestPrometheusFunctionSeriesAreUnique(t *testing.T) {
+	report := &analyzer.Report{
+		Packages: []analyzer.PackageResult{{
+			Name: "app",
+			Path: "app",
+			Functions: []analyzer.FunctionResult{
+				{FuncName: "init", FilePath: "/src/app/a.go", Complexity: 2, LoC: 4},
+				{FuncName: "init", FilePath: "/src/app/b.go", Complexity: 3, LoC: 5},
+				{FuncName: "init", FilePath: "/src/app/b.go", Complexity: 4, LoC: 6},
+				{FuncName: "Reader.Close", FilePath: "/src/app/a.go", Complexity: 1, LoC: 2},
+				{FuncName: "Writer.Close", FilePath: "/src/app/a.go", Complexity: 1, LoC: 2},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := GeneratePrometheusReport(report, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		series := line[:strings.LastIndex(line, " ")]
+		if seen[series] {
+			t.Errorf("duplicate series %s", series)
+		}
+		seen[series] = true
+	}
+
+	for _, want := range []string{
+		`code_health_complexity{package="app",file="a.go",function="init"} 2`,
+		`code_health_complexity{package="app",file="b.go",function="init"} 7`,
+		`code_health_function_loc{package="app",file="b.go",function="init"} 11`,
+		`code_health_complexity{package="app",file="a.go",function="Reader.Close"} 1`,
+		`code_health_complexity{package="app",file="a.go",function="Writer.Close"} 1`,
+	} {
+		if !strings.Contains(buf.String(), want+"\n") {
+			t.Errorf("missing %s in:\n%s", want, buf.String())
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"fmt"
 	"html/template"
+	"io"
 	"os"
 	"sort"
 
@@ -15,6 +16,18 @@ var htmlTemplate string
 
 // GenerateHTMLReport generates an interactive HTML report from the analysis results
 func GenerateHTMLReport(report *analyzer.Report, outputPath string) error {
+	// Create output file
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	return WriteHTMLReport(report, file)
+}
+
+// WriteHTMLReport writes the interactive HTML report to w
+func WriteHTMLReport(report *analyzer.Report, w io.Writer) error {
 	// Prepare template data
 	data := prepareTemplateData(report)
 
@@ -36,6 +49,14 @@ func GenerateHTMLReport(report *analyzer.Report, outputPath string) error {
 			}
 			return "red"
 		},
+		"maintainabilityClass": func(mi float64) string {
+			if mi >= 20 {
+				return "green"
+			} else if mi >= 10 {
+				return "yellow"
+			}
+			return "red"
+		},
 		"instabilityClass": func(instability float64) string {
 			if instability <= 0.3 {
 				return "green"
@@ -44,6 +65,9 @@ func GenerateHTMLReport(report *analyzer.Report, outputPath string) error {
 			}
 			return "red"
 		},
+		"packageAnchor":  packageAnchor,
+		"structAnchor":   structAnchor,
+		"functionAnchor": functionAnchor,
 		"add": func(a, b int) int {
 			return a + b
 		},
@@ -67,15 +91,8 @@ func GenerateHTMLReport(report *analyzer.Report, outputPath string) error {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	// Create output file
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer file.Close()
-
 	// Execute template
-	if err := tmpl.Execute(file, data); err != nil {
+	if err := tmpl.Execute(w, data); err != nil {
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
@@ -85,7 +102,11 @@ func GenerateHTMLReport(report *analyzer.Report, outputPath string) error {
 // TemplateData holds the data for the HTML template
 type TemplateData struct {
 	Summary         Summary
+	TopOffenders    []analyzer.Offender // Ranked worst spots, shown before everything else
+	Hotspots        []analyzer.Hotspot  // Functions ranked by complexity × churn (only with churn enabled)
 	Diagnostics     []analyzer.DiagnosticResult
+	Locations       map[string]sourceLocation // Declaration of each struct and function, keyed by RelatedPath
+	Navigation      []navPackage              // Sidebar entries, one per package
 	PackageResults  []analyzer.PackageResult
 	StructResults   []StructWithPackage
 	FunctionResults []FunctionWithPackage
@@ -96,12 +117,103 @@ type Summary struct {
 	TotalPackages        int
 	TotalStructs         int
 	TotalFunctions       int
-	TotalLoC             int // Total lines of code
-	HighLCOM4Count       int // LCOM4 > 2
-	HighComplexityCount  int // Complexity > 15
-	HighInstabilityCount int // Instability > 0.7
-	CriticalIssues       int // Critical diagnostics
-	WarningIssues        int // Warning diagnostics
+	TotalLoC             int     // Total lines of code
+	LoCMode              string  // How TotalLoC is counted: physical, source, or statements
+	HealthScore          float64 // Project health score (0-100)
+	HighLCOM4Count       int     // LCOM4 > 2
+	HighComplexityCount  int     // Complexity > 15
+	HighInstabilityCount int     // Instability > 0.7
+	CriticalIssues       int     // Critical diagnostics
+	WarningIssues        int     // Warning diagnostics
+	InfoIssues           int     // Info diagnostics
+}
+
+// sourceLocation is the position of a struct or function declaration
+type sourceLocation struct {
+	FilePath string
+	Line     int
+	EndLine  int
+}
+
+// String formats the location as "file:line"
+func (l sourceLocation) String() string {
+	return fmt.Sprintf("%s:%d", l.FilePath, l.Line)
+}
+
+// relatedLocations maps the RelatedPath anchors of struct and function diagnostics
+// ("#struct-<pkg>-<Struct>", "#function-<pkg>-<Func>") to the declaration they refer to
+func relatedLocations(report *analyzer.Report) map[string]sourceLocation {
+	locations := make(map[string]sourceLocation)
+	for _, pkg := range report.Packages {
+		for _, s := range pkg.Structs {
+			locations["#"+structAnchor(pkg.Path, s.StructName)] = sourceLocation{
+				FilePath: s.FilePath,
+				Line:     s.Line,
+				EndLine:  s.EndLine,
+			}
+		}
+		for _, f := range pkg.Functions {
+			locations["#"+functionAnchor(pkg.Path, f.FuncName)] = sourceLocation{
+				FilePath: f.FilePath,
+				Line:     f.Line,
+				EndLine:  f.EndLine,
+			}
+		}
+	}
+	return locations
+}
+
+// packageAnchor, structAnchor and functionAnchor return the element ids of package, struct, and
+// function rows. They are the RelatedPath of the analyzer without the leading "#", so a diagnostic's
+// RelatedPath links to its row; the template escapes them for the attribute they are written to.
+func packageAnchor(pkgPath string) string {
+	return "package-" + pkgPath
+}
+
+func structAnchor(pkgPath, name string) string {
+	return fmt.Sprintf("struct-%s-%s", pkgPath, name)
+}
+
+func functionAnchor(pkgPath, name string) string {
+	return fmt.Sprintf("function-%s-%s", pkgPath, name)
+}
+
+// navPackage is a package of the sidebar, with the structs and functions listed under it
+type navPackage struct {
+	Name      string
+	Path      string
+	Anchor    string
+	Structs   []navEntry
+	Functions []navEntry
+}
+
+// navEntry is a sidebar link to a struct or function row
+type navEntry struct {
+	Name   string
+	Anchor string
+}
+
+// navigation lists the packages in the order of the coupling table, each with its structs and
+// functions sorted by name
+func navigation(packages []analyzer.PackageResult) []navPackage {
+	nav := make([]navPackage, 0, len(packages))
+	for _, pkg := range packages {
+		entry := navPackage{
+			Name:   pkg.Name,
+			Path:   pkg.Path,
+			Anchor: packageAnchor(pkg.Path),
+		}
+		for _, s := range pkg.Structs {
+			entry.Structs = append(entry.Structs, navEntry{Name: s.StructName, Anchor: structAnchor(pkg.Path, s.StructName)})
+		}
+		for _, f := range pkg.Functions {
+			entry.Functions = append(entry.Functions, navEntry{Name: f.FuncName, Anchor: functionAnchor(pkg.Path, f.FuncName)})
+		}
+		sort.Slice(entry.Structs, func(i, j int) bool { return entry.Structs[i].Name < entry.Structs[j].Name })
+		sort.Slice(entry.Functions, func(i, j int) bool { return entry.Functions[i].Name < entry.Functions[j].Name })
+		nav = append(nav, entry)
+	}
+	return nav
 }
 
 // StructWithPackage adds package information to struct results
@@ -167,6 +279,8 @@ func prepareTemplateData(report *analyzer.Report) TemplateData {
 		TotalStructs:   len(structs),
 		TotalFunctions: len(functions),
 		TotalLoC:       report.TotalLoC,
+		LoCMode:        report.LoCMode,
+		HealthScore:    report.HealthScore,
 	}
 
 	for _, s := range structs {
@@ -193,11 +307,17 @@ func prepareTemplateData(report *analyzer.Report) TemplateData {
 			summary.CriticalIssues++
 		} else if d.Severity == "Warning" {
 			summary.WarningIssues++
+		} else if d.Severity == "Info" {
+			summary.InfoIssues++
 		}
 	}
 
 	data.Summary = summary
+	data.TopOffenders = report.TopOffenders
+	data.Hotspots = report.Hotspots
 	data.Diagnostics = report.Diagnostics
+	data.Locations = relatedLocations(report)
+	data.Navigation = navigation(packages)
 	data.PackageResults = packages
 	data.StructResults = structs
 	data.FunctionResults = functions
@@ -0,0 +1,187 @@
+package reporter
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/hiroki-yamauchi/go-code-health-analyzer/analyzer"
+)
+
+// sampleReport is a small report with one package, struct, and function
+func sampleReport() *analyzer.Report {
+	return &analyzer.Report{
+		TargetPath: "/src/app",
+		Packages: []analyzer.PackageResult{{
+			Name: "app",
+			Path: "app",
+			Structs: []analyzer.StructResult{
+				{StructName: "Store", FilePath: "app/store.go", LCOM4Score: 2},
+			},
+			Functions: []analyzer.FunctionResult{
+				{FuncName: "Run", FilePath: "app/run.go", Complexity: 12, LoC: 30},
+			},
+		}},
+	}
+}
+
+// renderHTML renders a report with WriteHTMLReport
+func renderHTML(t *testing.T, report *analyzer.Report) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(report, &buf); err != nil {
+		t.Fatalf("failed to render HTML: %v", err)
+	}
+	return buf.String()
+}
+
+func TestHTMLComplexityColumnIsSortable(t *testing.T) {
+	html := renderHTML(t, sampleReport())
+
+	for _, want := range []string{
+		`<th data-sort="number" onclick="sortTable('complexity-table', 3)">Complexity`,
+		`<td class="font-semibold" data-sort-value="12">12</td>`,
+		`class="table-filter border border-gray-300 rounded px-3 py-2 ml-4" data-table="complexity-table"`,
+		`function sortTable(`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report is missing %s", want)
+		}
+	}
+}
+
+func TestHTMLThemePalettesAndToggle(t *testing.T) {
+	html := renderHTML(t, sampleReport())
+
+	for _, want := range []string{
+		`:root, [data-theme="light"] {`,
+		`[data-theme="dark"] {`,
+		`.red { background-color: var(--bad-bg); }`,
+		`id="theme-toggle"`,
+		`onclick="toggleTheme()"`,
+		`localStorage.setItem('code-health-theme', theme)`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report is missing %s", want)
+		}
+	}
+}
+
+func TestHTMLPackageComplexityAggregates(t *testing.T) {
+	report := sampleReport()
+	report.Packages[0].FuncCount = 3
+	report.Packages[0].AvgComplexity = 6.5
+	report.Packages[0].MaxComplexity = 16
+	report.Packages[0].TotalComplexity = 20
+	html := renderHTML(t, report)
+
+	for _, want := range []string{
+		`<td class="yellow">6.5</td>`,
+		`<td class="red">16</td>`,
+		`<td>20</td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML package table is missing %s", want)
+		}
+	}
+}
+
+func TestSeverityOverrideRaisesCriticalIssues(t *testing.T) {
+	// 1 + switch + 14 cases: complexity 16, an "Overly Complex Function" Warning by default
+	const complexSource = `package app
+
+func Classify(n int) int {
+	switch n {
+	case 0:
+		return 0
+	case 1:
+		return 1
+	case 2:
+		return 2
+	case 3:
+		return 3
+	case 4:
+		return 4
+	case 5:
+		return 5
+	case 6:
+		return 6
+	case 7:
+		return 7
+	case 8:
+		return 8
+	case 9:
+		return 9
+	case 10:
+		return 10
+	case 11:
+		return 11
+	case 12:
+		return 12
+	case 13:
+		return 13
+	}
+	return -1
+}
+`
+
+	before := prepareTemplateData(analyzeProject(t, map[string]string{
+		"app/app.go": complexSource,
+	})).Summary
+	after := prepareTemplateData(analyzeProject(t, map[string]string{
+		"app/app.go":       complexSource,
+		".codehealth.json": `{"severity_overrides": {"Overly Complex Function": "Critical"}}`,
+	})).Summary
+
+	if after.CriticalIssues != before.CriticalIssues+1 || after.WarningIssues != before.WarningIssues-1 {
+		t.Errorf("critical %d -> %d, warning %d -> %d, want one diagnostic moved from Warning to Critical",
+			before.CriticalIssues, after.CriticalIssues, before.WarningIssues, after.WarningIssues)
+	}
+}
+
+func TestHTMLRelatedPathAnchorsExist(t *testing.T) {
+	report := analyzeProject(t, map[string]string{
+		// Every function is reported as complex, unused structs are reported, and a cycle
+		// reports the packages
+		".codehealth.json": `{"complex_function_threshold": 1}`,
+		"main.go":          "package main\n\nimport \"example.com/app/a/b\"\n\nfunc main() { b.Run() }\n",
+		"a/b/b.go": `package b
+
+import "example.com/app/c"
+
+type Store struct{ n int }
+
+func (s *Store) Get() int { return s.n }
+
+func Run() { c.C() }
+`,
+		"c/c.go": "package c\n\nimport \"example.com/app/a/b\"\n\nfunc C() { b.Run() }\n",
+	})
+
+	kinds := make(map[string]bool)
+	for _, d := range report.Diagnostics {
+		if d.RelatedPath != "" {
+			kinds[strings.SplitN(strings.TrimPrefix(d.RelatedPath, "#"), "-", 2)[0]] = true
+		}
+	}
+	if !kinds["package"] || !kinds["struct"] || !kinds["function"] {
+		t.Fatalf("fixture diagnostics link to %v, want package, struct, and function rows", kinds)
+	}
+
+	html := renderHTML(t, report)
+	for _, d := range report.Diagnostics {
+		if d.RelatedPath == "" {
+			continue
+		}
+		id := template.HTMLEscapeString(strings.TrimPrefix(d.RelatedPath, "#"))
+		if !strings.Contains(html, `id="`+id+`"`) {
+			t.Errorf("%s diagnostic for %s links to %s, but no element has that id", d.Type, d.TargetName, d.RelatedPath)
+		}
+	}
+
+	// The sidebar links to the rows too
+	if !strings.Contains(html, `href="#struct-a/b-Store"`) || !strings.Contains(html, `href="#function--main"`) {
+		t.Errorf("sidebar is missing links to the struct and function rows")
+	}
+}